@@ -27,7 +27,11 @@ func (a *AccountLock) handleGetAccountLockProfile(w http.ResponseWriter, req *ht
 	if err != nil {
 		return err
 	}
-	profileList := convertProfileList(list)
+	schedules, epoch, err := accountlock.GetLatestScheduleList()
+	if err != nil {
+		return err
+	}
+	profileList := convertProfileList(list, schedules, epoch)
 	return utils.WriteJSON(w, profileList)
 }
 
@@ -36,17 +40,25 @@ func (a *AccountLock) handleGetProfileByID(w http.ResponseWriter, req *http.Requ
 	if err != nil {
 		return err
 	}
+	schedules, epoch, err := accountlock.GetLatestScheduleList()
+	if err != nil {
+		return err
+	}
 	id := mux.Vars(req)["address"]
 	bytes, err := meter.ParseAddress(id)
 	if err != nil {
 		return utils.BadRequest(errors.WithMessage(err, "address"))
 	}
 	s := list.Get(bytes)
-	profile := convertProfile(s)
+	if s == nil {
+		return utils.WriteJSON(w, nil)
+	}
+	profile := convertProfile(s, schedules.Get(bytes), epoch)
 	return utils.WriteJSON(w, profile)
 }
 
 func (a *AccountLock) Mount(root *mux.Router, pathPrefix string) {
 	sub := root.PathPrefix(pathPrefix).Subrouter()
 	sub.Path("/profiles").Methods("Get").HandlerFunc(utils.WrapHandlerFunc(a.handleGetAccountLockProfile))
+	sub.Path("/profiles/{address}").Methods("Get").HandlerFunc(utils.WrapHandlerFunc(a.handleGetProfileByID))
 }
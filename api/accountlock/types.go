@@ -6,6 +6,7 @@
 package accountlock
 
 import (
+	"math/big"
 	"sort"
 
 	"github.com/dfinlab/meter/meter"
@@ -19,12 +20,26 @@ type AccountLockProfile struct {
 	ReleaseEpoch   uint32        `json:"releaseEpoch"`
 	MeterAmount    string        `json:"meter"`
 	MeterGovAmount string        `json:"meterGov"`
+	VestedMeter    string        `json:"vestedMeter"`
+	VestedMeterGov string        `json:"vestedMeterGov"`
+	LockedMeter    string        `json:"unvestedMeter"`
+	LockedMeterGov string        `json:"unvestedMeterGov"`
+	Tranches       []*Tranche    `json:"tranches"`
 }
 
-func convertProfileList(list *accountlock.ProfileList) []*AccountLockProfile {
+type Tranche struct {
+	StartEpoch     uint32 `json:"startEpoch"`
+	CliffEpoch     uint32 `json:"cliffEpoch"`
+	EndEpoch       uint32 `json:"endEpoch"`
+	MeterAmount    string `json:"meter"`
+	MeterGovAmount string `json:"meterGov"`
+}
+
+func convertProfileList(list *accountlock.ProfileList, schedules *accountlock.ScheduleList, epoch uint32) []*AccountLockProfile {
 	profileList := make([]*AccountLockProfile, 0)
 	for _, s := range list.ToList() {
-		profileList = append(profileList, convertProfile(&s))
+		p := s
+		profileList = append(profileList, convertProfile(&p, schedules.Get(p.Addr), epoch))
 	}
 
 	// sort with descendent total points
@@ -34,7 +49,20 @@ func convertProfileList(list *accountlock.ProfileList) []*AccountLockProfile {
 	return profileList
 }
 
-func convertProfile(a *accountlock.Profile) *AccountLockProfile {
+func convertProfile(a *accountlock.Profile, s *accountlock.Schedule, epoch uint32) *AccountLockProfile {
+	lockedMtr, lockedMtrg := accountlock.Unvested(a, s, epoch)
+	tranches := make([]*Tranche, 0)
+	if s != nil {
+		for _, t := range s.Tranches {
+			tranches = append(tranches, &Tranche{
+				StartEpoch:     t.StartEpoch,
+				CliffEpoch:     t.CliffEpoch,
+				EndEpoch:       t.EndEpoch,
+				MeterAmount:    t.MeterAmount.String(),
+				MeterGovAmount: t.MeterGovAmount.String(),
+			})
+		}
+	}
 	return &AccountLockProfile{
 		Addr:           a.Addr,
 		Memo:           string(a.Memo),
@@ -42,5 +70,10 @@ func convertProfile(a *accountlock.Profile) *AccountLockProfile {
 		ReleaseEpoch:   a.ReleaseEpoch,
 		MeterAmount:    a.MeterAmount.String(),
 		MeterGovAmount: a.MeterGovAmount.String(),
+		VestedMeter:    new(big.Int).Sub(a.MeterAmount, lockedMtr).String(),
+		VestedMeterGov: new(big.Int).Sub(a.MeterGovAmount, lockedMtrg).String(),
+		LockedMeter:    lockedMtr.String(),
+		LockedMeterGov: lockedMtrg.String(),
+		Tranches:       tranches,
 	}
 }
@@ -47,18 +47,22 @@ type CallResult struct {
 	GasUsed   uint64                   `json:"gasUsed"`
 	Reverted  bool                     `json:"reverted"`
 	VMError   string                   `json:"vmError"`
+
+	ScriptError *transactions.ScriptError `json:"scriptError,omitempty"`
 }
 
 func convertCallResultWithInputGas(vo *runtime.Output, inputGas uint64) *CallResult {
 	gasUsed := inputGas - vo.LeftOverGas
 	var (
-		vmError  string
-		reverted bool
+		vmError     string
+		reverted    bool
+		scriptError *transactions.ScriptError
 	)
 
 	if vo.VMErr != nil {
 		reverted = true
 		vmError = vo.VMErr.Error()
+		scriptError = transactions.ConvertScriptError(vo.Data)
 	}
 
 	events := make([]*transactions.Event, len(vo.Events))
@@ -91,6 +95,8 @@ func convertCallResultWithInputGas(vo *runtime.Output, inputGas uint64) *CallRes
 		GasUsed:   gasUsed,
 		Reverted:  reverted,
 		VMError:   vmError,
+
+		ScriptError: scriptError,
 	}
 }
 
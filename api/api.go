@@ -43,6 +43,8 @@ func New(chain *chain.Chain, stateCreator *state.Creator, txPool *txpool.TxPool,
 	}
 
 	router := mux.NewRouter()
+	router.Use(metricsMiddleware)
+	router.Use(featuresMiddleware)
 
 	// to serve api doc and swagger-ui
 	router.PathPrefix("/doc").Handler(
@@ -74,10 +76,10 @@ func New(chain *chain.Chain, stateCreator *state.Creator, txPool *txpool.TxPool,
 		Mount(router, "/logs/transfer")
 	blocks.New(chain).
 		Mount(router, "/blocks")
-	transactions.New(chain, txPool).
+	dbg := debug.New(chain, stateCreator, callGasLimit)
+	transactions.New(chain, txPool, dbg.RevertData).
 		Mount(router, "/transactions")
-	debug.New(chain, stateCreator).
-		Mount(router, "/debug")
+	dbg.Mount(router, "/debug")
 	node.New(nw, pubKey).
 		Mount(router, "/node")
 	peers.New(p2pServer).Mount(router, "/peers")
@@ -6,23 +6,72 @@
 package auction
 
 import (
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/dfinlab/meter/api/utils"
+	"github.com/dfinlab/meter/consensus"
 	"github.com/dfinlab/meter/meter"
 	"github.com/dfinlab/meter/script/auction"
 	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
 )
 
 type Auction struct {
+	preview *consensus.PreviewCache
 }
 
 func New() *Auction {
-	return &Auction{}
+	return &Auction{
+		consensus.NewPreviewCache(true, func(p *consensus.KBlockPreview) interface{} {
+			return convertAuctionPreview(p)
+		}),
+	}
+}
+
+const maxSummaryLimit = 512
+
+// parseUint returns def if the query param is absent.
+func parseUint(req *http.Request, name string, def uint64) (uint64, error) {
+	v := req.URL.Query().Get(name)
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		return 0, utils.BadRequest(errors.WithMessage(err, name))
+	}
+	return n, nil
 }
 
+// handleGetAuctionSummary returns the summaries of auctions ending in epoch [from, to],
+// paginated by offset and limit in the order of auctions.
 func (at *Auction) handleGetAuctionSummary(w http.ResponseWriter, req *http.Request) error {
-	list, err := auction.GetAuctionSummaryList()
+	from, err := parseUint(req, "from", 0)
+	if err != nil {
+		return err
+	}
+	to, err := parseUint(req, "to", math.MaxUint64)
+	if err != nil {
+		return err
+	}
+	offset, err := parseUint(req, "offset", 0)
+	if err != nil {
+		return err
+	}
+	limit, err := parseUint(req, "limit", maxSummaryLimit)
+	if err != nil {
+		return err
+	}
+	if limit == 0 || limit > maxSummaryLimit {
+		return utils.BadRequest(errors.New("limit: out of range (1-512)"))
+	}
+	if from > to {
+		return utils.BadRequest(errors.New("from: greater than to"))
+	}
+
+	list, err := auction.GetAuctionSummaries(from, to, offset, limit)
 	if err != nil {
 		return err
 	}
@@ -31,16 +80,18 @@ func (at *Auction) handleGetAuctionSummary(w http.ResponseWriter, req *http.Requ
 }
 
 func (at *Auction) handleGetSummaryByID(w http.ResponseWriter, req *http.Request) error {
-	list, err := auction.GetAuctionSummaryList()
+	id := mux.Vars(req)["id"]
+	bytes, err := meter.ParseBytes32(id)
 	if err != nil {
-		return err
+		return utils.BadRequest(errors.WithMessage(err, "id"))
 	}
-	id := mux.Vars(req)["auctionID"]
-	bytes, err := meter.ParseBytes32(id)
+	s, err := auction.GetAuctionSummaryByID(bytes)
 	if err != nil {
 		return err
 	}
-	s := list.Get(bytes)
+	if s == nil {
+		return utils.WriteJSON(w, nil)
+	}
 	summary := convertSummary(s)
 	return utils.WriteJSON(w, summary)
 }
@@ -55,10 +106,38 @@ func (at *Auction) handleGetAuctionCB(w http.ResponseWriter, req *http.Request)
 	return utils.WriteJSON(w, acb)
 }
 
+func (at *Auction) handleGetBids(w http.ResponseWriter, req *http.Request) error {
+	addr, err := meter.ParseAddress(req.URL.Query().Get("address"))
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "address"))
+	}
+	list, err := auction.GetAuctionBidList(addr)
+	if err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, convertBidList(list))
+}
+
+// handleGetPreview projects the auction actions of the next kblock on a copy
+// of best state. The active auction is closed even if it's not due, to project
+// its clearing price.
+func (at *Auction) handleGetPreview(w http.ResponseWriter, req *http.Request) error {
+	consensusInst := consensus.GetConsensusGlobInst()
+	if consensusInst == nil {
+		return errors.New("consensus is not initialized...")
+	}
+	res, err := at.preview.Get(consensusInst)
+	if err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, res)
+}
+
 func (at *Auction) Mount(root *mux.Router, pathPrefix string) {
 	sub := root.PathPrefix(pathPrefix).Subrouter()
 	sub.Path("/summaries").Methods("Get").HandlerFunc(utils.WrapHandlerFunc(at.handleGetAuctionSummary))
 	sub.Path("/summaries/{id}").Methods("Get").HandlerFunc(utils.WrapHandlerFunc(at.handleGetSummaryByID))
 	sub.Path("/present").Methods("Get").HandlerFunc(utils.WrapHandlerFunc(at.handleGetAuctionCB))
-	//sub.Path("/auctioncb/{address}").Methods("Get").HandlerFunc(utils.WrapHandlerFunc(st.handleGetAuctionTxByAddress))
+	sub.Path("/bids").Methods("Get").HandlerFunc(utils.WrapHandlerFunc(at.handleGetBids))
+	sub.Path("/preview").Methods("Get").HandlerFunc(utils.WrapHandlerFunc(at.handleGetPreview))
 }
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/dfinlab/meter/consensus"
 	"github.com/dfinlab/meter/script/auction"
 )
 
@@ -59,6 +60,16 @@ type AuctionTx struct {
 	Timestamp string `json:"timestamp"`
 }
 
+type AuctionBid struct {
+	AuctionID string `json:"auctionID"`
+	Addr      string `json:"addr"`
+	Amount    string `json:"amount"`
+	Nonce     uint64 `json:"nonce"`
+	Time      uint64 `json:"time"`
+	Timestamp string `json:"timestamp"`
+	Cancelled bool   `json:"cancelled"`
+}
+
 func convertSummaryList(list *auction.AuctionSummaryList) []*AuctionSummary {
 	summaryList := make([]*AuctionSummary, 0)
 	for _, s := range list.ToList() {
@@ -129,3 +140,43 @@ func convertAuctionCB(cb *auction.AuctionCB) *AuctionCB {
 		AuctionTxs:  txs,
 	}
 }
+
+func convertBidList(list *auction.AuctionBidList) []*AuctionBid {
+	bids := make([]*AuctionBid, 0)
+	for _, b := range list.ToList() {
+		bids = append(bids, &AuctionBid{
+			AuctionID: b.AuctionID.String(),
+			Addr:      b.Addr.String(),
+			Amount:    b.Amount.String(),
+			Nonce:     b.Nonce,
+			Time:      b.Timestamp,
+			Timestamp: fmt.Sprintln(time.Unix(int64(b.Timestamp), 0)),
+			Cancelled: b.Cancelled,
+		})
+	}
+	return bids
+}
+
+type AuctionPreview struct {
+	Due           bool            `json:"due"` // auction txs are included in the next kblock
+	ClearingPrice string          `json:"clearingPrice,omitempty"`
+	Closed        *AuctionSummary `json:"closed"` // the active auction when closed
+	Next          *AuctionCB      `json:"next"`   // the auction started by the kblock
+}
+
+func convertAuctionPreview(p *consensus.KBlockPreview) *AuctionPreview {
+	auc := auction.GetAuctionGlobInst()
+	preview := &AuctionPreview{Due: p.AuctionDue}
+	if p.AuctionEnd {
+		list := auc.GetLastSummaries(1, p.State)
+		if len(list.Summaries) != 0 {
+			s := list.Summaries[len(list.Summaries)-1]
+			preview.Closed = convertSummary(s)
+			preview.ClearingPrice = s.ActualPrice.String()
+		}
+	}
+	if p.AuctionDue {
+		preview.Next = convertAuctionCB(auc.GetAuctionCB(p.State))
+	}
+	return preview
+}
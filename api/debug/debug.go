@@ -14,13 +14,16 @@ import (
 
 	"github.com/ethereum/go-ethereum/crypto"
 
+	"github.com/dfinlab/meter/api/transactions"
 	"github.com/dfinlab/meter/api/utils"
+	"github.com/dfinlab/meter/block"
 	"github.com/dfinlab/meter/chain"
 	"github.com/dfinlab/meter/consensus"
 	"github.com/dfinlab/meter/meter"
 	"github.com/dfinlab/meter/runtime"
 	"github.com/dfinlab/meter/state"
 	"github.com/dfinlab/meter/tracers"
+	"github.com/dfinlab/meter/tracers/native"
 	"github.com/dfinlab/meter/trie"
 	"github.com/dfinlab/meter/types"
 	"github.com/dfinlab/meter/vm"
@@ -31,21 +34,37 @@ import (
 )
 
 type Debug struct {
-	chain  *chain.Chain
-	stateC *state.Creator
+	chain        *chain.Chain
+	stateC       *state.Creator
+	callGasLimit uint64
 }
 
 var (
 	Magic = [4]byte{0x00, 0x00, 0x00, 0x00}
 )
 
-func New(chain *chain.Chain, stateC *state.Creator) *Debug {
+func New(chain *chain.Chain, stateC *state.Creator, callGasLimit uint64) *Debug {
 	return &Debug{
 		chain,
 		stateC,
+		callGasLimit,
 	}
 }
 
+// newRuntimeForReplay creates a runtime to replay the transactions of the given block.
+func (d *Debug) newRuntimeForReplay(blk *block.Block) (*runtime.Runtime, error) {
+	// XXX TODO: make sure this won't change anything
+	// The reason why we have these lines is interface change of NewConsensusReactor( with private and public key added)
+	privKey, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, utils.Forbidden(errors.New("can not generate private/public key"))
+	}
+
+	blsCommon := consensus.NewBlsCommon()
+
+	return consensus.NewConsensusReactor(nil, d.chain, d.stateC, privKey, &privKey.PublicKey, Magic, blsCommon, make([]*types.Delegate /* FIXME: this is an empty input */, 0)).NewRuntimeForReplay(blk.Header())
+}
+
 func (d *Debug) handleTxEnv(ctx context.Context, blockID meter.Bytes32, txIndex uint64, clauseIndex uint64) (*runtime.Runtime, *runtime.TransactionExecutor, error) {
 	block, err := d.chain.GetBlock(blockID)
 	if err != nil {
@@ -62,16 +81,7 @@ func (d *Debug) handleTxEnv(ctx context.Context, blockID meter.Bytes32, txIndex
 		return nil, nil, utils.Forbidden(errors.New("clause index out of range"))
 	}
 
-	// XXX TODO: make sure this won't change anything
-	// The reason why we have these lines is interface change of NewConsensusReactor( with private and public key added)
-	privKey, err := crypto.GenerateKey()
-	if err != nil {
-		return nil, nil, utils.Forbidden(errors.New("can not generate private/public key"))
-	}
-
-	blsCommon := consensus.NewBlsCommon()
-
-	rt, err := consensus.NewConsensusReactor(nil, d.chain, d.stateC, privKey, &privKey.PublicKey, Magic, blsCommon, make([]*types.Delegate /* FIXME: this is an empty input */, 0)).NewRuntimeForReplay(block.Header())
+	rt, err := d.newRuntimeForReplay(block)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -111,11 +121,24 @@ func (d *Debug) traceTransaction(ctx context.Context, tracer vm.Tracer, blockID
 	if err != nil {
 		return nil, err
 	}
+	if pt, ok := tracer.(*native.PrestateTracer); ok {
+		// the value of the clause is moved back to the origin by token
+		block, err := d.chain.GetBlock(blockID)
+		if err != nil {
+			return nil, err
+		}
+		pt.SetToken(block.Transactions()[txIndex].Clauses()[clauseIndex].Token())
+	}
 	rt.SetVMConfig(vm.Config{Debug: true, Tracer: tracer})
-	gasUsed, output, err := txExec.NextClause()
+	gasUsed, output, err := execNextClause(ctx, txExec)
 	if err != nil {
 		return nil, err
 	}
+	return tracerResult(tracer, gasUsed, output)
+}
+
+// tracerResult returns the result of the tracer after a clause is executed.
+func tracerResult(tracer vm.Tracer, gasUsed uint64, output *runtime.Output) (interface{}, error) {
 	switch tr := tracer.(type) {
 	case *vm.StructLogger:
 		return &ExecutionResult{
@@ -126,11 +149,32 @@ func (d *Debug) traceTransaction(ctx context.Context, tracer vm.Tracer, blockID
 		}, nil
 	case *tracers.Tracer:
 		return tr.GetResult()
+	case native.Tracer:
+		return tr.GetResult()
 	default:
 		return nil, fmt.Errorf("bad tracer type %T", tracer)
 	}
 }
 
+// newTracer creates the tracer with the given name, native tracers are
+// preferred to the js ones. Empty name stands for the struct logger.
+func newTracer(name string) (vm.Tracer, error) {
+	if name == "" {
+		return vm.NewStructLogger(nil), nil
+	}
+	if !strings.HasSuffix(name, "Tracer") {
+		name += "Tracer"
+	}
+	if tr, ok := native.New(name); ok {
+		return tr, nil
+	}
+	code, ok := tracers.CodeByName(name)
+	if !ok {
+		return nil, utils.BadRequest(errors.New("name: unsupported tracer"))
+	}
+	return tracers.New(code)
+}
+
 func (d *Debug) handleTraceTransaction(w http.ResponseWriter, req *http.Request) error {
 	var opt *TracerOption
 	if err := utils.ParseJSON(req.Body, &opt); err != nil {
@@ -139,29 +183,72 @@ func (d *Debug) handleTraceTransaction(w http.ResponseWriter, req *http.Request)
 	if opt == nil {
 		return utils.BadRequest(errors.New("body: empty body"))
 	}
-	var tracer vm.Tracer
-	if opt.Name == "" {
-		tracer = vm.NewStructLogger(nil)
-	} else {
-		name := opt.Name
-		if !strings.HasSuffix(name, "Tracer") {
-			name += "Tracer"
-		}
-		code, ok := tracers.CodeByName(name)
-		if !ok {
-			return utils.BadRequest(errors.New("name: unsupported tracer"))
-		}
-		tr, err := tracers.New(code)
+	tracer, err := newTracer(opt.Name)
+	if err != nil {
+		return err
+	}
+	blockID, txIndex, clauseIndex, err := d.parseTarget(opt.Target)
+	if err != nil {
+		return err
+	}
+	res, err := d.traceTransaction(req.Context(), tracer, blockID, txIndex, clauseIndex)
+	if err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, res)
+}
+
+// RevertData replays the tx at txIndex of the block, and returns the output data of
+// its reverted clause, nil if no clause reverted.
+func (d *Debug) RevertData(ctx context.Context, blockID meter.Bytes32, txIndex uint64) ([]byte, error) {
+	_, txExec, err := d.handleTxEnv(ctx, blockID, txIndex, 0)
+	if err != nil {
+		return nil, err
+	}
+	for txExec.HasNextClause() {
+		_, output, err := execNextClause(ctx, txExec)
 		if err != nil {
-			return err
+			return nil, err
+		}
+		if output.VMErr != nil {
+			return output.Data, nil
 		}
-		tracer = tr
+	}
+	return nil, nil
+}
+
+// scriptError replays the clause, and decodes the revert data of the script engine.
+func (d *Debug) scriptError(ctx context.Context, blockID meter.Bytes32, txIndex uint64, clauseIndex uint64) (*ScriptErrorResult, error) {
+	_, txExec, err := d.handleTxEnv(ctx, blockID, txIndex, clauseIndex)
+	if err != nil {
+		return nil, err
+	}
+	_, output, err := txExec.NextClause()
+	if err != nil {
+		return nil, err
+	}
+	var res ScriptErrorResult
+	if output.VMErr != nil {
+		res.Reverted = true
+		res.VMError = output.VMErr.Error()
+		res.ScriptError = transactions.ConvertScriptError(output.Data)
+	}
+	return &res, nil
+}
+
+func (d *Debug) handleScriptError(w http.ResponseWriter, req *http.Request) error {
+	var opt *ScriptErrorOption
+	if err := utils.ParseJSON(req.Body, &opt); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+	if opt == nil {
+		return utils.BadRequest(errors.New("body: empty body"))
 	}
 	blockID, txIndex, clauseIndex, err := d.parseTarget(opt.Target)
 	if err != nil {
 		return err
 	}
-	res, err := d.traceTransaction(req.Context(), tracer, blockID, txIndex, clauseIndex)
+	res, err := d.scriptError(req.Context(), blockID, txIndex, clauseIndex)
 	if err != nil {
 		return err
 	}
@@ -270,6 +357,9 @@ func (d *Debug) Mount(root *mux.Router, pathPrefix string) {
 	sub := root.PathPrefix(pathPrefix).Subrouter()
 
 	sub.Path("/tracers").Methods(http.MethodPost).HandlerFunc(utils.WrapHandlerFunc(d.handleTraceTransaction))
+	sub.Path("/tracers/block/{revision}").Methods(http.MethodPost).HandlerFunc(utils.WrapHandlerFunc(d.handleTraceBlock))
+	sub.Path("/tracers/call").Methods(http.MethodPost).HandlerFunc(utils.WrapHandlerFunc(d.handleTraceCall))
 	sub.Path("/storage-range").Methods(http.MethodPost).HandlerFunc(utils.WrapHandlerFunc(d.handleDebugStorage))
+	sub.Path("/script-error").Methods(http.MethodPost).HandlerFunc(utils.WrapHandlerFunc(d.handleScriptError))
 
 }
@@ -0,0 +1,138 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package debug_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dfinlab/meter/api/accounts"
+	"github.com/dfinlab/meter/api/debug"
+	"github.com/dfinlab/meter/chain"
+	"github.com/dfinlab/meter/genesis"
+	"github.com/dfinlab/meter/lvldb"
+	"github.com/dfinlab/meter/meter"
+	"github.com/dfinlab/meter/state"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+var ts *httptest.Server
+
+var to = meter.BytesToAddress([]byte("to"))
+
+func TestDebug(t *testing.T) {
+	initDebugServer(t)
+	defer ts.Close()
+	traceCall(t)
+	traceBlockBadOption(t)
+	scriptErrorBadOption(t)
+}
+
+func initDebugServer(t *testing.T) {
+	db, _ := lvldb.NewMem()
+	stateC := state.NewCreator(db)
+	gene := genesis.NewDevnet()
+
+	b, _, err := gene.Build(stateC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, _ := chain.New(db, b, false)
+
+	router := mux.NewRouter()
+	debug.New(chain, stateC, math.MaxUint64).Mount(router, "/debug")
+	ts = httptest.NewServer(router)
+}
+
+func traceCall(t *testing.T) {
+	caller := genesis.DevAccounts()[0].Address
+	opt := &debug.TraceCallOption{
+		BatchCallData: accounts.BatchCallData{
+			Clauses: accounts.Clauses{
+				accounts.Clause{
+					To:    &to,
+					Value: (*math.HexOrDecimal256)(big.NewInt(1)),
+				}},
+			Caller: &caller,
+		},
+		Name: "call",
+	}
+	res, statusCode := httpPost(t, ts.URL+"/debug/tracers/call", opt)
+	assert.Equal(t, http.StatusOK, statusCode, string(res))
+
+	var results []struct {
+		Type  string
+		From  meter.Address
+		To    meter.Address
+		Value string
+	}
+	if err := json.Unmarshal(res, &results); err != nil {
+		t.Fatal(err)
+	}
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, "CALL", results[0].Type)
+		assert.Equal(t, caller, results[0].From)
+		assert.Equal(t, to, results[0].To)
+		assert.Equal(t, "0x1", results[0].Value)
+	}
+
+	opt.Name = "unknown"
+	_, statusCode = httpPost(t, ts.URL+"/debug/tracers/call", opt)
+	assert.Equal(t, http.StatusBadRequest, statusCode, "bad tracer")
+
+	opt.Name = "call"
+	opt.Timeout = "1 minute"
+	_, statusCode = httpPost(t, ts.URL+"/debug/tracers/call", opt)
+	assert.Equal(t, http.StatusBadRequest, statusCode, "bad timeout")
+
+	opt.Timeout = "1h"
+	_, statusCode = httpPost(t, ts.URL+"/debug/tracers/call", opt)
+	assert.Equal(t, http.StatusForbidden, statusCode, "timeout exceeds limit")
+
+	opt.Timeout = ""
+	_, statusCode = httpPost(t, ts.URL+"/debug/tracers/call?revision=4294967296", opt)
+	assert.Equal(t, http.StatusBadRequest, statusCode, "bad revision")
+}
+
+func traceBlockBadOption(t *testing.T) {
+	_, statusCode := httpPost(t, ts.URL+"/debug/tracers/block/best", &debug.TraceBlockOption{Name: "unknown"})
+	assert.Equal(t, http.StatusBadRequest, statusCode, "bad tracer")
+
+	_, statusCode = httpPost(t, ts.URL+"/debug/tracers/block/4294967296", &debug.TraceBlockOption{})
+	assert.Equal(t, http.StatusBadRequest, statusCode, "bad revision")
+}
+
+func scriptErrorBadOption(t *testing.T) {
+	_, statusCode := httpPost(t, ts.URL+"/debug/script-error", &debug.ScriptErrorOption{Target: "0x00/0"})
+	assert.Equal(t, http.StatusBadRequest, statusCode, "bad target")
+
+	_, statusCode = httpPost(t, ts.URL+"/debug/script-error", &debug.ScriptErrorOption{Target: meter.Bytes32{}.String() + "/0/0"})
+	assert.Equal(t, http.StatusForbidden, statusCode, "block not found")
+}
+
+func httpPost(t *testing.T, url string, body interface{}) ([]byte, int) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := http.Post(url, "application/x-www-form-urlencoded", bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r, res.StatusCode
+}
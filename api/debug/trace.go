@@ -0,0 +1,329 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package debug
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dfinlab/meter/api/accounts"
+	"github.com/dfinlab/meter/api/utils"
+	"github.com/dfinlab/meter/block"
+	"github.com/dfinlab/meter/meter"
+	"github.com/dfinlab/meter/runtime"
+	"github.com/dfinlab/meter/tracers/native"
+	"github.com/dfinlab/meter/tx"
+	"github.com/dfinlab/meter/vm"
+	"github.com/dfinlab/meter/xenv"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+)
+
+const (
+	// defaultTraceTimeout is the amount of time a block or call trace can run
+	// when no timeout is given.
+	defaultTraceTimeout = 5 * time.Second
+	// maxTraceTimeout is the upper bound of the given timeout.
+	maxTraceTimeout = time.Minute
+)
+
+var errTraceTimeout = errors.New("execution timeout")
+
+func parseTimeout(timeout string) (time.Duration, error) {
+	if timeout == "" {
+		return defaultTraceTimeout, nil
+	}
+	d, err := time.ParseDuration(timeout)
+	if err != nil {
+		return 0, utils.BadRequest(errors.WithMessage(err, "timeout"))
+	}
+	if d <= 0 {
+		return 0, utils.BadRequest(errors.New("timeout: must be positive"))
+	}
+	if d > maxTraceTimeout {
+		return 0, utils.Forbidden(errors.New("timeout: exceeds limit"))
+	}
+	return d, nil
+}
+
+// execNextClause executes the next clause of the tx, the evm is interrupted
+// once ctx is done.
+func execNextClause(ctx context.Context, txExec *runtime.TransactionExecutor) (uint64, *runtime.Output, error) {
+	type result struct {
+		gasUsed uint64
+		output  *runtime.Output
+		err     error
+	}
+	exec, interrupt := txExec.PrepareNext()
+	done := make(chan result, 1)
+	go func() {
+		gasUsed, output, err := exec()
+		done <- result{gasUsed, output, err}
+	}()
+	select {
+	case <-ctx.Done():
+		interrupt()
+		return 0, nil, errTraceTimeout
+	case r := <-done:
+		return r.gasUsed, r.output, r.err
+	}
+}
+
+// setClauseToken tells the tracer which token the clause value is in.
+func setClauseToken(tracer vm.Tracer, clause *tx.Clause) {
+	if pt, ok := tracer.(*native.PrestateTracer); ok {
+		pt.SetToken(clause.Token())
+	}
+}
+
+// traceBlock replays the block and traces every clause of its transactions,
+// each with a new tracer.
+func (d *Debug) traceBlock(ctx context.Context, name string, blk *block.Block) ([]*ClauseTraceResult, error) {
+	rt, err := d.newRuntimeForReplay(blk)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*ClauseTraceResult, 0)
+	for txIndex, txn := range blk.Transactions() {
+		txExec, err := rt.PrepareTransaction(txn)
+		if err != nil {
+			return nil, err
+		}
+		clauses := txn.Clauses()
+		for clauseIndex := 0; txExec.HasNextClause(); clauseIndex++ {
+			tracer, err := newTracer(name)
+			if err != nil {
+				return nil, err
+			}
+			setClauseToken(tracer, clauses[clauseIndex])
+			rt.SetVMConfig(vm.Config{Debug: true, Tracer: tracer})
+
+			gasUsed, output, err := execNextClause(ctx, txExec)
+			if err != nil {
+				return nil, err
+			}
+			res, err := tracerResult(tracer, gasUsed, output)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, &ClauseTraceResult{
+				TxID:        txn.ID(),
+				TxIndex:     uint64(txIndex),
+				ClauseIndex: uint64(clauseIndex),
+				Result:      res,
+			})
+		}
+		if _, err := txExec.Finalize(); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+func (d *Debug) handleTraceBlock(w http.ResponseWriter, req *http.Request) error {
+	var opt *TraceBlockOption
+	if err := utils.ParseJSON(req.Body, &opt); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+	if opt == nil {
+		return utils.BadRequest(errors.New("body: empty body"))
+	}
+	if _, err := newTracer(opt.Name); err != nil {
+		return err
+	}
+	timeout, err := parseTimeout(opt.Timeout)
+	if err != nil {
+		return err
+	}
+	h, err := d.handleRevision(mux.Vars(req)["revision"])
+	if err != nil {
+		return err
+	}
+	blk, err := d.chain.GetBlock(h.ID())
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	defer cancel()
+
+	results, err := d.traceBlock(ctx, opt.Name, blk)
+	if err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, results)
+}
+
+// traceCall simulates the clauses on the state of the given block like
+// /accounts/*, and traces each of them with a new tracer.
+func (d *Debug) traceCall(ctx context.Context, name string, batchCallData *accounts.BatchCallData, header *block.Header) ([]interface{}, error) {
+	gas, gasPrice, caller, clauses, err := d.handleBatchCallData(batchCallData)
+	if err != nil {
+		return nil, err
+	}
+	state, err := d.stateC.NewState(header.StateRoot())
+	if err != nil {
+		return nil, err
+	}
+	signer, _ := header.Signer()
+	rt := runtime.New(d.chain.NewSeeker(header.ParentID()), state,
+		&xenv.BlockContext{
+			Beneficiary: header.Beneficiary(),
+			Signer:      signer,
+			Number:      header.Number(),
+			Time:        header.Timestamp(),
+			GasLimit:    header.GasLimit(),
+			TotalScore:  header.TotalScore()})
+	results := make([]interface{}, 0, len(clauses))
+	vmout := make(chan *runtime.Output, 1)
+	for i, clause := range clauses {
+		tracer, err := newTracer(name)
+		if err != nil {
+			return nil, err
+		}
+		setClauseToken(tracer, clause)
+		rt.SetVMConfig(vm.Config{Debug: true, Tracer: tracer})
+
+		exec, interrupt := rt.PrepareClause(clause, uint32(i), gas, &xenv.TransactionContext{
+			Origin:     *caller,
+			GasPrice:   gasPrice,
+			ProvedWork: &big.Int{}})
+		go func() {
+			out, _ := exec()
+			vmout <- out
+		}()
+		select {
+		case <-ctx.Done():
+			interrupt()
+			return nil, errTraceTimeout
+		case out := <-vmout:
+			if err := rt.Seeker().Err(); err != nil {
+				return nil, err
+			}
+			if err := state.Err(); err != nil {
+				return nil, err
+			}
+			res, err := tracerResult(tracer, gas-out.LeftOverGas, out)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, res)
+			if out.VMErr != nil {
+				return results, nil
+			}
+			gas = out.LeftOverGas
+		}
+	}
+	return results, nil
+}
+
+func (d *Debug) handleTraceCall(w http.ResponseWriter, req *http.Request) error {
+	var opt *TraceCallOption
+	if err := utils.ParseJSON(req.Body, &opt); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+	if opt == nil {
+		return utils.BadRequest(errors.New("body: empty body"))
+	}
+	if _, err := newTracer(opt.Name); err != nil {
+		return err
+	}
+	timeout, err := parseTimeout(opt.Timeout)
+	if err != nil {
+		return err
+	}
+	h, err := d.handleRevision(req.URL.Query().Get("revision"))
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	defer cancel()
+
+	results, err := d.traceCall(ctx, opt.Name, &opt.BatchCallData, h)
+	if err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, results)
+}
+
+func (d *Debug) handleBatchCallData(batchCallData *accounts.BatchCallData) (gas uint64, gasPrice *big.Int, caller *meter.Address, clauses []*tx.Clause, err error) {
+	if batchCallData.Gas > d.callGasLimit {
+		return 0, nil, nil, nil, utils.Forbidden(errors.New("gas: exceeds limit"))
+	} else if batchCallData.Gas == 0 {
+		gas = d.callGasLimit
+	} else {
+		gas = batchCallData.Gas
+	}
+	if batchCallData.GasPrice == nil {
+		gasPrice = new(big.Int)
+	} else {
+		gasPrice = (*big.Int)(batchCallData.GasPrice)
+	}
+	if batchCallData.Caller == nil {
+		caller = &meter.Address{}
+	} else {
+		caller = batchCallData.Caller
+	}
+	clauses = make([]*tx.Clause, len(batchCallData.Clauses))
+	for i, c := range batchCallData.Clauses {
+		var value *big.Int
+		if c.Value == nil {
+			value = new(big.Int)
+		} else {
+			value = (*big.Int)(c.Value)
+		}
+		var data []byte
+		if c.Data != "" {
+			data, err = hexutil.Decode(c.Data)
+			if err != nil {
+				err = utils.BadRequest(errors.WithMessage(err, fmt.Sprintf("data[%d]", i)))
+				return
+			}
+		}
+		clauses[i] = tx.NewClause(c.To).WithData(data).WithValue(value).WithToken(c.Token)
+	}
+	return
+}
+
+func (d *Debug) handleRevision(revision string) (*block.Header, error) {
+	if revision == "" || revision == "best" {
+		return d.chain.BestBlock().Header(), nil
+	}
+	if len(revision) == 66 || len(revision) == 64 {
+		blockID, err := meter.ParseBytes32(revision)
+		if err != nil {
+			return nil, utils.BadRequest(errors.WithMessage(err, "revision"))
+		}
+		h, err := d.chain.GetBlockHeader(blockID)
+		if err != nil {
+			if d.chain.IsNotFound(err) {
+				return nil, utils.BadRequest(errors.WithMessage(err, "revision"))
+			}
+			return nil, err
+		}
+		return h, nil
+	}
+	n, err := strconv.ParseUint(revision, 0, 0)
+	if err != nil {
+		return nil, utils.BadRequest(errors.WithMessage(err, "revision"))
+	}
+	if n > math.MaxUint32 {
+		return nil, utils.BadRequest(errors.WithMessage(errors.New("block number out of max uint32"), "revision"))
+	}
+	h, err := d.chain.GetTrunkBlockHeader(uint32(n))
+	if err != nil {
+		if d.chain.IsNotFound(err) {
+			return nil, utils.BadRequest(errors.WithMessage(err, "revision"))
+		}
+		return nil, err
+	}
+	return h, nil
+}
@@ -3,6 +3,8 @@ package debug
 import (
 	"fmt"
 
+	"github.com/dfinlab/meter/api/accounts"
+	"github.com/dfinlab/meter/api/transactions"
 	"github.com/dfinlab/meter/meter"
 
 	"github.com/ethereum/go-ethereum/common/math"
@@ -14,6 +16,19 @@ type TracerOption struct {
 	Target string `json:"target"`
 }
 
+// ScriptErrorOption is the clause to look up the script engine error.
+type ScriptErrorOption struct {
+	Target string `json:"target"`
+}
+
+// ScriptErrorResult is the outcome of the replayed clause, ScriptError is nil
+// unless reverted by the script engine.
+type ScriptErrorResult struct {
+	Reverted    bool                      `json:"reverted"`
+	VMError     string                    `json:"vmError"`
+	ScriptError *transactions.ScriptError `json:"scriptError"`
+}
+
 type ExecutionResult struct {
 	Gas         uint64         `json:"gas"`
 	Failed      bool           `json:"failed"`
@@ -88,3 +103,22 @@ type StorageEntry struct {
 	Key   *meter.Bytes32 `json:"key"`
 	Value *meter.Bytes32 `json:"value"`
 }
+
+type TraceBlockOption struct {
+	Name    string `json:"name"`
+	Timeout string `json:"timeout"`
+}
+
+type TraceCallOption struct {
+	accounts.BatchCallData
+	Name    string `json:"name"`
+	Timeout string `json:"timeout"`
+}
+
+// ClauseTraceResult is the trace result of a clause in a block.
+type ClauseTraceResult struct {
+	TxID        meter.Bytes32 `json:"txID"`
+	TxIndex     uint64        `json:"txIndex"`
+	ClauseIndex uint64        `json:"clauseIndex"`
+	Result      interface{}   `json:"result"`
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package api
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// features are API modules which can be toggled at runtime, keyed by the first segment of mount path.
+var features = struct {
+	lock     sync.RWMutex
+	disabled map[string]bool
+}{disabled: make(map[string]bool)}
+
+var featureNames = []string{
+	"accountlock",
+	"accounts",
+	"auction",
+	"blocks",
+	"debug",
+	"events",
+	"logs",
+	"node",
+	"peers",
+	"slashing",
+	"staking",
+	"subscriptions",
+	"transactions",
+	"transfers",
+}
+
+// Features returns all API features and whether they are enabled.
+func Features() map[string]bool {
+	features.lock.RLock()
+	defer features.lock.RUnlock()
+	m := make(map[string]bool, len(featureNames))
+	for _, name := range featureNames {
+		m[name] = !features.disabled[name]
+	}
+	return m
+}
+
+// SetFeatureEnabled enables or disables the API feature.
+func SetFeatureEnabled(name string, enabled bool) error {
+	if i := sort.SearchStrings(featureNames, name); i >= len(featureNames) || featureNames[i] != name {
+		return errors.New("unknown feature: " + name)
+	}
+	features.lock.Lock()
+	defer features.lock.Unlock()
+	if enabled {
+		delete(features.disabled, name)
+	} else {
+		features.disabled[name] = true
+	}
+	return nil
+}
+
+func isFeatureDisabled(path string) bool {
+	name := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 2)[0]
+	features.lock.RLock()
+	defer features.lock.RUnlock()
+	return features.disabled[name]
+}
+
+// middleware to reject requests to disabled features.
+func featuresMiddleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isFeatureDisabled(r.URL.Path) {
+			http.Error(w, "feature disabled", http.StatusServiceUnavailable)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
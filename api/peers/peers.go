@@ -24,8 +24,12 @@ func New(p2pServer *p2psrv.Server) *Peers {
 }
 
 func (p *Peers) handleGetPeers(w http.ResponseWriter, req *http.Request) error {
-	nodes := p.p2pServer.GetDiscoveredNodes()
 	result := make([]*Peer, 0)
+	// p2p server is absent in solo mode
+	if p.p2pServer == nil {
+		return utils.WriteJSON(w, result)
+	}
+	nodes := p.p2pServer.GetDiscoveredNodes()
 	for _, n := range nodes {
 		peer := convertNode(n)
 		result = append(result, peer)
@@ -20,5 +20,6 @@ func convertNode(n *discover.Node) *Peer {
 	return &Peer{
 		EnodeID: n.ID.String(),
 		IP:      n.IP.String(),
+		Port:    uint32(n.TCP),
 	}
 }
@@ -0,0 +1,63 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package api
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dfinlab/meter/metric"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+)
+
+var (
+	requestDurationHist = metric.NewHistogramVec("api_request_duration_seconds", "Duration of API requests", "route", "method")
+	requestErrorCounter = metric.NewCounterVec("api_request_errors_total", "Counter of API requests responded with error", "route", "method", "code")
+)
+
+// statusRecorder records the status code written to the response.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Hijack implements http.Hijacker, which is required by the websocket subscriptions.
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+	return h.Hijack()
+}
+
+// metricsMiddleware measures latency and error count per route.
+func metricsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		route := "unknown"
+		if cur := mux.CurrentRoute(req); cur != nil {
+			if tpl, err := cur.GetPathTemplate(); err == nil {
+				route = tpl
+			}
+		}
+		start := time.Now()
+		rec := &statusRecorder{w, http.StatusOK}
+		next.ServeHTTP(rec, req)
+
+		metric.ObserveSince(requestDurationHist.WithLabelValues(route, req.Method), start)
+		if rec.status >= http.StatusBadRequest {
+			requestErrorCounter.WithLabelValues(route, req.Method, strconv.Itoa(rec.status)).Inc()
+		}
+	})
+}
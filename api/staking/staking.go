@@ -8,18 +8,26 @@ package staking
 import (
 	"encoding/hex"
 	"net/http"
+	"strconv"
 
 	"github.com/dfinlab/meter/api/utils"
+	"github.com/dfinlab/meter/consensus"
 	"github.com/dfinlab/meter/meter"
 	"github.com/dfinlab/meter/script/staking"
 	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
 )
 
 type Staking struct {
+	preview *consensus.PreviewCache
 }
 
 func New() *Staking {
-	return &Staking{}
+	return &Staking{
+		consensus.NewPreviewCache(false, func(p *consensus.KBlockPreview) interface{} {
+			return convertGoverningPreview(p)
+		}),
+	}
 }
 
 func (st *Staking) handleGetCandidateList(w http.ResponseWriter, req *http.Request) error {
@@ -27,7 +35,11 @@ func (st *Staking) handleGetCandidateList(w http.ResponseWriter, req *http.Reque
 	if err != nil {
 		return err
 	}
-	candidateList := convertCandidateList(list)
+	profiles, err := staking.GetLatestCandidateProfileList()
+	if err != nil {
+		return err
+	}
+	candidateList := convertCandidateList(list, profiles)
 	return utils.WriteJSON(w, candidateList)
 }
 
@@ -43,7 +55,11 @@ func (st *Staking) handleGetCandidateByAddress(w http.ResponseWriter, req *http.
 	}
 	meterAddr := meter.BytesToAddress(bytes)
 	c := list.Get(meterAddr)
-	candidate := convertCandidate(*c)
+	profiles, err := staking.GetLatestCandidateProfileList()
+	if err != nil {
+		return err
+	}
+	candidate := convertCandidate(*c, profiles.Get(meterAddr))
 	return utils.WriteJSON(w, candidate)
 }
 
@@ -110,6 +126,66 @@ func (st *Staking) handleGetValidatorRewardList(w http.ResponseWriter, req *http
 	return utils.WriteJSON(w, validatorRewardList)
 }
 
+func (st *Staking) handleGetEpochRewards(w http.ResponseWriter, req *http.Request) error {
+	epoch, err := strconv.ParseUint(mux.Vars(req)["epoch"], 10, 32)
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "epoch"))
+	}
+	rewards, err := staking.GetEpochRewards(uint32(epoch))
+	if err != nil {
+		return err
+	}
+	if rewards == nil {
+		return utils.WriteJSON(w, nil)
+	}
+	return utils.WriteJSON(w, convertEpochRewards(rewards))
+}
+
+func (st *Staking) handleGetAddressRewards(w http.ResponseWriter, req *http.Request) error {
+	addr, err := meter.ParseAddress(mux.Vars(req)["address"])
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "address"))
+	}
+	from, err := parseEpoch(req.URL.Query().Get("from"))
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "from"))
+	}
+	to, err := parseEpoch(req.URL.Query().Get("to"))
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "to"))
+	}
+	rewards, next, err := staking.GetAddressRewards(addr, from, to)
+	if err != nil {
+		return utils.BadRequest(err)
+	}
+	return utils.WriteJSON(w, convertAddressRewards(rewards, next))
+}
+
+// handleGetPreview projects the delegates of next epoch, by executing the
+// governing tx of the next kblock on a copy of best state.
+func (st *Staking) handleGetPreview(w http.ResponseWriter, req *http.Request) error {
+	consensusInst := consensus.GetConsensusGlobInst()
+	if consensusInst == nil {
+		return errors.New("consensus is not initialized...")
+	}
+	res, err := st.preview.Get(consensusInst)
+	if err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, res)
+}
+
+func parseEpoch(s string) (uint32, error) {
+	if s == "" {
+		return 0, nil
+	}
+	epoch, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	return uint32(epoch), nil
+}
+
 func (st *Staking) Mount(root *mux.Router, pathPrefix string) {
 	sub := root.PathPrefix(pathPrefix).Subrouter()
 	sub.Path("/candidates").Methods("Get").HandlerFunc(utils.WrapHandlerFunc(st.handleGetCandidateList))
@@ -120,4 +196,7 @@ func (st *Staking) Mount(root *mux.Router, pathPrefix string) {
 	sub.Path("/stakeholders/{address}").Methods("Get").HandlerFunc(utils.WrapHandlerFunc(st.handleGetStakeholderByAddress))
 	sub.Path("/delegates").Methods("Get").HandlerFunc(utils.WrapHandlerFunc(st.handleGetDelegateList))
 	sub.Path("/validator-rewards").Methods("Get").HandlerFunc(utils.WrapHandlerFunc(st.handleGetValidatorRewardList))
+	sub.Path("/validator-rewards/{epoch}").Methods("Get").HandlerFunc(utils.WrapHandlerFunc(st.handleGetEpochRewards))
+	sub.Path("/rewards/{address}").Methods("Get").HandlerFunc(utils.WrapHandlerFunc(st.handleGetAddressRewards))
+	sub.Path("/preview").Methods("Get").HandlerFunc(utils.WrapHandlerFunc(st.handleGetPreview))
 }
@@ -10,6 +10,7 @@ import (
 	"math/big"
 	"sort"
 
+	"github.com/dfinlab/meter/consensus"
 	"github.com/dfinlab/meter/meter"
 	"github.com/dfinlab/meter/script/staking"
 )
@@ -23,12 +24,19 @@ type Candidate struct {
 	TotalVotes string        `json:"totalVotes"` // total voting from all buckets
 	Commission uint64        `json:"commission"` // commission rate unit "1e09"
 	Buckets    []string      `json:"buckets"`    // all buckets voted for this candidate
+
+	Description       string        `json:"description"`
+	Website           string        `json:"website"`
+	Contact           string        `json:"contact"`
+	LogoHash          meter.Bytes32 `json:"logoHash"`
+	PendingCommission *uint64       `json:"pendingCommission"` // null if nothing pending
+	PendingTime       uint64        `json:"pendingTime"`       // the pending commission starts not earlier than this time
 }
 
-func convertCandidateList(list *staking.CandidateList) []*Candidate {
+func convertCandidateList(list *staking.CandidateList, profiles *staking.CandidateProfileList) []*Candidate {
 	candidateList := make([]*Candidate, 0)
 	for _, c := range list.ToList() {
-		candidateList = append(candidateList, convertCandidate(c))
+		candidateList = append(candidateList, convertCandidate(c, profiles.Get(c.Addr)))
 	}
 	sort.SliceStable(candidateList, func(i, j int) bool {
 		voteI := new(big.Int)
@@ -41,12 +49,12 @@ func convertCandidateList(list *staking.CandidateList) []*Candidate {
 	return candidateList
 }
 
-func convertCandidate(c staking.Candidate) *Candidate {
+func convertCandidate(c staking.Candidate, profile *staking.CandidateProfile) *Candidate {
 	buckets := make([]string, 0)
 	for _, b := range c.Buckets {
 		buckets = append(buckets, b.String())
 	}
-	return &Candidate{
+	candidate := &Candidate{
 		Name:    string(bytes.Trim(c.Name[:], "\x00")),
 		Address: c.Addr,
 		//PubKey:     hex.EncodeToString(c.PubKey),
@@ -57,6 +65,18 @@ func convertCandidate(c staking.Candidate) *Candidate {
 		Commission: c.Commission,
 		Buckets:    buckets,
 	}
+	if profile != nil {
+		candidate.Description = string(profile.Meta.Description)
+		candidate.Website = string(profile.Meta.Website)
+		candidate.Contact = string(profile.Meta.Contact)
+		candidate.LogoHash = profile.Meta.LogoHash
+		if profile.Pending {
+			pending := profile.PendingCommission
+			candidate.PendingCommission = &pending
+			candidate.PendingTime = profile.PendingTime
+		}
+	}
+	return candidate
 }
 
 type Bucket struct {
@@ -195,3 +215,101 @@ func convertValidatorReward(r staking.ValidatorReward) *ValidatorReward {
 		ActualDistribute: r.ActualDistribute.String(),
 	}
 }
+
+type RewardInfo struct {
+	Address meter.Address `json:"address"`
+	Amount  string        `json:"amount"`
+}
+
+type EpochRewards struct {
+	ValidatorReward
+	Rewards []*RewardInfo `json:"rewards"`
+}
+
+func convertEpochRewards(e *staking.EpochRewards) *EpochRewards {
+	rewards := make([]*RewardInfo, 0)
+	for _, r := range e.Rewards {
+		rewards = append(rewards, &RewardInfo{
+			Address: r.Address,
+			Amount:  r.Amount.String(),
+		})
+	}
+	return &EpochRewards{
+		ValidatorReward: *convertValidatorReward(*e.Reward),
+		Rewards:         rewards,
+	}
+}
+
+type AddressReward struct {
+	Epoch  uint32 `json:"epoch"`
+	Amount string `json:"amount"`
+}
+
+// AddressRewards is a page of address rewards, Next is the epoch to query
+// from for the rest of the range, nil if the range is completed.
+type AddressRewards struct {
+	Rewards []*AddressReward `json:"rewards"`
+	Next    *uint32          `json:"next"`
+}
+
+func convertAddressRewards(list []*staking.AddressReward, next uint32) *AddressRewards {
+	rewards := make([]*AddressReward, 0)
+	for _, r := range list {
+		rewards = append(rewards, &AddressReward{
+			Epoch:  r.Epoch,
+			Amount: r.Amount.String(),
+		})
+	}
+	page := &AddressRewards{Rewards: rewards}
+	if next != 0 {
+		page.Next = &next
+	}
+	return page
+}
+
+type UnboundMaturity struct {
+	BucketID    string        `json:"bucketID"`
+	Owner       meter.Address `json:"owner"`
+	Value       string        `json:"value"`
+	Token       uint8         `json:"token"`
+	ReleaseTime uint64        `json:"releaseTime"` // mature time plus the unbound grace
+	Released    bool          `json:"released"`    // released by the kblock
+}
+
+type GoverningPreview struct {
+	Epoch     uint64             `json:"epoch"` // epoch closed by the kblock
+	Delegates []*Delegate        `json:"delegates"`
+	Rewards   *EpochRewards      `json:"rewards"`
+	Unbounds  []*UnboundMaturity `json:"unbounds"`
+}
+
+func convertGoverningPreview(p *consensus.KBlockPreview) *GoverningPreview {
+	stk := staking.GetStakingGlobInst()
+	params := staking.GetStakingParams(p.Best, p.Number >= stk.ForkConfig().StakingParams)
+	after := stk.GetBucketList(p.State)
+
+	unbounds := make([]*UnboundMaturity, 0)
+	for _, b := range stk.GetBucketList(p.Best).ToList() {
+		if b.Unbounded == false {
+			continue
+		}
+		unbounds = append(unbounds, &UnboundMaturity{
+			BucketID:    b.BucketID.String(),
+			Owner:       b.Owner,
+			Value:       b.Value.String(),
+			Token:       b.Token,
+			ReleaseTime: b.MatureTime + params.UnboundGrace,
+			Released:    after.Get(b.BucketID) == nil,
+		})
+	}
+
+	preview := &GoverningPreview{
+		Epoch:     p.Epoch,
+		Delegates: convertDelegateList(stk.GetDelegateList(p.State)),
+		Unbounds:  unbounds,
+	}
+	if rewards := stk.GetEpochRewards(uint32(p.Epoch), p.State); rewards != nil {
+		preview.Rewards = convertEpochRewards(rewards)
+	}
+	return preview
+}
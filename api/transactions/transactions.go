@@ -7,6 +7,7 @@ package transactions
 
 import (
 	"bytes"
+	"context"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -17,28 +18,41 @@ import (
 	"github.com/dfinlab/meter/api/utils"
 	"github.com/dfinlab/meter/chain"
 	"github.com/dfinlab/meter/meter"
+	"github.com/dfinlab/meter/runtime"
 	"github.com/dfinlab/meter/tx"
 	"github.com/dfinlab/meter/txpool"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/gorilla/mux"
+	lru "github.com/hashicorp/golang-lru"
 	"github.com/pkg/errors"
 )
 
 const (
 	RecentTxLimit = 10
+
+	scriptErrorCacheSize = 1024
 )
 
+// RevertDataFunc replays the tx at txIndex of the block, and returns the output data
+// of its reverted clause.
+type RevertDataFunc func(ctx context.Context, blockID meter.Bytes32, txIndex uint64) ([]byte, error)
+
 type Transactions struct {
-	chain *chain.Chain
-	pool  *txpool.TxPool
+	chain        *chain.Chain
+	pool         *txpool.TxPool
+	revertData   RevertDataFunc
+	scriptErrors *lru.Cache
 }
 
-func New(chain *chain.Chain, pool *txpool.TxPool) *Transactions {
+func New(chain *chain.Chain, pool *txpool.TxPool, revertData RevertDataFunc) *Transactions {
+	scriptErrors, _ := lru.New(scriptErrorCacheSize)
 	return &Transactions{
 		chain,
 		pool,
+		revertData,
+		scriptErrors,
 	}
 }
 
@@ -92,7 +106,7 @@ func (t *Transactions) getTransactionByID(txID meter.Bytes32, blockID meter.Byte
 }
 
 //GetTransactionReceiptByID get tx's receipt
-func (t *Transactions) getTransactionReceiptByID(txID meter.Bytes32, blockID meter.Bytes32) (*Receipt, error) {
+func (t *Transactions) getTransactionReceiptByID(ctx context.Context, txID meter.Bytes32, blockID meter.Bytes32) (*Receipt, error) {
 	txMeta, err := t.chain.GetTransactionMeta(txID, blockID)
 	if err != nil {
 		if t.chain.IsNotFound(err) {
@@ -112,7 +126,44 @@ func (t *Transactions) getTransactionReceiptByID(txID meter.Bytes32, blockID met
 	if err != nil {
 		return nil, err
 	}
-	return convertReceipt(receipt, h, tx)
+	converted, err := convertReceipt(receipt, h, tx)
+	if err != nil {
+		return nil, err
+	}
+	if receipt.Reverted && hasScriptClause(tx) {
+		if converted.ScriptError, err = t.scriptError(ctx, txID, txMeta.BlockID, txMeta.Index); err != nil {
+			return nil, err
+		}
+	}
+	return converted, nil
+}
+
+func hasScriptClause(tx *tx.Transaction) bool {
+	for _, c := range tx.Clauses() {
+		if runtime.IsScriptEngineClause(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// scriptError decodes the revert data of the script engine. The revert data is not
+// kept in receipts, so the tx is replayed once and the result is cached.
+func (t *Transactions) scriptError(ctx context.Context, txID meter.Bytes32, blockID meter.Bytes32, txIndex uint64) (*ScriptError, error) {
+	if t.revertData == nil {
+		return nil, nil
+	}
+	key := blockID.String() + txID.String()
+	if cached, ok := t.scriptErrors.Get(key); ok {
+		return cached.(*ScriptError), nil
+	}
+	data, err := t.revertData(ctx, blockID, txIndex)
+	if err != nil {
+		return nil, err
+	}
+	scriptError := ConvertScriptError(data)
+	t.scriptErrors.Add(key, scriptError)
+	return scriptError, nil
 }
 
 func (t *Transactions) handleSendEthRawTransaction(w http.ResponseWriter, req *http.Request) error {
@@ -282,7 +333,7 @@ func (t *Transactions) handleGetTransactionReceiptByID(w http.ResponseWriter, re
 		}
 		return err
 	}
-	receipt, err := t.getTransactionReceiptByID(txID, h.ID())
+	receipt, err := t.getTransactionReceiptByID(req.Context(), txID, h.ID())
 	if err != nil {
 		return err
 	}
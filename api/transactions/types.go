@@ -12,6 +12,7 @@ import (
 
 	"github.com/dfinlab/meter/block"
 	"github.com/dfinlab/meter/meter"
+	"github.com/dfinlab/meter/script/scripterr"
 	"github.com/dfinlab/meter/tx"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/math"
@@ -208,6 +209,30 @@ type Receipt struct {
 	Reverted bool                  `json:"reverted"`
 	Meta     LogMeta               `json:"meta"`
 	Outputs  []*Output             `json:"outputs"`
+
+	ScriptError *ScriptError `json:"scriptError,omitempty"`
+}
+
+// ScriptError is the decoded revert data of the script engine.
+type ScriptError struct {
+	Module  string `json:"module"`
+	Op      string `json:"op"`
+	Code    uint32 `json:"code"`
+	Message string `json:"message"`
+}
+
+// ConvertScriptError decodes the revert data, nil if not reverted by the script engine.
+func ConvertScriptError(data []byte) *ScriptError {
+	r := scripterr.Decode(data)
+	if r == nil {
+		return nil
+	}
+	return &ScriptError{
+		Module:  r.Module(),
+		Op:      r.OpName(),
+		Code:    r.Code,
+		Message: r.Message,
+	}
 }
 
 // Output output of clause execution.
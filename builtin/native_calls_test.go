@@ -231,6 +231,28 @@ func TestParamsNative(t *testing.T) {
 		ShouldOutput(value).
 		Assert(t)
 
+	// values rejected by the validator are not set
+	limited := meter.BytesToBytes32([]byte("limited"))
+	builtin.RegisterParamValidator(limited, func(_ *state.State, _ meter.Bytes32, v *big.Int) error {
+		if v.Cmp(big.NewInt(1000)) > 0 {
+			return errors.New("exceeds 1000")
+		}
+		return nil
+	})
+
+	test.Case("set", limited, big.NewInt(1001)).
+		Caller(executor).
+		ShouldVMError(errReverted).
+		Assert(t)
+
+	test.Case("set", limited, big.NewInt(1000)).
+		Caller(executor).
+		ShouldLog(setEvent(limited, big.NewInt(1000))).
+		Assert(t)
+
+	test.Case("get", limited).
+		ShouldOutput(big.NewInt(1000)).
+		Assert(t)
 }
 
 func TestAuthorityNative(t *testing.T) {
@@ -9,10 +9,33 @@ import (
 	"math/big"
 
 	"github.com/dfinlab/meter/meter"
+	"github.com/dfinlab/meter/state"
 	"github.com/dfinlab/meter/xenv"
 	"github.com/ethereum/go-ethereum/common"
 )
 
+// ParamValidator checks the value of param key before it's set.
+type ParamValidator func(state *state.State, key meter.Bytes32, value *big.Int) error
+
+var paramValidators = make(map[meter.Bytes32]ParamValidator)
+
+// RegisterParamValidator registers the validator of param key, values set by
+// executor are rejected if invalid since the StakingParams fork.
+func RegisterParamValidator(key meter.Bytes32, validator ParamValidator) {
+	paramValidators[key] = validator
+}
+
+func forkConfig(env *xenv.Environment) meter.ForkConfig {
+	if env.Seeker() == nil {
+		return meter.NoFork
+	}
+	return meter.GetForkConfig(env.Seeker().GenesisID())
+}
+
+func isForked(env *xenv.Environment, height uint32) bool {
+	return env.BlockContext().Number >= height
+}
+
 func init() {
 	defines := []struct {
 		name string
@@ -39,6 +62,11 @@ func init() {
 			env.ParseArgs(&args)
 
 			env.UseGas(meter.SstoreSetGas)
+			if validate, ok := paramValidators[meter.Bytes32(args.Key)]; ok && isForked(env, forkConfig(env).StakingParams) {
+				if err := validate(env.State(), meter.Bytes32(args.Key), args.Value); err != nil {
+					env.Fail(err)
+				}
+			}
 			Params.Native(env.State()).Set(meter.Bytes32(args.Key), args.Value)
 			return nil
 		}},
@@ -15,11 +15,11 @@ import (
 	"github.com/dfinlab/meter/co"
 	"github.com/dfinlab/meter/kv"
 	"github.com/dfinlab/meter/meter"
+	"github.com/dfinlab/meter/metric"
 	"github.com/dfinlab/meter/tx"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/inconshreveable/log15"
 	"github.com/pkg/errors"
-	"github.com/prometheus/client_golang/prometheus"
 )
 
 const (
@@ -35,14 +35,8 @@ var errNotFound = errors.New("not found")
 var ErrBlockExist = errors.New("block already exists")
 var errParentNotFinalized = errors.New("parent is not finalized")
 var (
-	bestHeightGauge = prometheus.NewGauge(prometheus.GaugeOpts{
-		Name: "best_height",
-		Help: "BestBlock height",
-	})
-	bestQCHeightGauge = prometheus.NewGauge(prometheus.GaugeOpts{
-		Name: "best_qc_height",
-		Help: "BestQC height",
-	})
+	bestHeightGauge   = metric.NewGauge("best_height", "BestBlock height")
+	bestQCHeightGauge = metric.NewGauge("best_qc_height", "BestQC height")
 )
 
 // Chain describes a persistent block chain.
@@ -68,9 +62,6 @@ type caches struct {
 
 // New create an instance of Chain.
 func New(kv kv.GetPutter, genesisBlock *block.Block, verbose bool) (*Chain, error) {
-	prometheus.MustRegister(bestQCHeightGauge)
-	prometheus.MustRegister(bestHeightGauge)
-
 	if genesisBlock.Header().Number() != 0 {
 		return nil, errors.New("genesis number != 0")
 	}
@@ -0,0 +1,219 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package admin
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/dfinlab/meter/api"
+	"github.com/dfinlab/meter/api/utils"
+	"github.com/dfinlab/meter/comm"
+	"github.com/dfinlab/meter/consensus"
+	"github.com/dfinlab/meter/powpool"
+	"github.com/ethereum/go-ethereum/p2p/discover"
+	"github.com/gorilla/mux"
+	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
+)
+
+const maxAuditParamsSize = 4096
+
+// Options are dependencies of admin actions.
+type Options struct {
+	Token           string
+	Comm            *comm.Communicator
+	PowPool         *powpool.PowPool
+	Cons            *consensus.ConsensusReactor
+	SetLogLevel     func(lvl log15.Lvl)
+	ReloadDelegates func() error
+	AddPeer         func(node *discover.Node) error // add static peer, kept across restarts
+	RemovePeer      func(node *discover.Node) error // remove static peer
+	Shutdown        func()
+}
+
+// Admin serves authenticated actions to control the node at runtime.
+// Every action is recorded in the audit log.
+type Admin struct {
+	opts  Options
+	audit log15.Logger
+}
+
+// New creates the admin service. Actions are audit-logged with the given handler.
+func New(opts Options, auditHandler log15.Handler) *Admin {
+	audit := log15.New("pkg", "admin")
+	audit.SetHandler(auditHandler)
+	return &Admin{opts, audit}
+}
+
+func (a *Admin) authorize(req *http.Request) error {
+	token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if a.opts.Token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(a.opts.Token)) != 1 {
+		return utils.HTTPError(errors.New("unauthorized"), http.StatusUnauthorized)
+	}
+	return nil
+}
+
+// action wraps the handler with authorization and audit logging.
+func (a *Admin) action(name string, f utils.HandlerFunc) http.HandlerFunc {
+	return utils.WrapHandlerFunc(func(w http.ResponseWriter, req *http.Request) error {
+		if err := a.authorize(req); err != nil {
+			a.audit.Warn("unauthorized", "action", name, "remote", req.RemoteAddr)
+			return err
+		}
+
+		params, err := ioutil.ReadAll(io.LimitReader(req.Body, maxAuditParamsSize))
+		if err != nil {
+			return utils.BadRequest(errors.WithMessage(err, "body"))
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(params))
+
+		err = f(w, req)
+		if err != nil {
+			a.audit.Warn(name, "remote", req.RemoteAddr, "params", string(params), "err", err)
+		} else {
+			a.audit.Info(name, "remote", req.RemoteAddr, "params", string(params))
+		}
+		return err
+	})
+}
+
+func (a *Admin) handleSetLogLevel(w http.ResponseWriter, req *http.Request) error {
+	var body LogLevel
+	if err := utils.ParseJSON(req.Body, &body); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+	lvl, err := log15.LvlFromString(body.Level)
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "level"))
+	}
+	a.opts.SetLogLevel(lvl)
+	return utils.WriteJSON(w, &body)
+}
+
+func (a *Admin) handleTriggerSync(w http.ResponseWriter, req *http.Request) error {
+	if a.opts.Comm == nil {
+		return utils.Forbidden(errors.New("communicator is absent"))
+	}
+	if !a.opts.Comm.TryTriggerSync() {
+		return utils.Forbidden(errors.New("synchronization is in progress"))
+	}
+	return utils.WriteJSON(w, &Result{"sync triggered"})
+}
+
+func (a *Admin) handleReplayPowPool(w http.ResponseWriter, req *http.Request) error {
+	var body PowReplay
+	if err := utils.ParseJSON(req.Body, &body); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+	if body.Height < 0 {
+		return utils.BadRequest(errors.New("height: negative"))
+	}
+	if err := a.opts.PowPool.ReplayFrom(body.Height); err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, &Result{"pow pool replayed"})
+}
+
+func (a *Admin) handleGetFeatures(w http.ResponseWriter, req *http.Request) error {
+	return utils.WriteJSON(w, api.Features())
+}
+
+func (a *Admin) handleSetFeature(w http.ResponseWriter, req *http.Request) error {
+	var body Feature
+	if err := utils.ParseJSON(req.Body, &body); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+	if err := api.SetFeatureEnabled(body.Name, body.Enabled); err != nil {
+		return utils.BadRequest(err)
+	}
+	return utils.WriteJSON(w, api.Features())
+}
+
+func (a *Admin) handleReloadDelegates(w http.ResponseWriter, req *http.Request) error {
+	if err := a.opts.ReloadDelegates(); err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, &Result{"delegates reloaded, take effect from next committee"})
+}
+
+func (a *Admin) handleGetPacemaker(w http.ResponseWriter, req *http.Request) error {
+	status, err := a.opts.Cons.PacemakerStatus()
+	if err != nil {
+		return utils.Forbidden(err)
+	}
+	return utils.WriteJSON(w, status)
+}
+
+func parsePeer(req *http.Request) (*discover.Node, error) {
+	var body Peer
+	if err := utils.ParseJSON(req.Body, &body); err != nil {
+		return nil, utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+	node, err := discover.ParseNode(body.Enode)
+	if err != nil {
+		return nil, utils.BadRequest(errors.WithMessage(err, "enode"))
+	}
+	return node, nil
+}
+
+func (a *Admin) handleAddPeer(w http.ResponseWriter, req *http.Request) error {
+	if a.opts.AddPeer == nil {
+		return utils.Forbidden(errors.New("p2p server is absent"))
+	}
+	node, err := parsePeer(req)
+	if err != nil {
+		return err
+	}
+	if node.Incomplete() {
+		return utils.BadRequest(errors.New("enode: address required"))
+	}
+	if err := a.opts.AddPeer(node); err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, &Peer{node.String()})
+}
+
+func (a *Admin) handleRemovePeer(w http.ResponseWriter, req *http.Request) error {
+	if a.opts.RemovePeer == nil {
+		return utils.Forbidden(errors.New("p2p server is absent"))
+	}
+	node, err := parsePeer(req)
+	if err != nil {
+		return err
+	}
+	if err := a.opts.RemovePeer(node); err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, &Peer{node.String()})
+}
+
+func (a *Admin) handleShutdown(w http.ResponseWriter, req *http.Request) error {
+	if err := utils.WriteJSON(w, &Result{"shutting down"}); err != nil {
+		return err
+	}
+	a.opts.Shutdown()
+	return nil
+}
+
+func (a *Admin) Mount(root *mux.Router, pathPrefix string) {
+	sub := root.PathPrefix(pathPrefix).Subrouter()
+
+	sub.Path("/loglevel").Methods("POST").HandlerFunc(a.action("set log level", a.handleSetLogLevel))
+	sub.Path("/sync").Methods("POST").HandlerFunc(a.action("trigger sync", a.handleTriggerSync))
+	sub.Path("/powpool/replay").Methods("POST").HandlerFunc(a.action("replay pow pool", a.handleReplayPowPool))
+	sub.Path("/features").Methods("GET").HandlerFunc(a.action("get api features", a.handleGetFeatures))
+	sub.Path("/features").Methods("POST").HandlerFunc(a.action("set api feature", a.handleSetFeature))
+	sub.Path("/delegates/reload").Methods("POST").HandlerFunc(a.action("reload delegates", a.handleReloadDelegates))
+	sub.Path("/pacemaker").Methods("GET").HandlerFunc(a.action("dump pacemaker", a.handleGetPacemaker))
+	sub.Path("/peers").Methods("POST").HandlerFunc(a.action("add peer", a.handleAddPeer))
+	sub.Path("/peers").Methods("DELETE").HandlerFunc(a.action("remove peer", a.handleRemovePeer))
+	sub.Path("/shutdown").Methods("POST").HandlerFunc(a.action("shutdown", a.handleShutdown))
+}
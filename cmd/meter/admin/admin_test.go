@@ -0,0 +1,122 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package admin_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dfinlab/meter/api"
+	"github.com/dfinlab/meter/cmd/meter/admin"
+	"github.com/ethereum/go-ethereum/p2p/discover"
+	"github.com/gorilla/mux"
+	"github.com/inconshreveable/log15"
+	"github.com/stretchr/testify/assert"
+)
+
+const token = "secret"
+
+func TestAdmin(t *testing.T) {
+	var (
+		lvl      log15.Lvl
+		shutdown bool
+		records  []*log15.Record
+	)
+	router := mux.NewRouter()
+	admin.New(admin.Options{
+		Token:       token,
+		SetLogLevel: func(l log15.Lvl) { lvl = l },
+		Shutdown:    func() { shutdown = true },
+	}, log15.FuncHandler(func(r *log15.Record) error {
+		records = append(records, r)
+		return nil
+	})).Mount(router, "/")
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	post := func(path string, body string, auth bool) int {
+		req, _ := http.NewRequest("POST", ts.URL+path, bytes.NewBufferString(body))
+		if auth {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		return res.StatusCode
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, post("/loglevel", `{"level":"debug"}`, false))
+	assert.Equal(t, log15.Lvl(0), lvl)
+
+	assert.Equal(t, http.StatusOK, post("/loglevel", `{"level":"debug"}`, true))
+	assert.Equal(t, log15.LvlDebug, lvl)
+	assert.Equal(t, http.StatusBadRequest, post("/loglevel", `{"level":"verbose"}`, true))
+
+	assert.Equal(t, http.StatusOK, post("/features", `{"name":"debug","enabled":false}`, true))
+	assert.False(t, api.Features()["debug"])
+	assert.Equal(t, http.StatusBadRequest, post("/features", `{"name":"unknown","enabled":false}`, true))
+	api.SetFeatureEnabled("debug", true)
+
+	assert.Equal(t, http.StatusOK, post("/shutdown", "", true))
+	assert.True(t, shutdown)
+
+	// every action is audit-logged, including the rejected ones
+	assert.Equal(t, 6, len(records))
+	assert.Equal(t, "unauthorized", records[0].Msg)
+	assert.Equal(t, "set log level", records[1].Msg)
+}
+
+func TestAdminPeers(t *testing.T) {
+	peers := make(map[discover.NodeID]bool)
+	router := mux.NewRouter()
+	admin.New(admin.Options{
+		Token: token,
+		AddPeer: func(node *discover.Node) error {
+			peers[node.ID] = true
+			return nil
+		},
+		RemovePeer: func(node *discover.Node) error {
+			delete(peers, node.ID)
+			return nil
+		},
+	}, log15.DiscardHandler()).Mount(router, "/")
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	do := func(method string, body string, auth bool) int {
+		req, _ := http.NewRequest(method, ts.URL+"/peers", bytes.NewBufferString(body))
+		if auth {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		return res.StatusCode
+	}
+
+	id := "3a4f3dd6a2cbab8d2e72e0bd2a59d5ab9a0b2d6b1a7e14f8c3b1f7a8d8c0f1e23a4f3dd6a2cbab8d2e72e0bd2a59d5ab9a0b2d6b1a7e14f8c3b1f7a8d8c0f1e2"
+	node, _ := discover.HexID(id)
+	enode := `{"enode":"enode://` + id + `@127.0.0.1:11235"}`
+
+	assert.Equal(t, http.StatusUnauthorized, do("POST", enode, false))
+	assert.Equal(t, http.StatusBadRequest, do("POST", `{"enode":"enode://`+id+`"}`, true), "address required")
+	assert.Equal(t, http.StatusBadRequest, do("POST", `{"enode":"invalid"}`, true))
+	assert.Equal(t, 0, len(peers))
+
+	assert.Equal(t, http.StatusOK, do("POST", enode, true))
+	assert.True(t, peers[node])
+
+	assert.Equal(t, http.StatusUnauthorized, do("DELETE", enode, false))
+	assert.True(t, peers[node])
+	assert.Equal(t, http.StatusOK, do("DELETE", enode, true))
+	assert.False(t, peers[node])
+}
@@ -0,0 +1,29 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package admin
+
+// LogLevel is the log verbosity, one of crit, error, warn, info, debug.
+type LogLevel struct {
+	Level string `json:"level"`
+}
+
+type PowReplay struct {
+	Height int32 `json:"height"`
+}
+
+type Feature struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+}
+
+// Peer is the static peer in enode format.
+type Peer struct {
+	Enode string `json:"enode"`
+}
+
+type Result struct {
+	Message string `json:"message"`
+}
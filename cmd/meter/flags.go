@@ -58,6 +58,22 @@ var (
 		Name:  "peers, P",
 		Usage: "P2P peers in enode format",
 	}
+	staticPeersFlag = cli.StringSliceFlag{
+		Name:  "static-peers",
+		Usage: "P2P peers in enode format, which are always kept connected",
+	}
+	trustedPeersFlag = cli.StringSliceFlag{
+		Name:  "trusted-peers",
+		Usage: "P2P peers in enode format, which are allowed to connect even above max peers",
+	}
+	netRestrictFlag = cli.StringFlag{
+		Name:  "netrestrict",
+		Usage: "restrict P2P network communication to the given IP networks (CIDR masks)",
+	}
+	whitelistOnlyFlag = cli.BoolFlag{
+		Name:  "whitelist-only",
+		Usage: "only connect with static and trusted peers, discovery is disabled",
+	}
 	maxPeersFlag = cli.IntFlag{
 		Name:  "max-peers",
 		Usage: "maximum number of P2P network peers (P2P network disabled if set to 0)",
@@ -86,6 +102,11 @@ var (
 		Value: 200000000,
 		Usage: "block gas limit",
 	}
+	blockIntervalFlag = cli.IntFlag{
+		Name:  "block-interval",
+		Value: 10,
+		Usage: "block interval in seconds for solo mode (ignored if on-demand is set)",
+	}
 	importMasterKeyFlag = cli.BoolFlag{
 		Name:  "import",
 		Usage: "import master key from keystore",
@@ -94,6 +115,22 @@ var (
 		Name:  "export",
 		Usage: "export master key to keystore",
 	}
+	passwordFlag = cli.StringFlag{
+		Name:  "password",
+		Usage: "password file to unlock the master key keystore",
+	}
+	newPasswordFlag = cli.StringFlag{
+		Name:  "new-password",
+		Usage: "password file to encrypt the master key keystore",
+	}
+	forceFlag = cli.BoolFlag{
+		Name:  "force",
+		Usage: "overwrite the existing master key keystore on import",
+	}
+	newBlsKeyFlag = cli.BoolFlag{
+		Name:  "new-bls-key",
+		Usage: "generate a new BLS key when importing a legacy keystore, which changes the consensus identity of the node",
+	}
 	generateKFrameFlag = cli.BoolFlag{
 		Name:  "gen-kframe",
 		Usage: "start a coroutine for kframe generation (FOR TEST ONLY)",
@@ -173,4 +210,107 @@ var (
 		Usage: "path for https key file (default is meterio.key)",
 		Value: "meterio.key",
 	}
+	remoteSignerFlag = cli.StringFlag{
+		Name:  "remote-signer",
+		Usage: "URL of the remote signer holding the master key, e.g. http://localhost:8671",
+	}
+	remoteSignerTokenFlag = cli.StringFlag{
+		Name:  "remote-signer-token",
+		Usage: "path of the file holding the token of remote signer, which is signer.token in data dir of the signer",
+	}
+	signerAddrFlag = cli.StringFlag{
+		Name:  "signer-addr",
+		Value: "localhost:8671",
+		Usage: "signer service listening address",
+	}
+	adminAddrFlag = cli.StringFlag{
+		Name:  "admin-addr",
+		Usage: "admin service listening address, e.g. localhost:8673, or unix:<path> for a unix socket (disabled if empty)",
+	}
+	metricsAddrFlag = cli.StringFlag{
+		Name:  "metrics-addr",
+		Usage: "prometheus metrics service listening address, e.g. localhost:8672 (disabled if empty)",
+	}
+
+	// flags of the staking and auction transactions
+	apiURLFlag = cli.StringFlag{
+		Name:  "api-url",
+		Value: "http://localhost:8669",
+		Usage: "API service of the node to send the transaction to",
+	}
+	keystoreFlag = cli.StringFlag{
+		Name:  "keystore",
+		Usage: "JSON keystore of the account sending the transaction",
+	}
+	keyPasswordFlag = cli.StringFlag{
+		Name:  "key-password",
+		Usage: "password file to unlock the account keystore",
+	}
+	dryRunFlag = cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "simulate the transaction without sending it",
+	}
+	amountFlag = cli.StringFlag{
+		Name:  "amount",
+		Usage: "amount in MTR or MTRG, e.g. 100.5",
+	}
+	tokenFlag = cli.StringFlag{
+		Name:  "token",
+		Value: "mtrg",
+		Usage: "token of the amount (mtr|mtrg)",
+	}
+	candidateFlag = cli.StringFlag{
+		Name:  "candidate",
+		Usage: "address of the candidate",
+	}
+	bucketFlag = cli.StringFlag{
+		Name:  "bucket",
+		Usage: "ID of the staking bucket",
+	}
+	lockOptionFlag = cli.UintFlag{
+		Name:  "lock-option",
+		Usage: "lock option of the bucket (0: one day, 1: one week, 2: two weeks, 3: three weeks, 4: four weeks)",
+	}
+	candNameFlag = cli.StringFlag{
+		Name:  "name",
+		Usage: "name of the candidate",
+	}
+	candPubKeyFlag = cli.StringFlag{
+		Name:  "pubkey",
+		Usage: "public key of the candidate node, as shown by public-key command",
+	}
+	candIPFlag = cli.StringFlag{
+		Name:  "ip",
+		Usage: "IPv4 address of the candidate node",
+	}
+	candPortFlag = cli.UintFlag{
+		Name:  "port",
+		Value: 8670,
+		Usage: "port of the candidate node",
+	}
+	commissionFlag = cli.StringFlag{
+		Name:  "commission",
+		Value: "10",
+		Usage: "commission rate of the candidate in percent",
+	}
+	descriptionFlag = cli.StringFlag{
+		Name:  "description",
+		Usage: "description of the candidate",
+	}
+	websiteFlag = cli.StringFlag{
+		Name:  "website",
+		Usage: "website of the candidate",
+	}
+	contactFlag = cli.StringFlag{
+		Name:  "contact",
+		Usage: "contact of the candidate",
+	}
+	auctionIDFlag = cli.StringFlag{
+		Name:  "auction-id",
+		Usage: "ID of the auction",
+	}
+	bidNonceFlag = cli.Uint64Flag{
+		Name:  "nonce",
+		Usage: "nonce of the bid, as printed when bidding",
+	}
 )
@@ -6,6 +6,7 @@
 package main
 
 import (
+	"bytes"
 	"crypto/ecdsa"
 	"crypto/sha256"
 	b64 "encoding/base64"
@@ -18,6 +19,7 @@ import (
 	"github.com/dfinlab/meter/consensus"
 	bls "github.com/dfinlab/meter/crypto/multi_sig"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
 	cli "gopkg.in/urfave/cli.v1"
 )
 
@@ -35,7 +37,7 @@ func verifyECDSA(privKey *ecdsa.PrivateKey, pubKey *ecdsa.PublicKey) bool {
 	hash := []byte("testing")
 	r, s, err := ecdsa.Sign(strings.NewReader("test-plain-text-some-thing"), privKey, hash)
 	if err != nil {
-		fmt.Println("Error during sign: ", err)
+		fmt.Fprintln(os.Stderr, "Error during sign: ", err)
 		return false
 	}
 	return ecdsa.Verify(pubKey, hash, r, s)
@@ -50,8 +52,10 @@ func verifyBls(privKey bls.PrivateKey, pubKey bls.PublicKey) bool {
 }
 
 type KeyLoader struct {
+	ctx          *cli.Context
 	masterPath   string
 	publicPath   string
+	keystorePath string
 	masterBytes  []byte
 	publicBytes  []byte
 	ecdsaPrivKey *ecdsa.PrivateKey
@@ -59,7 +63,8 @@ type KeyLoader struct {
 	blsPrivKey   *bls.PrivateKey
 	blsPubKey    *bls.PublicKey
 
-	updated bool
+	updated   bool
+	encrypted bool
 }
 
 func NewKeyLoader(ctx *cli.Context) *KeyLoader {
@@ -75,10 +80,12 @@ func NewKeyLoader(ctx *cli.Context) *KeyLoader {
 		publicBytes = []byte(strings.TrimSuffix(string(publicBytes), "\n"))
 	}
 	return &KeyLoader{
-		masterPath:  masterPath,
-		publicPath:  publicPath,
-		masterBytes: masterBytes,
-		publicBytes: publicBytes,
+		ctx:          ctx,
+		masterPath:   masterPath,
+		publicPath:   publicPath,
+		keystorePath: masterKeystorePath(ctx),
+		masterBytes:  masterBytes,
+		publicBytes:  publicBytes,
 
 		updated: false,
 	}
@@ -88,7 +95,7 @@ func (k *KeyLoader) genECDSA() error {
 	k.updated = true
 	key, err := crypto.GenerateKey()
 	if err != nil {
-		fmt.Println("Error during ECDSA key pair generation: ", err)
+		fmt.Fprintln(os.Stderr, "Error during ECDSA key pair generation: ", err)
 		return err
 	}
 	k.ecdsaPrivKey = key
@@ -176,7 +183,8 @@ func (k *KeyLoader) validateBls() error {
 	return nil
 }
 
-func (k *KeyLoader) saveKeys(system bls.System) error {
+// encodeKeys encodes the keys as the content of master.key and public.key.
+func (k *KeyLoader) encodeKeys(system bls.System) (master, public []byte) {
 	ecdsaPrivBytes := crypto.FromECDSA(k.ecdsaPrivKey)
 	ecdsaPrivB64 := b64.StdEncoding.EncodeToString(ecdsaPrivBytes)
 	ecdsaPubBytes := crypto.FromECDSAPub(k.ecdsaPubKey)
@@ -188,60 +196,124 @@ func (k *KeyLoader) saveKeys(system bls.System) error {
 
 	priv := strings.Join([]string{ecdsaPrivB64, blsPrivB64}, ":::")
 	pub := strings.Join([]string{ecdsaPubB64, blsPubB64}, ":::")
-	k.masterBytes = []byte(priv)
-	k.publicBytes = []byte(pub)
-	err := ioutil.WriteFile(k.masterPath, []byte(priv+"\n"), 0600)
+	return []byte(priv), []byte(pub)
+}
+
+func (k *KeyLoader) saveKeys(system bls.System) error {
+	k.masterBytes, k.publicBytes = k.encodeKeys(system)
+	err := ioutil.WriteFile(k.masterPath, append(k.masterBytes, '\n'), 0600)
 	if err != nil {
 		return err
 	}
-	err = ioutil.WriteFile(k.publicPath, []byte(pub+"\n"), 0600)
+	err = ioutil.WriteFile(k.publicPath, append(k.publicBytes, '\n'), 0600)
 	return err
 }
 
+// unlock decrypts the master keystore with the passphrase from --password or
+// the terminal. Keys in the keystore take precedence over master.key.
+func (k *KeyLoader) unlock() error {
+	keyjson, err := ioutil.ReadFile(k.keystorePath)
+	if err != nil {
+		return err
+	}
+	password, err := readPassphrase(k.ctx, passwordFlag, "Enter passphrase to unlock master key: ")
+	if err != nil {
+		return err
+	}
+	master, public, err := decryptMasterKey(keyjson, password)
+	if err != nil {
+		return err
+	}
+	// the plaintext copy left by an earlier version is removed, unless it's another key
+	if len(k.masterBytes) != 0 {
+		if bytes.Equal(k.masterBytes, master) {
+			if err := os.Remove(k.masterPath); err != nil {
+				return err
+			}
+			fmt.Fprintln(os.Stderr, "plaintext master.key removed, master key is kept in master.keystore")
+		} else {
+			fmt.Fprintln(os.Stderr, "WARNING: plaintext master.key differs from master.keystore and is ignored, remove it once backed up")
+		}
+	}
+	k.masterBytes = master
+	k.publicBytes = public
+	k.encrypted = true
+	return nil
+}
+
+// checkMasterKey validates the master key content without generating any
+// missing key.
+func checkMasterKey(master, public []byte) error {
+	k := &KeyLoader{masterBytes: master, publicBytes: public}
+	if err := k.validateECDSA(); err != nil {
+		return err
+	}
+	if err := k.validateBls(); err != nil {
+		return err
+	}
+	if k.updated {
+		return errors.New("malformed master key")
+	}
+	return nil
+}
+
 func (k *KeyLoader) Load() (*ecdsa.PrivateKey, *ecdsa.PublicKey, *consensus.BlsCommon, error) {
+	if fileExists(k.keystorePath) {
+		if err := k.unlock(); err != nil {
+			return nil, nil, nil, errors.WithMessage(err, "unlock master keystore")
+		}
+	}
+
 	err := k.validateECDSA()
 	if err != nil {
-		fmt.Println("could not validate ecdsa keys, error:", err)
+		fmt.Fprintln(os.Stderr, "could not validate ecdsa keys, error:", err)
 		panic("could not validate ecdsa keys")
 	}
 
 	err = k.validateBls()
 	if err != nil {
-		fmt.Println("could not validate ecdsa keys, error:", err)
+		fmt.Fprintln(os.Stderr, "could not validate ecdsa keys, error:", err)
 		panic("could not validate ecdsa keys")
 	}
 
 	paraBytes, err := hex.DecodeString(paraString)
 	if err != nil {
-		fmt.Println("decode paraString error:", err)
+		fmt.Fprintln(os.Stderr, "decode paraString error:", err)
 		return nil, nil, nil, nil
 	}
 	params, err := bls.ParamsFromBytes(paraBytes)
 	if err != nil {
-		fmt.Println("params from bytes error:", err)
+		fmt.Fprintln(os.Stderr, "params from bytes error:", err)
 		return nil, nil, nil, nil
 	}
 	pairing := bls.GenPairing(params)
 
 	systemBytes, err := hex.DecodeString(systemString)
 	if err != nil {
-		fmt.Println("decode system string error:", err)
+		fmt.Fprintln(os.Stderr, "decode system string error:", err)
 		return nil, nil, nil, nil
 	}
 
 	system, err := bls.SystemFromBytes(pairing, systemBytes)
 	if err != nil {
-		fmt.Println("system from bytes error:", err)
+		fmt.Fprintln(os.Stderr, "system from bytes error:", err)
 		return nil, nil, nil, nil
 	}
 
 	if k.updated == true {
+		// never replace keys of an encrypted keystore
+		if k.encrypted {
+			return nil, nil, nil, errors.New("malformed master keystore")
+		}
 		err := k.saveKeys(system)
 		if err != nil {
-			fmt.Println("save keys error:", err)
+			fmt.Fprintln(os.Stderr, "save keys error:", err)
 		}
 
 	}
+	if !k.encrypted {
+		fmt.Fprintln(os.Stderr, "master key is stored in plaintext, run `meter master-key rotate` to encrypt it")
+	}
 
 	blsCommon := consensus.NewBlsCommonFromParams(*k.blsPubKey, *k.blsPrivKey, system, params, pairing)
 	return k.ecdsaPrivKey, k.ecdsaPubKey, blsCommon, nil
@@ -0,0 +1,286 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pborman/uuid"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/scrypt"
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+const (
+	masterKeystoreVersion = 1
+	legacyKeystoreVersion = 3 // ethereum keystore of the ECDSA key only
+
+	keystoreCipher = "aes-128-ctr"
+	keystoreKDF    = "scrypt"
+	scryptR        = 8
+	scryptDKLen    = 32
+)
+
+var (
+	errDecrypt        = errors.New("could not decrypt key with given passphrase")
+	errLegacyKeystore = errors.New("legacy ethereum keystore")
+)
+
+// masterKeystore is the encrypted form of master.key. Unlike the ethereum
+// keystore it covers both the ECDSA and the BLS private keys.
+type masterKeystore struct {
+	Version   int            `json:"version"`
+	ID        string         `json:"id"`
+	Address   string         `json:"address"`
+	PublicKey string         `json:"public_key"`
+	Crypto    keystoreCrypto `json:"crypto"`
+}
+
+type keystoreCrypto struct {
+	Cipher       string               `json:"cipher"`
+	CipherText   string               `json:"ciphertext"`
+	CipherParams keystoreCipherParams `json:"cipherparams"`
+	KDF          string               `json:"kdf"`
+	KDFParams    keystoreKDFParams    `json:"kdfparams"`
+	MAC          string               `json:"mac"`
+}
+
+type keystoreCipherParams struct {
+	IV string `json:"iv"`
+}
+
+type keystoreKDFParams struct {
+	N     int    `json:"n"`
+	R     int    `json:"r"`
+	P     int    `json:"p"`
+	DKLen int    `json:"dklen"`
+	Salt  string `json:"salt"`
+}
+
+// encryptMasterKey encrypts the master key content, which is the ECDSA and BLS
+// private keys in base64 joined by ":::", with scrypt and aes-128-ctr.
+func encryptMasterKey(master, public []byte, passphrase string, scryptN, scryptP int) ([]byte, error) {
+	address, err := addressOfPublicKey(public)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+	derivedKey, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, scryptDKLen)
+	if err != nil {
+		return nil, err
+	}
+
+	iv := make([]byte, aes.BlockSize)
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return nil, err
+	}
+	cipherText, err := aesCTRXOR(derivedKey[:16], master, iv)
+	if err != nil {
+		return nil, err
+	}
+	mac := crypto.Keccak256(derivedKey[16:32], cipherText)
+
+	ks := masterKeystore{
+		Version:   masterKeystoreVersion,
+		ID:        uuid.NewRandom().String(),
+		Address:   address,
+		PublicKey: string(public),
+		Crypto: keystoreCrypto{
+			Cipher:       keystoreCipher,
+			CipherText:   hex.EncodeToString(cipherText),
+			CipherParams: keystoreCipherParams{IV: hex.EncodeToString(iv)},
+			KDF:          keystoreKDF,
+			KDFParams: keystoreKDFParams{
+				N:     scryptN,
+				R:     scryptR,
+				P:     scryptP,
+				DKLen: scryptDKLen,
+				Salt:  hex.EncodeToString(salt),
+			},
+			MAC: hex.EncodeToString(mac),
+		},
+	}
+	return json.MarshalIndent(&ks, "", "  ")
+}
+
+// decryptMasterKey returns the master key content and the public key content
+// stored in the keystore.
+func decryptMasterKey(keyjson []byte, passphrase string) (master, public []byte, err error) {
+	var ks masterKeystore
+	if err := json.Unmarshal(keyjson, &ks); err != nil {
+		return nil, nil, errors.WithMessage(err, "unmarshal")
+	}
+	if ks.Version == legacyKeystoreVersion {
+		return nil, nil, errLegacyKeystore
+	}
+	if ks.Version != masterKeystoreVersion {
+		return nil, nil, errors.Errorf("unsupported keystore version %v", ks.Version)
+	}
+	c := ks.Crypto
+	if c.Cipher != keystoreCipher {
+		return nil, nil, errors.Errorf("unsupported cipher %v", c.Cipher)
+	}
+	if c.KDF != keystoreKDF {
+		return nil, nil, errors.Errorf("unsupported kdf %v", c.KDF)
+	}
+
+	salt, err := hex.DecodeString(c.KDFParams.Salt)
+	if err != nil {
+		return nil, nil, err
+	}
+	iv, err := hex.DecodeString(c.CipherParams.IV)
+	if err != nil {
+		return nil, nil, err
+	}
+	cipherText, err := hex.DecodeString(c.CipherText)
+	if err != nil {
+		return nil, nil, err
+	}
+	mac, err := hex.DecodeString(c.MAC)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p := c.KDFParams
+	derivedKey, err := scrypt.Key([]byte(passphrase), salt, p.N, p.R, p.P, p.DKLen)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !bytes.Equal(crypto.Keccak256(derivedKey[16:32], cipherText), mac) {
+		return nil, nil, errDecrypt
+	}
+	master, err = aesCTRXOR(derivedKey[:16], cipherText, iv)
+	if err != nil {
+		return nil, nil, err
+	}
+	return master, []byte(ks.PublicKey), nil
+}
+
+// decryptLegacyKeystore decrypts the ethereum keystore exported by earlier versions,
+// which holds the ECDSA key only. A new BLS key pair is generated along with it, so
+// callers must only use it when asked for a new consensus identity.
+func decryptLegacyKeystore(keyjson []byte, passphrase string) (master, public []byte, err error) {
+	key, err := keystore.DecryptKey(keyjson, passphrase)
+	if err != nil {
+		return nil, nil, err
+	}
+	system, err := getBlsSystem()
+	if err != nil {
+		return nil, nil, err
+	}
+	k := &KeyLoader{ecdsaPrivKey: key.PrivateKey, ecdsaPubKey: &key.PrivateKey.PublicKey}
+	if err := k.genBls(); err != nil {
+		return nil, nil, err
+	}
+	master, public = k.encodeKeys(*system)
+	return master, public, nil
+}
+
+func aesCTRXOR(key, in, iv []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(in))
+	cipher.NewCTR(block, iv).XORKeyStream(out, in)
+	return out, nil
+}
+
+func addressOfPublicKey(public []byte) (string, error) {
+	split := strings.Split(string(public), ":::")
+	pub, err := fromBase64Pub(split[0])
+	if err != nil {
+		return "", errors.WithMessage(err, "invalid public key")
+	}
+	return hex.EncodeToString(crypto.PubkeyToAddress(*pub).Bytes()), nil
+}
+
+// readPassphrase reads the passphrase from the first line of the password file
+// given by flag, or prompts on the terminal if the flag is not set.
+func readPassphrase(ctx *cli.Context, flag cli.StringFlag, prompt string) (string, error) {
+	if path := ctx.String(flag.Name); path != "" {
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return "", errors.WithMessage(err, "read password file")
+		}
+		return strings.TrimRight(strings.SplitN(string(content), "\n", 2)[0], "\r"), nil
+	}
+	return readPasswordFromNewTTY(prompt)
+}
+
+// readNewPassphrase reads a non-empty passphrase from --new-password, or
+// prompts twice on the terminal.
+func readNewPassphrase(ctx *cli.Context) (string, error) {
+	password, err := readPassphrase(ctx, newPasswordFlag, "Enter new passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if password == "" {
+		return "", errors.New("non-empty passphrase required")
+	}
+	if ctx.String(newPasswordFlag.Name) != "" {
+		return password, nil
+	}
+
+	confirm, err := readPasswordFromNewTTY("Confirm passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if password != confirm {
+		return "", errors.New("passphrase confirmation mismatch")
+	}
+	return password, nil
+}
+
+// saveMasterKeystore stores the keystore of master along with the public key. An
+// existing keystore is kept unless overwrite is set. The plaintext master.key is
+// removed only if it's the same key, otherwise it's left for backup.
+func saveMasterKeystore(ctx *cli.Context, keyjson, master, public []byte, overwrite bool) error {
+	keystorePath := masterKeystorePath(ctx)
+	if !overwrite && fileExists(keystorePath) {
+		return errors.Errorf("%v already exists, use --%v to overwrite", keystorePath, forceFlag.Name)
+	}
+	var plaintext []byte
+	masterPath := masterKeyPath(ctx)
+	if fileExists(masterPath) {
+		content, err := ioutil.ReadFile(masterPath)
+		if err != nil {
+			return err
+		}
+		plaintext = bytes.TrimSuffix(content, []byte("\n"))
+	}
+
+	if err := ioutil.WriteFile(keystorePath, keyjson, 0600); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(publicKeyPath(ctx), append(public, '\n'), 0600); err != nil {
+		return err
+	}
+	if plaintext == nil {
+		return nil
+	}
+	if !bytes.Equal(plaintext, master) {
+		fmt.Fprintln(os.Stderr, "WARNING: plaintext master.key holds another key and is kept, remove it once backed up")
+		return nil
+	}
+	return os.Remove(masterPath)
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	b64 "encoding/base64"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+func testMasterKey(t *testing.T) (master, public []byte) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	blsPriv := b64.StdEncoding.EncodeToString([]byte("bls-private-key"))
+	blsPub := b64.StdEncoding.EncodeToString([]byte("bls-public-key"))
+	master = []byte(strings.Join([]string{b64.StdEncoding.EncodeToString(crypto.FromECDSA(key)), blsPriv}, ":::"))
+	public = []byte(strings.Join([]string{b64.StdEncoding.EncodeToString(crypto.FromECDSAPub(&key.PublicKey)), blsPub}, ":::"))
+	return
+}
+
+func TestMasterKeystore(t *testing.T) {
+	master, public := testMasterKey(t)
+
+	keyjson, err := encryptMasterKey(master, public, "passphrase", keystore.LightScryptN, keystore.LightScryptP)
+	assert.Nil(t, err)
+	assert.False(t, strings.Contains(string(keyjson), string(master)), "private keys should not be stored in plaintext")
+
+	var ks masterKeystore
+	assert.Nil(t, json.Unmarshal(keyjson, &ks))
+	assert.Equal(t, string(public), ks.PublicKey)
+
+	decMaster, decPublic, err := decryptMasterKey(keyjson, "passphrase")
+	assert.Nil(t, err)
+	assert.Equal(t, master, decMaster)
+	assert.Equal(t, public, decPublic)
+
+	_, _, err = decryptMasterKey(keyjson, "wrong")
+	assert.Equal(t, errDecrypt, err)
+}
+
+func TestMasterKeystoreTampered(t *testing.T) {
+	master, public := testMasterKey(t)
+
+	keyjson, err := encryptMasterKey(master, public, "passphrase", keystore.LightScryptN, keystore.LightScryptP)
+	assert.Nil(t, err)
+
+	var ks masterKeystore
+	assert.Nil(t, json.Unmarshal(keyjson, &ks))
+	cipherText := []byte(ks.Crypto.CipherText)
+	if cipherText[0] == '0' {
+		cipherText[0] = '1'
+	} else {
+		cipherText[0] = '0'
+	}
+	ks.Crypto.CipherText = string(cipherText)
+	tampered, _ := json.Marshal(&ks)
+
+	_, _, err = decryptMasterKey(tampered, "passphrase")
+	assert.Equal(t, errDecrypt, err)
+}
+
+func TestLegacyKeystore(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyjson, err := keystore.EncryptKey(&keystore.Key{
+		Id:         uuid.NewRandom(),
+		Address:    crypto.PubkeyToAddress(key.PublicKey),
+		PrivateKey: key,
+	}, "passphrase", keystore.LightScryptN, keystore.LightScryptP)
+	assert.Nil(t, err)
+
+	_, _, err = decryptMasterKey(keyjson, "passphrase")
+	assert.Equal(t, errLegacyKeystore, err)
+
+	_, _, err = decryptLegacyKeystore(keyjson, "wrong")
+	assert.NotNil(t, err)
+
+	master, public, err := decryptLegacyKeystore(keyjson, "passphrase")
+	assert.Nil(t, err)
+	assert.Nil(t, checkMasterKey(master, public))
+	assert.True(t, strings.HasPrefix(string(master), b64.StdEncoding.EncodeToString(crypto.FromECDSA(key))+":::"))
+}
+
+func TestSaveMasterKeystore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "keystore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	set := flag.NewFlagSet("test", flag.ContinueOnError)
+	set.String(dataDirFlag.Name, dir, "")
+	ctx := cli.NewContext(nil, set, nil)
+
+	master, public := testMasterKey(t)
+	other, _ := testMasterKey(t)
+	assert.Nil(t, ioutil.WriteFile(masterKeyPath(ctx), append(master, '\n'), 0600))
+
+	// another key, the plaintext one is kept
+	assert.Nil(t, saveMasterKeystore(ctx, []byte("keystore of other"), other, public, false))
+	assert.True(t, fileExists(masterKeyPath(ctx)))
+
+	// the keystore is never overwritten implicitly
+	err = saveMasterKeystore(ctx, []byte("keystore of master"), master, public, false)
+	assert.NotNil(t, err)
+	content, _ := ioutil.ReadFile(filepath.Join(dir, "master.keystore"))
+	assert.Equal(t, "keystore of other", string(content))
+
+	// the same key, the plaintext one is removed
+	assert.Nil(t, saveMasterKeystore(ctx, []byte("keystore of master"), master, public, true))
+	assert.False(t, fileExists(masterKeyPath(ctx)))
+	content, _ = ioutil.ReadFile(filepath.Join(dir, "master.keystore"))
+	assert.Equal(t, "keystore of master", string(content))
+}
@@ -6,13 +6,15 @@
 package main
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/binary"
-	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"math"
 	"math/rand"
+	"net"
+	"net/http"
 	"os"
 	"path"
 	"path/filepath"
@@ -22,22 +24,27 @@ import (
 	"github.com/dfinlab/meter/api"
 	"github.com/dfinlab/meter/api/doc"
 	"github.com/dfinlab/meter/block"
+	"github.com/dfinlab/meter/cmd/meter/admin"
 	"github.com/dfinlab/meter/cmd/meter/node"
+	"github.com/dfinlab/meter/cmd/meter/solo"
+	"github.com/dfinlab/meter/co"
 	"github.com/dfinlab/meter/consensus"
+	"github.com/dfinlab/meter/genesis"
+	"github.com/dfinlab/meter/logdb"
+	"github.com/dfinlab/meter/lvldb"
 	"github.com/dfinlab/meter/meter"
 	"github.com/dfinlab/meter/powpool"
 	pow_api "github.com/dfinlab/meter/powpool/api"
 	"github.com/dfinlab/meter/preset"
 	"github.com/dfinlab/meter/script"
+	"github.com/dfinlab/meter/signer"
 	"github.com/dfinlab/meter/state"
 	"github.com/dfinlab/meter/txpool"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/p2p/discover"
-	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/gorilla/mux"
 	"github.com/inconshreveable/log15"
 	"github.com/mattn/go-isatty"
-	"github.com/pborman/uuid"
 	"github.com/pkg/errors"
 	cli "gopkg.in/urfave/cli.v1"
 )
@@ -98,6 +105,10 @@ func main() {
 			p2pPortFlag,
 			natFlag,
 			peersFlag,
+			staticPeersFlag,
+			trustedPeersFlag,
+			netRestrictFlag,
+			whitelistOnlyFlag,
 			forceLastKFrameFlag,
 			generateKFrameFlag,
 			skipSignatureCheckFlag,
@@ -115,18 +126,80 @@ func main() {
 			epochBlockCountFlag,
 			httpsCertFlag,
 			httpsKeyFlag,
+			passwordFlag,
+			remoteSignerFlag,
+			remoteSignerTokenFlag,
+			metricsAddrFlag,
+			adminAddrFlag,
 		},
 		Action: defaultAction,
 		Commands: []cli.Command{
+			{
+				Name:  "solo",
+				Usage: "client runs in solo mode for test & dev",
+				Flags: []cli.Flag{
+					dataDirFlag,
+					apiAddrFlag,
+					apiCorsFlag,
+					apiTimeoutFlag,
+					apiCallGasLimitFlag,
+					apiBacktraceLimitFlag,
+					onDemandFlag,
+					persistFlag,
+					gasLimitFlag,
+					blockIntervalFlag,
+					verbosityFlag,
+					httpsCertFlag,
+					httpsKeyFlag,
+				},
+				Action: soloAction,
+			},
 			{
 				Name:  "master-key",
-				Usage: "import and export master key",
+				Usage: "import, export and rotate the encrypted master key",
 				Flags: []cli.Flag{
 					dataDirFlag,
 					importMasterKeyFlag,
 					exportMasterKeyFlag,
+					passwordFlag,
+					newPasswordFlag,
+					forceFlag,
+					newBlsKeyFlag,
 				},
 				Action: masterKeyAction,
+				Subcommands: []cli.Command{
+					{
+						Name:  "import",
+						Usage: "import master key from JSON keystore",
+						Flags: []cli.Flag{
+							dataDirFlag,
+							passwordFlag,
+							forceFlag,
+							newBlsKeyFlag,
+						},
+						Action: importMasterKeyAction,
+					},
+					{
+						Name:  "export",
+						Usage: "export master key to JSON keystore",
+						Flags: []cli.Flag{
+							dataDirFlag,
+							passwordFlag,
+							newPasswordFlag,
+						},
+						Action: exportMasterKeyAction,
+					},
+					{
+						Name:  "rotate",
+						Usage: "encrypt master key with a new passphrase",
+						Flags: []cli.Flag{
+							dataDirFlag,
+							passwordFlag,
+							newPasswordFlag,
+						},
+						Action: rotateMasterKeyAction,
+					},
+				},
 			},
 			{
 				Name:  "enode-id",
@@ -142,9 +215,21 @@ func main() {
 				Usage: "export public key",
 				Flags: []cli.Flag{
 					dataDirFlag,
+					passwordFlag,
 				},
 				Action: publicKeyAction,
 			},
+			{
+				Name:  "signer",
+				Usage: "serve the master key as remote signer",
+				Flags: []cli.Flag{
+					dataDirFlag,
+					passwordFlag,
+					signerAddrFlag,
+					verbosityFlag,
+				},
+				Action: signerAction,
+			},
 			{
 				Name:  "peers",
 				Usage: "export peers",
@@ -154,6 +239,8 @@ func main() {
 				},
 				Action: peersAction,
 			},
+			stakingCommand,
+			auctionCommand,
 		},
 	}
 
@@ -185,29 +272,76 @@ func publicKeyAction(ctx *cli.Context) error {
 	return nil
 }
 
+func signerAction(ctx *cli.Context) error {
+	exitSignal := handleExitSignal()
+	initLogger(ctx)
+
+	makeDataDir(ctx)
+	keyLoader := NewKeyLoader(ctx)
+	privKey, _, blsCommon, err := keyLoader.Load()
+	if err != nil {
+		fatal("error load keys", err)
+	}
+	guard, err := signer.NewGuard(filepath.Join(ctx.String(dataDirFlag.Name), "sign-state.json"))
+	if err != nil {
+		fatal("load sign state:", err)
+	}
+	local := signer.NewLocal(privKey, *blsCommon.GetSystem(), blsCommon.GetPrivKey(), *blsCommon.GetPubKey(), guard)
+
+	tokenPath := filepath.Join(ctx.String(dataDirFlag.Name), "signer.token")
+	token, err := loadOrGenerateToken(tokenPath)
+	if err != nil {
+		fatal("load or generate signer token:", err)
+	}
+
+	addr := ctx.String(signerAddrFlag.Name)
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		fatal(fmt.Sprintf("listen signer addr [%v]: %v", addr, err))
+	}
+	router := mux.NewRouter()
+	signer.NewServer(local, token).Mount(router, "/")
+	srv := &http.Server{Handler: router}
+
+	var goes co.Goes
+	goes.Go(func() {
+		srv.Serve(listener)
+	})
+	fmt.Printf("Signer of %v serving on http://%v/, token in %v\n", local.Address(), listener.Addr(), tokenPath)
+
+	<-exitSignal.Done()
+	srv.Close()
+	goes.Wait()
+	return nil
+}
+
 func peersAction(ctx *cli.Context) error {
 	fmt.Println("Peers from peers.cache")
 	gene := selectGenesis(ctx)
 	instanceDir := makeInstanceDir(ctx, gene)
 	peersCachePath := path.Join(instanceDir, "peers.cache")
-	nodes := make([]*discover.Node, 0)
-	if data, err := ioutil.ReadFile(peersCachePath); err != nil {
+	cache, err := loadPeersCache(peersCachePath)
+	if err != nil {
 		if !os.IsNotExist(err) {
 			fmt.Println("failed to load peers cache", "err", err)
 			return err
 		}
-	} else if err := rlp.DecodeBytes(data, &nodes); err != nil {
-		fmt.Println("failed to load peers cache", "err", err)
+		cache = &peersCache{}
 	}
-	for i, n := range nodes {
+	for i, n := range cache.Nodes {
 		fmt.Println(fmt.Sprintf("Node #%d: enode://%s@%s", i, n.ID, n.IP.String()))
 	}
+	for i, b := range cache.Bans {
+		fmt.Println(fmt.Sprintf("Banned #%d: %s until %s", i, b.ID, time.Unix(int64(b.Until), 0)))
+	}
 	fmt.Println("End.")
 	return nil
 }
 
 func defaultAction(ctx *cli.Context) error {
-	exitSignal := handleExitSignal()
+	// the node can also be shut down by admin service
+	exitSignal, shutdown := context.WithCancel(handleExitSignal())
+	defer shutdown()
 
 	defer func() { log.Info("exited") }()
 
@@ -224,7 +358,16 @@ func defaultAction(ctx *cli.Context) error {
 	defer func() { log.Info("closing log database..."); logDB.Close() }()
 
 	chain := initChain(gene, mainDB, logDB)
-	master, blsCommon := loadNodeMaster(ctx)
+	var (
+		master    *node.Master
+		blsCommon *consensus.BlsCommon
+		sgn       signer.Signer
+	)
+	if ctx.String(remoteSignerFlag.Name) != "" {
+		master, blsCommon, sgn = loadRemoteSigner(ctx)
+	} else {
+		master, blsCommon = loadNodeMaster(ctx)
+	}
 	pubkey, err := getNodeComplexPubKey(master, blsCommon)
 	if err != nil {
 		panic("could not load pubkey")
@@ -288,10 +431,38 @@ func defaultAction(ctx *cli.Context) error {
 	stateCreator := state.NewCreator(mainDB)
 	sc := script.NewScriptEngine(chain, stateCreator)
 	cons := consensus.NewConsensusReactor(ctx, chain, stateCreator, master.PrivateKey, master.PublicKey, magic, blsCommon, initDelegates)
+	if sgn == nil {
+		sgn = newLocalSigner(master, blsCommon, instanceDir)
+	}
+	cons.SetSigner(sgn)
+	cons.SetPMStateStore(consensus.NewPMStateStore(filepath.Join(instanceDir, "pacemaker-state.rlp")))
 
 	observeURL, observeSrvCloser := startObserveServer(ctx, cons, pubkey, p2pcom.comm, chain)
 	defer func() { log.Info("closing Observe Server ..."); observeSrvCloser() }()
 
+	metricsURL, metricsSrvCloser := startMetricsServer(ctx)
+	defer func() { log.Info("stopping metrics server..."); metricsSrvCloser() }()
+
+	adminURL, adminSrvCloser := startAdminServer(ctx, instanceDir, admin.Options{
+		Comm:        p2pcom.comm,
+		PowPool:     powPool,
+		Cons:        cons,
+		SetLogLevel: setLogLevel,
+		ReloadDelegates: func() error {
+			delegates, err := readDelegates(ctx, blsCommon)
+			if err != nil {
+				return err
+			}
+			printDelegates(delegates)
+			cons.SetInitDelegates(delegates)
+			return nil
+		},
+		AddPeer:    p2pcom.AddStaticPeer,
+		RemovePeer: p2pcom.RemoveStaticPeer,
+		Shutdown:   shutdown,
+	})
+	defer func() { log.Info("stopping admin server..."); adminSrvCloser() }()
+
 	//also create the POW components
 	// powR := pow.NewPowpoolReactor(chain, stateCreator, powpool)
 
@@ -299,7 +470,7 @@ func defaultAction(ctx *cli.Context) error {
 	genCloser := newKFrameGenerator(ctx, cons)
 	defer func() { log.Info("stopping kframe generator service ..."); genCloser() }()
 
-	printStartupMessage(topic, gene, chain, master, instanceDir, apiURL, powApiURL, observeURL)
+	printStartupMessage(topic, gene, chain, master, instanceDir, apiURL, powApiURL, observeURL, metricsURL, adminURL)
 
 	p2pcom.Start()
 	defer p2pcom.Stop()
@@ -317,6 +488,60 @@ func defaultAction(ctx *cli.Context) error {
 		Run(exitSignal)
 }
 
+func soloAction(ctx *cli.Context) error {
+	exitSignal := handleExitSignal()
+
+	defer func() { log.Info("exited") }()
+
+	initLogger(ctx)
+
+	gene := genesis.NewDevnet()
+	// init blockchain config
+	meter.InitBlockChainConfig(gene.ID(), "dev")
+
+	var mainDB *lvldb.LevelDB
+	var logDB *logdb.LogDB
+	var instanceDir string
+
+	if ctx.Bool(persistFlag.Name) {
+		instanceDir = makeInstanceDir(ctx, gene)
+		mainDB = openMainDB(ctx, instanceDir)
+		logDB = openLogDB(ctx, instanceDir)
+	} else {
+		instanceDir = "Memory"
+		mainDB = openMemMainDB()
+		logDB = openMemLogDB()
+	}
+	defer func() { log.Info("closing main database..."); mainDB.Close() }()
+	defer func() { log.Info("closing log database..."); logDB.Close() }()
+
+	chain := initChain(gene, mainDB, logDB)
+	stateCreator := state.NewCreator(mainDB)
+
+	txPool := txpool.New(chain, stateCreator, defaultTxPoolOptions)
+	defer func() { log.Info("closing tx pool..."); txPool.Close() }()
+
+	// script engine is required to execute staking/auction/accountlock clauses
+	script.NewScriptEngine(chain, stateCreator)
+
+	apiHandler, apiCloser := api.New(chain, stateCreator, txPool, logDB, solo.Communicator{}, ctx.String(apiCorsFlag.Name), uint32(ctx.Int(apiBacktraceLimitFlag.Name)), uint64(ctx.Int(apiCallGasLimitFlag.Name)), nil, "")
+	defer func() { log.Info("closing API..."); apiCloser() }()
+
+	apiURL, srvCloser := startAPIServer(ctx, apiHandler, chain.GenesisBlock().Header().ID())
+	defer func() { log.Info("stopping API server..."); srvCloser() }()
+
+	printSoloStartupMessage(gene, chain, instanceDir, apiURL)
+
+	return solo.New(chain,
+		stateCreator,
+		logDB,
+		txPool,
+		uint64(ctx.Int(gasLimitFlag.Name)),
+		ctx.Bool(onDemandFlag.Name),
+		time.Duration(ctx.Int(blockIntervalFlag.Name))*time.Second).
+		Run(exitSignal)
+}
+
 func newKFrameGenerator(ctx *cli.Context, cons *consensus.ConsensusReactor) func() {
 	done := make(chan int)
 	go func() {
@@ -354,69 +579,129 @@ func masterKeyAction(ctx *cli.Context) error {
 	}
 
 	if hasImportFlag {
-		if isatty.IsTerminal(os.Stdin.Fd()) {
-			fmt.Println("Input JSON keystore (end with ^d):")
-		}
-		keyjson, err := ioutil.ReadAll(os.Stdin)
-		if err != nil {
-			return err
-		}
+		return importMasterKeyAction(ctx)
+	}
+	return exportMasterKeyAction(ctx)
+}
 
-		if err := json.Unmarshal(keyjson, &map[string]interface{}{}); err != nil {
-			return errors.WithMessage(err, "unmarshal")
-		}
-		password, err := readPasswordFromNewTTY("Enter passphrase: ")
-		if err != nil {
-			return err
-		}
+func importMasterKeyAction(ctx *cli.Context) error {
+	if isatty.IsTerminal(os.Stdin.Fd()) {
+		fmt.Println("Input JSON keystore (end with ^d):")
+	}
+	keyjson, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		return err
+	}
 
-		key, err := keystore.DecryptKey(keyjson, password)
-		if err != nil {
+	password, err := readPassphrase(ctx, passwordFlag, "Enter passphrase: ")
+	if err != nil {
+		return err
+	}
+	master, public, err := decryptMasterKey(keyjson, password)
+	if err == errLegacyKeystore {
+		// the BLS key is the consensus identity, never replace it implicitly
+		if !ctx.Bool(newBlsKeyFlag.Name) {
+			return errors.Errorf("legacy keystore holds no BLS key, use --%s to generate a new one, which changes the consensus identity of the node", newBlsKeyFlag.Name)
+		}
+		// re-encrypted as master keystore with the same passphrase
+		if master, public, err = decryptLegacyKeystore(keyjson, password); err != nil {
 			return errors.WithMessage(err, "decrypt")
 		}
-
-		if err := crypto.SaveECDSA(masterKeyPath(ctx), key.PrivateKey); err != nil {
+		fmt.Fprintln(os.Stderr, "Legacy keystore holds no BLS key, a new BLS key pair is generated")
+		if keyjson, err = encryptMasterKey(master, public, password, keystore.StandardScryptN, keystore.StandardScryptP); err != nil {
 			return err
 		}
-		fmt.Println("Master key imported:", meter.Address(key.Address))
-		return nil
+	} else if err != nil {
+		return errors.WithMessage(err, "decrypt")
+	}
+	if err := checkMasterKey(master, public); err != nil {
+		return err
 	}
 
-	if hasExportFlag {
-		masterKey, err := loadOrGeneratePrivateKey(masterKeyPath(ctx))
+	makeDataDir(ctx)
+	if err := saveMasterKeystore(ctx, keyjson, master, public, ctx.Bool(forceFlag.Name)); err != nil {
+		return err
+	}
+	address, _ := addressOfPublicKey(public)
+	fmt.Println("Master key imported:", "0x"+address)
+	return nil
+}
+
+func exportMasterKeyAction(ctx *cli.Context) error {
+	var keyjson []byte
+	if path := masterKeystorePath(ctx); fileExists(path) {
+		// already encrypted, export as it is
+		content, err := ioutil.ReadFile(path)
 		if err != nil {
 			return err
 		}
-
-		password, err := readPasswordFromNewTTY("Enter passphrase: ")
-		if err != nil {
+		keyjson = content
+	} else if fileExists(masterKeyPath(ctx)) {
+		// never generate keys on export
+		keyLoader := NewKeyLoader(ctx)
+		if err := checkMasterKey(keyLoader.masterBytes, keyLoader.publicBytes); err != nil {
 			return err
 		}
-		if password == "" {
-			return errors.New("non-empty passphrase required")
+		password, err := readNewPassphrase(ctx)
+		if err != nil {
+			return err
 		}
-		confirm, err := readPasswordFromNewTTY("Confirm passphrase: ")
+		keyjson, err = encryptMasterKey(keyLoader.masterBytes, keyLoader.publicBytes, password, keystore.StandardScryptN, keystore.StandardScryptP)
 		if err != nil {
 			return err
 		}
+	} else {
+		return errors.New("no master key found")
+	}
 
-		if password != confirm {
-			return errors.New("passphrase confirmation mismatch")
-		}
+	if isatty.IsTerminal(os.Stdout.Fd()) {
+		fmt.Println("=== JSON keystore ===")
+	}
+	_, err := fmt.Println(string(keyjson))
+	return err
+}
+
+// rotateMasterKeyAction re-encrypts the master keystore with a new passphrase.
+// A plaintext master.key is migrated into the keystore and then removed.
+func rotateMasterKeyAction(ctx *cli.Context) error {
+	makeDataDir(ctx)
 
-		keyjson, err := keystore.EncryptKey(&keystore.Key{
-			PrivateKey: masterKey,
-			Address:    crypto.PubkeyToAddress(masterKey.PublicKey),
-			Id:         uuid.NewRandom()},
-			password, keystore.StandardScryptN, keystore.StandardScryptP)
+	var master, public []byte
+	if path := masterKeystorePath(ctx); fileExists(path) {
+		keyjson, err := ioutil.ReadFile(path)
 		if err != nil {
 			return err
 		}
-		if isatty.IsTerminal(os.Stdout.Fd()) {
-			fmt.Println("=== JSON keystore ===")
+		password, err := readPassphrase(ctx, passwordFlag, "Enter current passphrase: ")
+		if err != nil {
+			return err
+		}
+		if master, public, err = decryptMasterKey(keyjson, password); err != nil {
+			return errors.WithMessage(err, "decrypt")
 		}
-		_, err = fmt.Println(string(keyjson))
+	} else if fileExists(masterKeyPath(ctx)) {
+		keyLoader := NewKeyLoader(ctx)
+		if _, _, _, err := keyLoader.Load(); err != nil {
+			return err
+		}
+		master, public = keyLoader.masterBytes, keyLoader.publicBytes
+	} else {
+		return errors.New("no master key found")
+	}
+
+	password, err := readNewPassphrase(ctx)
+	if err != nil {
+		return err
+	}
+	keyjson, err := encryptMasterKey(master, public, password, keystore.StandardScryptN, keystore.StandardScryptP)
+	if err != nil {
+		return err
+	}
+	// the same key, re-encrypted
+	if err := saveMasterKeystore(ctx, keyjson, master, public, true); err != nil {
 		return err
 	}
+	address, _ := addressOfPublicKey(public)
+	fmt.Println("Master key encrypted:", "0x"+address)
 	return nil
 }
@@ -20,11 +20,13 @@ import (
 	"path"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	api_node "github.com/dfinlab/meter/api/node"
 	api_utils "github.com/dfinlab/meter/api/utils"
 	"github.com/dfinlab/meter/chain"
+	"github.com/dfinlab/meter/cmd/meter/admin"
 	"github.com/dfinlab/meter/cmd/meter/node"
 	"github.com/dfinlab/meter/cmd/meter/probe"
 	"github.com/dfinlab/meter/co"
@@ -35,9 +37,11 @@ import (
 	"github.com/dfinlab/meter/logdb"
 	"github.com/dfinlab/meter/lvldb"
 	"github.com/dfinlab/meter/meter"
+	"github.com/dfinlab/meter/metric"
 	"github.com/dfinlab/meter/p2psrv"
 	"github.com/dfinlab/meter/powpool"
 	"github.com/dfinlab/meter/preset"
+	"github.com/dfinlab/meter/signer"
 	"github.com/dfinlab/meter/state"
 	"github.com/dfinlab/meter/txpool"
 	"github.com/dfinlab/meter/types"
@@ -46,12 +50,14 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 	ethlog "github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/p2p/nat"
+	"github.com/ethereum/go-ethereum/p2p/netutil"
 	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/gorilla/mux"
 	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
 	cli "gopkg.in/urfave/cli.v1"
 
 	"github.com/ethereum/go-ethereum/p2p/discover"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 var (
@@ -60,13 +66,17 @@ var (
 
 func initLogger(ctx *cli.Context) {
 	logLevel := ctx.Int(verbosityFlag.Name)
-	log15.Root().SetHandler(log15.LvlFilterHandler(log15.Lvl(logLevel), log15.StderrHandler))
+	setLogLevel(log15.Lvl(logLevel))
 	// set go-ethereum log lvl to Warn
 	ethLogHandler := ethlog.NewGlogHandler(ethlog.StreamHandler(os.Stderr, ethlog.TerminalFormat(true)))
 	ethLogHandler.Verbosity(ethlog.LvlWarn)
 	ethlog.Root().SetHandler(ethLogHandler)
 }
 
+func setLogLevel(lvl log15.Lvl) {
+	log15.Root().SetHandler(log15.LvlFilterHandler(lvl, log15.StderrHandler))
+}
+
 func selectGenesis(ctx *cli.Context) *genesis.Genesis {
 	network := ctx.String(networkFlag.Name)
 	switch network {
@@ -101,6 +111,17 @@ func (d Delegate1) String() string {
 }
 
 func loadDelegates(ctx *cli.Context, blsCommon *consensus.BlsCommon) []*types.Delegate {
+	delegates, err := readDelegates(ctx, blsCommon)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+		return nil
+	}
+	return delegates
+}
+
+// readDelegates reads delegates from preset or delegates.json in data dir.
+func readDelegates(ctx *cli.Context, blsCommon *consensus.BlsCommon) (delegates []*types.Delegate, err error) {
 	delegates1 := make([]*Delegate1, 0)
 
 	// Hack for compile
@@ -116,19 +137,19 @@ func loadDelegates(ctx *cli.Context, blsCommon *consensus.BlsCommon) []*types.De
 		file, err := ioutil.ReadFile(filePath)
 		content = file
 		if err != nil {
-			fmt.Println("Unable load delegate file at", filePath, "error", err)
-			os.Exit(1)
-			return nil
+			return nil, errors.WithMessage(err, fmt.Sprintf("Unable load delegate file at %v", filePath))
 		}
 	}
-	err := json.Unmarshal(content, &delegates1)
-	if err != nil {
-		fmt.Println("Unable unmarshal delegate file, please check your config", "error", err)
-		os.Exit(1)
-		return nil
+	if err := json.Unmarshal(content, &delegates1); err != nil {
+		return nil, errors.WithMessage(err, "Unable unmarshal delegate file, please check your config")
 	}
 
-	delegates := make([]*types.Delegate, 0)
+	// splitPubKey panics on malformed keys
+	defer func() {
+		if e := recover(); e != nil {
+			delegates, err = nil, errors.New(fmt.Sprint(e))
+		}
+	}()
 	for _, d := range delegates1 {
 		// first part is ecdsa public, 2nd part is bls public key
 		pubKey, blsPub := splitPubKey(string(d.PubKey), blsCommon)
@@ -137,9 +158,7 @@ func loadDelegates(ctx *cli.Context, blsCommon *consensus.BlsCommon) []*types.De
 		if len(d.Address) != 0 {
 			addr, err = meter.ParseAddress(d.Address)
 			if err != nil {
-				fmt.Println("can't read address of delegates:", d.String(), "error", err)
-				os.Exit(1)
-				return nil
+				return nil, errors.WithMessage(err, fmt.Sprintf("can't read address of delegates: %v", d.String()))
 			}
 		} else {
 			// derive from public key
@@ -151,7 +170,7 @@ func loadDelegates(ctx *cli.Context, blsCommon *consensus.BlsCommon) []*types.De
 		dd.NetAddr = d.NetAddr
 		delegates = append(delegates, dd)
 	}
-	return delegates
+	return delegates, nil
 }
 
 func splitPubKey(comboPub string, blsCommon *consensus.BlsCommon) (*ecdsa.PublicKey, *bls.PublicKey) {
@@ -239,6 +258,7 @@ func openMainDB(ctx *cli.Context, dataDir string) *lvldb.LevelDB {
 	if err != nil {
 		fatal(fmt.Sprintf("open chain database [%v]: %v", dir, err))
 	}
+	metric.Register(db.StatsCollector("main"))
 	return db
 }
 
@@ -275,6 +295,10 @@ func masterKeyPath(ctx *cli.Context) string {
 	return filepath.Join(ctx.String("data-dir"), "master.key")
 }
 
+func masterKeystorePath(ctx *cli.Context) string {
+	return filepath.Join(ctx.String("data-dir"), "master.keystore")
+}
+
 func publicKeyPath(ctx *cli.Context) string {
 	return filepath.Join(ctx.String("data-dir"), "public.key")
 }
@@ -315,6 +339,18 @@ func discoServerParse(ctx *cli.Context) ([]*discover.Node, bool, error) {
 	return nodes, true, nil
 }
 
+func parseNodes(urls []string) ([]*discover.Node, error) {
+	nodes := make([]*discover.Node, 0, len(urls))
+	for _, url := range urls {
+		node, err := discover.ParseNode(url)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
 func loadNodeMaster(ctx *cli.Context) (*node.Master, *consensus.BlsCommon) {
 	if ctx.String(networkFlag.Name) == "dev" {
 		i := rand.Intn(len(genesis.DevAccounts()))
@@ -335,6 +371,61 @@ func loadNodeMaster(ctx *cli.Context) (*node.Master, *consensus.BlsCommon) {
 	return master, blsCommon
 }
 
+// loadRemoteSigner connects to the remote signer given by --remote-signer. The
+// node master and BLS common are made of the public keys only, private keys
+// are never loaded.
+func loadRemoteSigner(ctx *cli.Context) (*node.Master, *consensus.BlsCommon, signer.Signer) {
+	url := ctx.String(remoteSignerFlag.Name)
+	tokenPath := ctx.String(remoteSignerTokenFlag.Name)
+	if tokenPath == "" {
+		fatal("remote signer token is required, see --" + remoteSignerTokenFlag.Name)
+	}
+	token, err := ioutil.ReadFile(tokenPath)
+	if err != nil {
+		fatal("read remote signer token:", err)
+	}
+	remote, err := signer.NewRemote(url, strings.TrimSpace(string(token)))
+	if err != nil {
+		fatal(fmt.Sprintf("connect remote signer [%v]: %v", url, err))
+	}
+
+	paraBytes, err := hex.DecodeString(paraString)
+	if err != nil {
+		fatal("decode bls params:", err)
+	}
+	params, err := bls.ParamsFromBytes(paraBytes)
+	if err != nil {
+		fatal("decode bls params:", err)
+	}
+	pairing := bls.GenPairing(params)
+	systemBytes, err := hex.DecodeString(systemString)
+	if err != nil {
+		fatal("decode bls system:", err)
+	}
+	system, err := bls.SystemFromBytes(pairing, systemBytes)
+	if err != nil {
+		fatal("decode bls system:", err)
+	}
+	blsPubKey, err := system.PubKeyFromBytes(remote.BlsPublicKey())
+	if err != nil {
+		fatal("remote signer bls public key:", err)
+	}
+
+	master := &node.Master{PublicKey: remote.PublicKey(), Beneficiary: beneficiary(ctx)}
+	blsCommon := consensus.NewBlsCommonFromParams(blsPubKey, bls.PrivateKey{}, system, params, pairing)
+	return master, blsCommon, remote
+}
+
+// newLocalSigner creates the signer of the local master key, guarded by the sign
+// state persisted in instance dir.
+func newLocalSigner(master *node.Master, blsCommon *consensus.BlsCommon, instanceDir string) signer.Signer {
+	guard, err := signer.NewGuard(filepath.Join(instanceDir, "sign-state.json"))
+	if err != nil {
+		fatal("load sign state:", err)
+	}
+	return signer.NewLocal(master.PrivateKey, *blsCommon.GetSystem(), blsCommon.GetPrivKey(), *blsCommon.GetPubKey(), guard)
+}
+
 func getNodeComplexPubKey(master *node.Master, blsCommon *consensus.BlsCommon) (string, error) {
 	ecdsaPubBytes := crypto.FromECDSAPub(master.PublicKey)
 	ecdsaPubB64 := b64.StdEncoding.EncodeToString(ecdsaPubBytes)
@@ -346,10 +437,48 @@ func getNodeComplexPubKey(master *node.Master, blsCommon *consensus.BlsCommon) (
 	return pub, nil
 }
 
+// peersCache is the content of peers.cache.
+type peersCache struct {
+	Nodes p2psrv.Nodes
+	Bans  []*comm.BannedPeer
+}
+
+// loadPeersCache loads peers cache from file. The legacy format, which contains only nodes, is also accepted.
+func loadPeersCache(path string) (*peersCache, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cache peersCache
+	if err := rlp.DecodeBytes(data, &cache); err != nil {
+		cache = peersCache{}
+		if err := rlp.DecodeBytes(data, &cache.Nodes); err != nil {
+			return nil, err
+		}
+	}
+	return &cache, nil
+}
+
+// loadStaticPeers loads static peers added at runtime, which are saved in enode format.
+func loadStaticPeers(path string) ([]*discover.Node, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var urls []string
+	if err := json.Unmarshal(data, &urls); err != nil {
+		return nil, err
+	}
+	return parseNodes(urls)
+}
+
 type p2pComm struct {
-	comm           *comm.Communicator
-	p2pSrv         *p2psrv.Server
-	peersCachePath string
+	comm            *comm.Communicator
+	p2pSrv          *p2psrv.Server
+	peersCachePath  string
+	staticPeersPath string
+	staticPeers     map[discover.NodeID]*discover.Node // static peers added at runtime
+	staticPeersLock sync.Mutex
 }
 
 func newP2PComm(ctx *cli.Context, chain *chain.Chain, txPool *txpool.TxPool, instanceDir string, powPool *powpool.PowPool, magic [4]byte) *p2pComm {
@@ -372,6 +501,35 @@ func newP2PComm(ctx *cli.Context, chain *chain.Chain, txPool *txpool.TxPool, ins
 		os.Exit(1)
 	}
 
+	staticNodes, err := parseNodes(ctx.StringSlice(staticPeersFlag.Name))
+	if err != nil {
+		cli.ShowAppHelp(ctx)
+		fmt.Println("parse -static-peers flag:", err)
+		os.Exit(1)
+	}
+
+	trustedNodes, err := parseNodes(ctx.StringSlice(trustedPeersFlag.Name))
+	if err != nil {
+		cli.ShowAppHelp(ctx)
+		fmt.Println("parse -trusted-peers flag:", err)
+		os.Exit(1)
+	}
+
+	var netRestrict *netutil.Netlist
+	if restrict := ctx.String(netRestrictFlag.Name); restrict != "" {
+		netRestrict, err = netutil.ParseNetlist(restrict)
+		if err != nil {
+			cli.ShowAppHelp(ctx)
+			fmt.Println("parse -netrestrict flag:", err)
+			os.Exit(1)
+		}
+	}
+
+	whitelistOnly := ctx.Bool(whitelistOnlyFlag.Name)
+	if whitelistOnly && len(staticNodes) == 0 && len(trustedNodes) == 0 {
+		log.Warn("whitelist-only mode without static or trusted peers, no peer will be connected")
+	}
+
 	// if the discoverServerFlag is not set, use default hardcoded nodes
 	var BootstrapNodes []*discover.Node
 	if overrided == true {
@@ -388,16 +546,35 @@ func newP2PComm(ctx *cli.Context, chain *chain.Chain, txPool *txpool.TxPool, ins
 		BootstrapNodes: BootstrapNodes,
 		NAT:            nat,
 		NoDiscovery:    ctx.Bool("no-discover"),
+		NetRestrict:    netRestrict,
+		StaticNodes:    staticNodes,
+		TrustedNodes:   trustedNodes,
+		WhitelistOnly:  whitelistOnly,
+	}
+
+	staticPeersPath := filepath.Join(instanceDir, "static-peers.json")
+	staticPeers := make(map[discover.NodeID]*discover.Node)
+	if nodes, err := loadStaticPeers(staticPeersPath); err != nil {
+		if !os.IsNotExist(err) {
+			log.Warn("failed to load static peers", "err", err)
+		}
+	} else {
+		for _, node := range nodes {
+			staticPeers[node.ID] = node
+		}
+		opts.StaticNodes = append(opts.StaticNodes, nodes...)
 	}
 
 	peersCachePath := filepath.Join(instanceDir, "peers.cache")
 
-	if data, err := ioutil.ReadFile(peersCachePath); err != nil {
+	var bans []*comm.BannedPeer
+	if cache, err := loadPeersCache(peersCachePath); err != nil {
 		if !os.IsNotExist(err) {
 			log.Warn("failed to load peers cache", "err", err)
 		}
-	} else if err := rlp.DecodeBytes(data, &opts.KnownNodes); err != nil {
-		log.Warn("failed to load peers cache", "err", err)
+	} else {
+		opts.KnownNodes = cache.Nodes
+		bans = cache.Bans
 	}
 
 	topic := ctx.String("disco-topic")
@@ -411,13 +588,50 @@ func newP2PComm(ctx *cli.Context, chain *chain.Chain, txPool *txpool.TxPool, ins
 	}
 	opts.KnownNodes = append(opts.KnownNodes, validNodes...)
 
+	communicator := comm.New(chain, txPool, powPool, topic, magic)
+	communicator.LoadBannedPeers(bans)
+
 	return &p2pComm{
-		comm:           comm.New(chain, txPool, powPool, topic, magic),
-		p2pSrv:         p2psrv.New(opts),
-		peersCachePath: peersCachePath,
+		comm:            communicator,
+		p2pSrv:          p2psrv.New(opts),
+		peersCachePath:  peersCachePath,
+		staticPeersPath: staticPeersPath,
+		staticPeers:     staticPeers,
 	}
 }
 
+// AddStaticPeer keeps the node connected, it's saved to be restored on restart.
+func (p *p2pComm) AddStaticPeer(node *discover.Node) error {
+	p.staticPeersLock.Lock()
+	defer p.staticPeersLock.Unlock()
+
+	p.p2pSrv.AddStatic(node)
+	p.staticPeers[node.ID] = node
+	return p.saveStaticPeers()
+}
+
+// RemoveStaticPeer disconnects the node, and removes it from saved static peers.
+func (p *p2pComm) RemoveStaticPeer(node *discover.Node) error {
+	p.staticPeersLock.Lock()
+	defer p.staticPeersLock.Unlock()
+
+	p.p2pSrv.RemoveStatic(node)
+	delete(p.staticPeers, node.ID)
+	return p.saveStaticPeers()
+}
+
+func (p *p2pComm) saveStaticPeers() error {
+	urls := make([]string, 0, len(p.staticPeers))
+	for _, node := range p.staticPeers {
+		urls = append(urls, node.String())
+	}
+	data, err := json.MarshalIndent(urls, "", "  ")
+	if err != nil {
+		return err
+	}
+	return errors.WithMessage(ioutil.WriteFile(p.staticPeersPath, data, 0600), "save static peers")
+}
+
 func (p *p2pComm) Start() {
 	log.Info("starting P2P networking")
 	if err := p.p2pSrv.Start(p.comm.Protocols()); err != nil {
@@ -434,8 +648,10 @@ func (p *p2pComm) Stop() {
 	p.p2pSrv.Stop()
 
 	log.Info("saving peers cache...")
-	nodes := p.p2pSrv.KnownNodes()
-	data, err := rlp.EncodeToBytes(nodes)
+	data, err := rlp.EncodeToBytes(&peersCache{
+		Nodes: p.p2pSrv.KnownNodes(),
+		Bans:  p.comm.BannedPeers(),
+	})
 	if err != nil {
 		log.Warn("failed to encode cached peers", "err", err)
 		return
@@ -474,7 +690,7 @@ func startObserveServer(ctx *cli.Context, cons *consensus.ConsensusReactor, comp
 	}
 	probe := &probe.Probe{cons, complexPubkey, chain, fullVersion(), nw}
 	mux := http.NewServeMux()
-	mux.Handle("/metrics", promhttp.Handler())
+	mux.Handle("/metrics", metric.Handler())
 	mux.HandleFunc("/probe", probe.HandleProbe)
 	mux.HandleFunc("/probe/version", probe.HandleVersion)
 	mux.HandleFunc("/probe/pubkey", probe.HandlePubkey)
@@ -607,6 +823,100 @@ func startPowAPIServer(ctx *cli.Context, handler http.Handler) (string, func())
 	}
 }
 
+func startMetricsServer(ctx *cli.Context) (string, func()) {
+	addr := ctx.String(metricsAddrFlag.Name)
+	if addr == "" {
+		return "disabled", func() {}
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		fatal(fmt.Sprintf("listen metrics addr [%v]: %v", addr, err))
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metric.Handler())
+
+	srv := &http.Server{Handler: mux}
+	var goes co.Goes
+	goes.Go(func() {
+		err := srv.Serve(listener)
+		if err != nil {
+			if err != http.ErrServerClosed {
+				fmt.Println("metrics server stopped, error:", err)
+			}
+		}
+	})
+	return "http://" + listener.Addr().String() + "/metrics", func() {
+		err := srv.Close()
+		if err != nil {
+			fmt.Println("can't close metrics http service, error:", err)
+		}
+		goes.Wait()
+	}
+}
+
+// startAdminServer starts the admin service on a TCP address, or a unix socket if the address
+// is prefixed with "unix:". Requests are authenticated by the token stored in data dir.
+func startAdminServer(ctx *cli.Context, instanceDir string, opts admin.Options) (string, func()) {
+	addr := ctx.String(adminAddrFlag.Name)
+	if addr == "" {
+		return "disabled", func() {}
+	}
+
+	tokenPath := filepath.Join(ctx.String(dataDirFlag.Name), "admin.token")
+	token, err := loadOrGenerateToken(tokenPath)
+	if err != nil {
+		fatal("load or generate admin token:", err)
+	}
+	opts.Token = token
+
+	auditHandler, err := log15.FileHandler(filepath.Join(instanceDir, "admin-audit.log"), log15.LogfmtFormat())
+	if err != nil {
+		fatal("open admin audit log:", err)
+	}
+
+	var (
+		listener net.Listener
+		url      string
+	)
+	if strings.HasPrefix(addr, "unix:") {
+		sockPath := strings.TrimPrefix(addr, "unix:")
+		// remove the stale socket left by unclean exit
+		os.Remove(sockPath)
+		if listener, err = net.Listen("unix", sockPath); err == nil {
+			err = os.Chmod(sockPath, 0600)
+		}
+		url = addr
+	} else {
+		if listener, err = net.Listen("tcp", addr); err == nil {
+			url = "http://" + listener.Addr().String() + "/"
+		}
+	}
+	if err != nil {
+		fatal(fmt.Sprintf("listen admin addr [%v]: %v", addr, err))
+	}
+
+	router := mux.NewRouter()
+	admin.New(opts, auditHandler).Mount(router, "/")
+
+	srv := &http.Server{Handler: requestBodyLimit(router)}
+	var goes co.Goes
+	goes.Go(func() {
+		err := srv.Serve(listener)
+		if err != nil {
+			if err != http.ErrServerClosed {
+				fmt.Println("admin server stopped, error:", err)
+			}
+		}
+	})
+	return fmt.Sprintf("%v (token in %v)", url, tokenPath), func() {
+		err := srv.Close()
+		if err != nil {
+			fmt.Println("can't close admin http service, error:", err)
+		}
+		goes.Wait()
+	}
+}
+
 func printStartupMessage(
 	topic string,
 	gene *genesis.Genesis,
@@ -616,6 +926,8 @@ func printStartupMessage(
 	apiURL string,
 	powApiURL string,
 	observeURL string,
+	metricsURL string,
+	adminURL string,
 ) {
 	bestBlock := chain.BestBlock()
 
@@ -631,6 +943,8 @@ func printStartupMessage(
     API portal      [ %v ]
     POW API portal  [ %v ]
     Observe service [ %v ]
+    Metrics service [ %v ]
+    Admin service   [ %v ]
 `,
 		common.MakeName("Meter", fullVersion()),
 		topic,
@@ -646,7 +960,33 @@ func printStartupMessage(
 			return master.Beneficiary.String()
 		}(),
 		dataDir,
-		apiURL, powApiURL, observeURL)
+		apiURL, powApiURL, observeURL, metricsURL, adminURL)
+}
+
+func printSoloStartupMessage(
+	gene *genesis.Genesis,
+	chain *chain.Chain,
+	dataDir string,
+	apiURL string,
+) {
+	bestBlock := chain.BestBlock()
+
+	info := fmt.Sprintf(`Starting %v
+    Network     [ %v %v ]
+    Best block  [ %v #%v @%v ]
+    Data dir    [ %v ]
+    API portal  [ %v ]
+`,
+		common.MakeName("Meter solo", fullVersion()),
+		gene.ID(), gene.Name(),
+		bestBlock.Header().ID(), bestBlock.Header().Number(), time.Unix(int64(bestBlock.Header().Timestamp()), 0),
+		dataDir,
+		apiURL)
+
+	for i, a := range genesis.DevAccounts() {
+		info += fmt.Sprintf("    Account #%d [ %v ] PrivateKey [ %x ]\n", i, a.Address, crypto.FromECDSA(a.PrivateKey))
+	}
+	fmt.Print(info)
 }
 
 func openMemMainDB() *lvldb.LevelDB {
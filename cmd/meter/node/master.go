@@ -12,6 +12,7 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
+// Master is the node master, PrivateKey is nil if the key is held by remote signer.
 type Master struct {
 	PrivateKey  *ecdsa.PrivateKey
 	PublicKey   *ecdsa.PublicKey
@@ -19,5 +20,8 @@ type Master struct {
 }
 
 func (m *Master) Address() meter.Address {
+	if m.PrivateKey == nil {
+		return meter.Address(crypto.PubkeyToAddress(*m.PublicKey))
+	}
 	return meter.Address(crypto.PubkeyToAddress(m.PrivateKey.PublicKey))
 }
@@ -276,14 +276,19 @@ func (n *Node) processBlock(blk *block.Block, stats *blockStats) (bool, error) {
 		switch {
 		case consensus.IsKnownBlock(err):
 			stats.UpdateIgnored(1)
+			blockProcessedCounter.WithLabelValues("ignored").Inc()
 			return false, nil
 		case consensus.IsFutureBlock(err) || consensus.IsParentMissing(err):
 			stats.UpdateQueued(1)
+			blockProcessedCounter.WithLabelValues("queued").Inc()
 		case consensus.IsCritical(err):
 			msg := fmt.Sprintf(`failed to process block due to consensus failure \n%v\n`, blk.Header())
 			log.Error(msg, "err", err)
+			blockProcessedCounter.WithLabelValues("failed").Inc()
+			n.comm.ReportBadBlock(blk.Header().ID())
 		default:
 			log.Error("failed to process block", "err", err)
+			blockProcessedCounter.WithLabelValues("failed").Inc()
 		}
 		return false, err
 	}
@@ -304,6 +309,9 @@ func (n *Node) processBlock(blk *block.Block, stats *blockStats) (bool, error) {
 	}
 	commitElapsed := mclock.Now() - startTime - execElapsed
 	stats.UpdateProcessed(1, len(receipts), execElapsed, commitElapsed, blk.Header().GasUsed())
+	blockExecDurationHist.Observe(time.Duration(execElapsed).Seconds())
+	blockCommitDurationHist.Observe(time.Duration(commitElapsed).Seconds())
+	blockProcessedCounter.WithLabelValues("processed").Inc()
 	n.processFork(fork)
 
 	// XXX: shortcut to refresh height
@@ -0,0 +1,14 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package node
+
+import "github.com/dfinlab/meter/metric"
+
+var (
+	blockExecDurationHist   = metric.NewHistogram("block_exec_duration_seconds", "Duration of executing received blocks")
+	blockCommitDurationHist = metric.NewHistogram("block_commit_duration_seconds", "Duration of committing received blocks")
+	blockProcessedCounter   = metric.NewCounterVec("blocks_processed_total", "Counter of received blocks by result", "result")
+)
@@ -108,6 +108,8 @@ type PeerStats struct {
 	NetAddr     string        `json:"netAddr"`
 	Inbound     bool          `json:"inbound"`
 	Duration    uint64        `json:"duration"`
+	Score       int           `json:"score"`
+	Latency     uint64        `json:"latency"`
 }
 
 func ConvertPeersStats(ss []*comm.PeerStats) []*PeerStats {
@@ -124,6 +126,8 @@ func ConvertPeersStats(ss []*comm.PeerStats) []*PeerStats {
 			NetAddr:     peerStats.NetAddr,
 			Inbound:     peerStats.Inbound,
 			Duration:    peerStats.Duration,
+			Score:       peerStats.Score,
+			Latency:     peerStats.Latency,
 		}
 	}
 	return peersStats
@@ -0,0 +1,233 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package solo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/dfinlab/meter/block"
+	"github.com/dfinlab/meter/chain"
+	"github.com/dfinlab/meter/co"
+	"github.com/dfinlab/meter/comm"
+	"github.com/dfinlab/meter/genesis"
+	"github.com/dfinlab/meter/logdb"
+	"github.com/dfinlab/meter/packer"
+	"github.com/dfinlab/meter/state"
+	"github.com/dfinlab/meter/tx"
+	"github.com/dfinlab/meter/txpool"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/mclock"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
+)
+
+var log = log15.New("pkg", "solo")
+
+// Solo mode is the standalone client without p2p server, pow node or committee.
+// Blocks are sealed by the first dev account, either periodically or on
+// arrival of each pending tx.
+type Solo struct {
+	chain    *chain.Chain
+	txPool   *txpool.TxPool
+	packer   *packer.Packer
+	logDB    *logdb.LogDB
+	onDemand bool
+	interval time.Duration
+}
+
+// New returns Solo instance.
+func New(
+	chain *chain.Chain,
+	stateCreator *state.Creator,
+	logDB *logdb.LogDB,
+	txPool *txpool.TxPool,
+	gasLimit uint64,
+	onDemand bool,
+	interval time.Duration,
+) *Solo {
+	master := genesis.DevAccounts()[0].Address
+	p := packer.New(chain, stateCreator, master, &master)
+	if gasLimit != 0 {
+		p.SetTargetGasLimit(gasLimit)
+	}
+	return &Solo{
+		chain:    chain,
+		txPool:   txPool,
+		packer:   p,
+		logDB:    logDB,
+		onDemand: onDemand,
+		interval: interval,
+	}
+}
+
+// Run runs the packer for solo.
+func (s *Solo) Run(ctx context.Context) error {
+	var goes co.Goes
+
+	defer func() {
+		<-ctx.Done()
+		goes.Wait()
+	}()
+
+	goes.Go(func() {
+		s.loop(ctx)
+	})
+
+	log.Info("prepared to pack block")
+	return nil
+}
+
+func (s *Solo) loop(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	var scope event.SubscriptionScope
+	defer scope.Close()
+
+	txEvCh := make(chan *txpool.TxEvent, 10)
+	scope.Track(s.txPool.SubscribeTxEvent(txEvCh))
+
+	// always seal the first block, so the chain head is fresh and the tx pool
+	// starts to treat the chain as synced
+	if err := s.packing(nil, false); err != nil {
+		log.Error("failed to pack block", "err", err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("stopping interval packing service......")
+			return
+		case txEv := <-txEvCh:
+			newTx := txEv.Tx
+			origin, _ := newTx.Signer()
+			log.Info("new Tx", "id", newTx.ID(), "origin", origin)
+			if s.onDemand {
+				if err := s.packing(s.pendingTxs(), true); err != nil {
+					log.Error("failed to pack block", "err", err)
+				}
+			}
+		case <-ticker.C:
+			if s.onDemand {
+				continue
+			}
+			if err := s.packing(s.pendingTxs(), false); err != nil {
+				log.Error("failed to pack block", "err", err)
+			}
+		}
+	}
+}
+
+// pendingTxs returns all txs in pool ordered by nonce. The executables of pool
+// are only refreshed by its housekeeping, which is too late for on-demand sealing,
+// so non-executable ones are left to be filtered out by the packing flow.
+func (s *Solo) pendingTxs() tx.Transactions {
+	txs := s.txPool.Dump()
+	sort.Slice(txs, func(i, j int) bool {
+		return txs[i].Nonce() < txs[j].Nonce()
+	})
+	return txs
+}
+
+func (s *Solo) packing(pendingTxs tx.Transactions, skipEmpty bool) error {
+	best := s.chain.BestBlock()
+	var txsToRemove []*tx.Transaction
+	defer func() {
+		for _, tx := range txsToRemove {
+			s.txPool.Remove(tx.ID())
+		}
+	}()
+
+	now := uint64(time.Now().Unix())
+	if now <= best.Header().Timestamp() {
+		now = best.Header().Timestamp() + 1
+	}
+
+	master := genesis.DevAccounts()[0]
+	gasLimit := s.packer.GasLimit(best.Header().GasLimit())
+	flow, err := s.packer.Mock(best.Header(), now, gasLimit, &master.Address)
+	if err != nil {
+		return errors.WithMessage(err, "mock packer")
+	}
+
+	startTime := mclock.Now()
+	for _, tx := range pendingTxs {
+		if err := flow.Adopt(tx); err != nil {
+			if packer.IsGasLimitReached(err) {
+				break
+			}
+			if packer.IsTxNotAdoptableNow(err) {
+				continue
+			}
+		}
+		txsToRemove = append(txsToRemove, tx)
+	}
+
+	b, stage, receipts, err := flow.Pack(master.PrivateKey, block.BLOCK_TYPE_M_BLOCK, best.Header().LastKBlockHeight())
+	if err != nil {
+		return errors.WithMessage(err, "pack")
+	}
+	execElapsed := mclock.Now() - startTime
+
+	// if there is no tx packed in the on-demand mode then skip
+	if skipEmpty && len(b.Transactions()) == 0 {
+		return nil
+	}
+
+	// solo has no committee, so the block carries a QC that simply justifies its parent
+	b.SetMagic(block.BlockMagicVersion1)
+	b.SetQC(&block.QuorumCert{
+		QCHeight: best.Header().Number(),
+		QCRound:  best.Header().Number(),
+		EpochID:  best.QC.EpochID,
+	})
+
+	if _, err := stage.Commit(); err != nil {
+		return errors.WithMessage(err, "commit state")
+	}
+
+	// ignore fork when solo
+	if _, err := s.chain.AddBlock(b, receipts, true); err != nil {
+		return errors.WithMessage(err, "commit block")
+	}
+
+	batch := s.logDB.Prepare(b.Header())
+	for i, tx := range b.Transactions() {
+		origin, _ := tx.Signer()
+		txBatch := batch.ForTransaction(tx.ID(), origin)
+		for _, output := range receipts[i].Outputs {
+			txBatch.Insert(output.Events, output.Transfers)
+		}
+	}
+	if err := batch.Commit(); err != nil {
+		return errors.WithMessage(err, "commit log")
+	}
+
+	commitElapsed := mclock.Now() - startTime - execElapsed
+
+	blockID := b.Header().ID()
+	log.Info("📦 new block packed",
+		"txs", len(receipts),
+		"mgas", float64(b.Header().GasUsed())/1000/1000,
+		"et", fmt.Sprintf("%v|%v", common.PrettyDuration(execElapsed), common.PrettyDuration(commitElapsed)),
+		"id", fmt.Sprintf("[#%v…%x]", block.Number(blockID), blockID[28:]),
+	)
+	log.Debug(b.String())
+
+	return nil
+}
+
+// Communicator in solo is a fake one just for api handler.
+type Communicator struct{}
+
+// PeersStats returns nil since solo doesn't join p2p network.
+func (c Communicator) PeersStats() []*comm.PeerStats {
+	return nil
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package solo
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/dfinlab/meter/block"
+	"github.com/dfinlab/meter/chain"
+	"github.com/dfinlab/meter/genesis"
+	"github.com/dfinlab/meter/logdb"
+	"github.com/dfinlab/meter/lvldb"
+	"github.com/dfinlab/meter/meter"
+	"github.com/dfinlab/meter/state"
+	"github.com/dfinlab/meter/tx"
+	"github.com/dfinlab/meter/txpool"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestSolo(t *testing.T, onDemand bool) (*Solo, func()) {
+	kv, _ := lvldb.NewMem()
+	stateCreator := state.NewCreator(kv)
+	b0, _, err := genesis.NewDevnet().Build(stateCreator)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := chain.New(kv, b0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logDB, err := logdb.NewMem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := txpool.New(c, stateCreator, txpool.Options{Limit: 10000, LimitPerAccount: 16, MaxLifetime: 10 * time.Minute})
+
+	return New(c, stateCreator, logDB, pool, 0, onDemand, time.Second), func() {
+		pool.Close()
+		logDB.Close()
+	}
+}
+
+func newTestTx(t *testing.T, s *Solo, nonce uint64, blockRef uint32) *tx.Transaction {
+	to := meter.BytesToAddress([]byte("to"))
+	trx := new(tx.Builder).
+		ChainTag(s.chain.Tag()).
+		GasPriceCoef(1).
+		Expiration(100).
+		Gas(21000).
+		Nonce(nonce).
+		Clause(tx.NewClause(&to).WithValue(big.NewInt(10000))).
+		BlockRef(tx.NewBlockRef(blockRef)).
+		Build()
+
+	sig, err := crypto.Sign(trx.SigningHash().Bytes(), genesis.DevAccounts()[1].PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return trx.WithSignature(sig)
+}
+
+func TestSoloPackingOnDemand(t *testing.T) {
+	s, closeSolo := newTestSolo(t, true)
+	defer closeSolo()
+
+	// the first block is always sealed, even empty
+	assert.Nil(t, s.packing(nil, false))
+	assert.Equal(t, uint32(1), s.chain.BestBlock().Header().Number())
+
+	// nothing pending, skipped
+	assert.Nil(t, s.packing(s.pendingTxs(), true))
+	assert.Equal(t, uint32(1), s.chain.BestBlock().Header().Number())
+
+	trx := newTestTx(t, s, 1, 0)
+	future := newTestTx(t, s, 2, 100)
+	assert.Nil(t, s.txPool.Add(trx))
+	assert.Nil(t, s.txPool.Add(future))
+
+	assert.Nil(t, s.packing(s.pendingTxs(), true))
+	best := s.chain.BestBlock()
+	assert.Equal(t, uint32(2), best.Header().Number())
+	assert.Equal(t, tx.Transactions{trx}, best.Transactions())
+
+	// the tx not adoptable yet stays in the pool
+	assert.Equal(t, tx.Transactions{future}, s.txPool.Dump())
+
+	// still nothing adoptable, skipped
+	assert.Nil(t, s.packing(s.pendingTxs(), true))
+	assert.Equal(t, uint32(2), s.chain.BestBlock().Header().Number())
+}
+
+func TestSoloPackingQC(t *testing.T) {
+	s, closeSolo := newTestSolo(t, false)
+	defer closeSolo()
+
+	for i := 0; i < 3; i++ {
+		parent := s.chain.BestBlock()
+		assert.Nil(t, s.packing(nil, false))
+
+		best := s.chain.BestBlock()
+		assert.Equal(t, parent.Header().ID(), best.Header().ParentID())
+		assert.Equal(t, block.BlockMagicVersion1, best.Magic)
+		assert.Equal(t, parent.Header().Number(), best.QC.QCHeight)
+		assert.Equal(t, parent.Header().Number(), best.QC.QCRound)
+		assert.Equal(t, parent.QC.EpochID, best.QC.EpochID)
+	}
+}
+
+func TestSoloPackingLogs(t *testing.T) {
+	s, closeSolo := newTestSolo(t, true)
+	defer closeSolo()
+
+	trx := newTestTx(t, s, 1, 0)
+	assert.Nil(t, s.txPool.Add(trx))
+	assert.Nil(t, s.packing(s.pendingTxs(), true))
+
+	best := s.chain.BestBlock()
+	receipt, err := s.chain.GetTransactionReceipt(best.Header().ID(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, receipt.Reverted)
+
+	txID := trx.ID()
+	transfers, err := s.logDB.FilterTransfers(context.Background(), &logdb.TransferFilter{TxID: &txID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assert.Equal(t, 1, len(transfers)) {
+		assert.Equal(t, best.Header().Number(), transfers[0].BlockNumber)
+		assert.Equal(t, genesis.DevAccounts()[1].Address, transfers[0].TxOrigin)
+		assert.Equal(t, big.NewInt(10000), transfers[0].Amount)
+	}
+}
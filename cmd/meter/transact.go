@@ -0,0 +1,318 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strings"
+
+	"github.com/dfinlab/meter/meter"
+	"github.com/dfinlab/meter/script/staking"
+	"github.com/dfinlab/meter/sdk"
+	"github.com/dfinlab/meter/tx"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+var transactFlags = []cli.Flag{
+	apiURLFlag,
+	keystoreFlag,
+	keyPasswordFlag,
+	dryRunFlag,
+}
+
+var stakingCommand = cli.Command{
+	Name:  "staking",
+	Usage: "send staking transactions",
+	Subcommands: []cli.Command{
+		{
+			Name:   "bond",
+			Usage:  "create a bucket, voting for the candidate if given",
+			Flags:  append([]cli.Flag{amountFlag, tokenFlag, lockOptionFlag, candidateFlag}, transactFlags...),
+			Action: stakingBondAction,
+		},
+		{
+			Name:   "unbond",
+			Usage:  "unbond the bucket, it's released after the lock time",
+			Flags:  append([]cli.Flag{bucketFlag}, transactFlags...),
+			Action: stakingUnbondAction,
+		},
+		{
+			Name:  "candidate",
+			Usage: "list the account as candidate with a forever locked bucket",
+			Flags: append([]cli.Flag{amountFlag, tokenFlag, candNameFlag, candPubKeyFlag, candIPFlag, candPortFlag,
+				commissionFlag, descriptionFlag, websiteFlag, contactFlag}, transactFlags...),
+			Action: stakingCandidateAction,
+		},
+		{
+			Name:   "delegate",
+			Usage:  "vote for the candidate with the bucket",
+			Flags:  append([]cli.Flag{bucketFlag, candidateFlag}, transactFlags...),
+			Action: stakingDelegateAction,
+		},
+		{
+			Name:   "undelegate",
+			Usage:  "withdraw the vote of the bucket",
+			Flags:  append([]cli.Flag{bucketFlag}, transactFlags...),
+			Action: stakingUndelegateAction,
+		},
+	},
+}
+
+var auctionCommand = cli.Command{
+	Name:  "auction",
+	Usage: "send auction transactions",
+	Subcommands: []cli.Command{
+		{
+			Name:   "bid",
+			Usage:  "bid MTR in the current auction",
+			Flags:  append([]cli.Flag{amountFlag}, transactFlags...),
+			Action: auctionBidAction,
+		},
+		{
+			Name:   "cancel",
+			Usage:  "cancel the bid within the cancel window",
+			Flags:  append([]cli.Flag{auctionIDFlag, bidNonceFlag}, transactFlags...),
+			Action: auctionCancelAction,
+		},
+	},
+}
+
+func stakingBondAction(ctx *cli.Context) error {
+	key, holder, err := loadAccountKey(ctx)
+	if err != nil {
+		return err
+	}
+	amount, err := parseAmount(ctx)
+	if err != nil {
+		return err
+	}
+	token, err := parseToken(ctx)
+	if err != nil {
+		return err
+	}
+	var candidate meter.Address
+	if s := ctx.String(candidateFlag.Name); s != "" {
+		if candidate, err = meter.ParseAddress(s); err != nil {
+			return errors.WithMessage(err, "candidate")
+		}
+	}
+	body := sdk.Bound(holder, candidate, amount, token, uint32(ctx.Uint(lockOptionFlag.Name)))
+	return transactStaking(ctx, key, body)
+}
+
+func stakingUnbondAction(ctx *cli.Context) error {
+	key, holder, err := loadAccountKey(ctx)
+	if err != nil {
+		return err
+	}
+	bucket, err := loadBucket(ctx, holder)
+	if err != nil {
+		return err
+	}
+	body := sdk.Unbound(holder, bucket.BucketID, bucket.Value, bucket.Token)
+	return transactStaking(ctx, key, body)
+}
+
+func stakingCandidateAction(ctx *cli.Context) error {
+	key, addr, err := loadAccountKey(ctx)
+	if err != nil {
+		return err
+	}
+	amount, err := parseAmount(ctx)
+	if err != nil {
+		return err
+	}
+	token, err := parseToken(ctx)
+	if err != nil {
+		return err
+	}
+	commission, ok := new(big.Rat).SetString(ctx.String(commissionFlag.Name))
+	if !ok {
+		return errors.New("invalid commission")
+	}
+	// commission is in the unit of 1e09, 1e07 is 1%
+	commission.Mul(commission, new(big.Rat).SetInt64(1e07))
+	if !commission.IsInt() || commission.Sign() < 0 || !commission.Num().IsUint64() {
+		return errors.New("invalid commission")
+	}
+
+	var meta *staking.CandidateMeta
+	if ctx.String(descriptionFlag.Name) != "" || ctx.String(websiteFlag.Name) != "" || ctx.String(contactFlag.Name) != "" {
+		meta = &staking.CandidateMeta{
+			Description: []byte(ctx.String(descriptionFlag.Name)),
+			Website:     []byte(ctx.String(websiteFlag.Name)),
+			Contact:     []byte(ctx.String(contactFlag.Name)),
+		}
+	}
+	body, err := sdk.Candidate(addr, ctx.String(candNameFlag.Name), ctx.String(candPubKeyFlag.Name), ctx.String(candIPFlag.Name),
+		uint16(ctx.Uint(candPortFlag.Name)), amount, token, uint32(commission.Num().Uint64()), meta)
+	if err != nil {
+		return err
+	}
+	return transactStaking(ctx, key, body)
+}
+
+func stakingDelegateAction(ctx *cli.Context) error {
+	key, holder, err := loadAccountKey(ctx)
+	if err != nil {
+		return err
+	}
+	candidate, err := meter.ParseAddress(ctx.String(candidateFlag.Name))
+	if err != nil {
+		return errors.WithMessage(err, "candidate")
+	}
+	bucket, err := loadBucket(ctx, holder)
+	if err != nil {
+		return err
+	}
+	body := sdk.Delegate(holder, candidate, bucket.BucketID, bucket.Value, bucket.Token)
+	return transactStaking(ctx, key, body)
+}
+
+func stakingUndelegateAction(ctx *cli.Context) error {
+	key, holder, err := loadAccountKey(ctx)
+	if err != nil {
+		return err
+	}
+	bucket, err := loadBucket(ctx, holder)
+	if err != nil {
+		return err
+	}
+	body := sdk.Undelegate(holder, bucket.BucketID, bucket.Value, bucket.Token)
+	return transactStaking(ctx, key, body)
+}
+
+func auctionBidAction(ctx *cli.Context) error {
+	key, bidder, err := loadAccountKey(ctx)
+	if err != nil {
+		return err
+	}
+	amount, err := parseAmount(ctx)
+	if err != nil {
+		return err
+	}
+	body := sdk.Bid(bidder, amount)
+	clause, err := sdk.AuctionClause(body)
+	if err != nil {
+		return err
+	}
+	if err := transact(ctx, key, clause); err != nil {
+		return err
+	}
+	fmt.Println("Bid nonce:", body.Nonce)
+	return nil
+}
+
+func auctionCancelAction(ctx *cli.Context) error {
+	key, bidder, err := loadAccountKey(ctx)
+	if err != nil {
+		return err
+	}
+	auctionID, err := meter.ParseBytes32(ctx.String(auctionIDFlag.Name))
+	if err != nil {
+		return errors.WithMessage(err, "auction-id")
+	}
+	clause, err := sdk.AuctionClause(sdk.CancelBid(bidder, auctionID, ctx.Uint64(bidNonceFlag.Name)))
+	if err != nil {
+		return err
+	}
+	return transact(ctx, key, clause)
+}
+
+// loadAccountKey decrypts the account keystore given by --keystore.
+func loadAccountKey(ctx *cli.Context) (*ecdsa.PrivateKey, meter.Address, error) {
+	path := ctx.String(keystoreFlag.Name)
+	if path == "" {
+		return nil, meter.Address{}, errors.New("keystore required")
+	}
+	keyjson, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, meter.Address{}, errors.WithMessage(err, "read keystore")
+	}
+	password, err := readPassphrase(ctx, keyPasswordFlag, "Enter passphrase: ")
+	if err != nil {
+		return nil, meter.Address{}, err
+	}
+	key, err := keystore.DecryptKey(keyjson, password)
+	if err != nil {
+		return nil, meter.Address{}, errors.WithMessage(err, "decrypt keystore")
+	}
+	return key.PrivateKey, meter.Address(crypto.PubkeyToAddress(key.PrivateKey.PublicKey)), nil
+}
+
+// loadBucket fetches the bucket given by --bucket, which must be owned by holder.
+func loadBucket(ctx *cli.Context, holder meter.Address) (*staking.Bucket, error) {
+	id, err := meter.ParseBytes32(ctx.String(bucketFlag.Name))
+	if err != nil {
+		return nil, errors.WithMessage(err, "bucket")
+	}
+	bucket, err := sdk.NewClient(ctx.String(apiURLFlag.Name)).GetBucket(id)
+	if err != nil {
+		return nil, err
+	}
+	if bucket.Owner != holder {
+		return nil, errors.Errorf("bucket %v is owned by %v", id, bucket.Owner)
+	}
+	return bucket, nil
+}
+
+// parseAmount converts --amount in MTR or MTRG to wei.
+func parseAmount(ctx *cli.Context) (*big.Int, error) {
+	amount, ok := new(big.Rat).SetString(ctx.String(amountFlag.Name))
+	if !ok || amount.Sign() <= 0 {
+		return nil, errors.New("invalid amount")
+	}
+	amount.Mul(amount, new(big.Rat).SetInt64(1e18))
+	if !amount.IsInt() {
+		return nil, errors.New("invalid amount, too many decimals")
+	}
+	return amount.Num(), nil
+}
+
+func parseToken(ctx *cli.Context) (byte, error) {
+	switch strings.ToLower(ctx.String(tokenFlag.Name)) {
+	case "mtr":
+		return staking.TOKEN_METER, nil
+	case "mtrg":
+		return staking.TOKEN_METER_GOV, nil
+	default:
+		return 0, errors.New("invalid token (mtr|mtrg)")
+	}
+}
+
+func transactStaking(ctx *cli.Context, key *ecdsa.PrivateKey, body *staking.StakingBody) error {
+	clause, err := sdk.StakingClause(body)
+	if err != nil {
+		return err
+	}
+	return transact(ctx, key, clause)
+}
+
+// transact sends the clause, or only simulates it with --dry-run.
+func transact(ctx *cli.Context, key *ecdsa.PrivateKey, clause *tx.Clause) error {
+	client := sdk.NewClient(ctx.String(apiURLFlag.Name))
+	if ctx.Bool(dryRunFlag.Name) {
+		results, err := client.Simulate(meter.Address(crypto.PubkeyToAddress(key.PublicKey)), clause)
+		if err != nil {
+			return err
+		}
+		fmt.Println("Simulation succeeded, gas used:", results[0].GasUsed)
+		return nil
+	}
+
+	id, err := client.Transact(key, clause)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Transaction sent:", id)
+	return nil
+}
@@ -8,6 +8,7 @@ package main
 import (
 	"context"
 	"crypto/ecdsa"
+	crand "crypto/rand"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -17,6 +18,7 @@ import (
 	"os/user"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"syscall"
 	"time"
 
@@ -31,9 +33,9 @@ import (
 	tty "github.com/mattn/go-tty"
 )
 
-const paraString = "7479706520610a7120393838353834383131343738353339323431393933323931313633343633383233393237323539333630313734353437303331303937363333343133333530303632303839383839373036333235323836313831303431393231303532353631343638393937373833313833373239383735313336373034373032383734373731313033383234383836323436333937353435373032373639310a682031333532383333373038363039373437363036393233353830313130323833353636323231393236323833343938313336333130333037363536313036373633383333353631353531343531363531393734363630363036363434333134333539303831373330323933320a72203733303735313136373131343539353138363134323832393030323835333733393531393935383631343830323433310a65787032203135390a65787031203133380a7369676e3120310a7369676e30202d310a"
+const paraString = bls.ParamsHex
 
-const systemString = "2db8cb49c44a1c7ba19fdaf6947425a7c0191c710b64fd89cdc8b573881d98d814e377bb5a158c90a93e077b6ec1c3c92ae51f53fb22ef42d117b95f84c2dfec00"
+const systemString = bls.SystemHex
 
 func fatal(args ...interface{}) {
 	var w io.Writer
@@ -85,6 +87,32 @@ func loadOrGeneratePrivateKey(path string) (*ecdsa.PrivateKey, error) {
 	return key, nil
 }
 
+// loadOrGenerateToken loads the token to access admin or signer service, or generates one if absent.
+func loadOrGenerateToken(path string) (string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err == nil {
+		// an empty token would authorize requests without credential
+		token := strings.TrimSpace(string(data))
+		if token == "" {
+			return "", fmt.Errorf("token file %v is empty", path)
+		}
+		return token, nil
+	}
+	if !os.IsNotExist(err) {
+		return "", err
+	}
+
+	var b [32]byte
+	if _, err := crand.Read(b[:]); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(b[:])
+	if err := ioutil.WriteFile(path, []byte(token), 0600); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
 func fromBase64Pub(pub string) (*ecdsa.PublicKey, error) {
 	b, err := b64.StdEncoding.DecodeString(pub)
 	if err != nil {
@@ -84,6 +84,7 @@ func (c *Communicator) fetchBlockByID(peer *Peer, newBlockID meter.Bytes32) {
 		return
 	}
 
+	c.markBlockSource(peer, &blk)
 	c.newBlockFeed.Send(&NewBlockEvent{
 		Block: &blk,
 	})
@@ -25,6 +25,7 @@ import (
 	"github.com/dfinlab/meter/txpool"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/p2p"
+	lru "github.com/hashicorp/golang-lru"
 	"github.com/inconshreveable/log15"
 	"github.com/pkg/errors"
 )
@@ -51,6 +52,9 @@ type Communicator struct {
 	powPool     *powpool.PowPool
 	configTopic string
 	syncTrigCh  chan bool
+	bans        *banList
+	// peers which sent the blocks, to be blamed if the blocks are invalid
+	blockSources *lru.Cache
 
 	magic [4]byte
 }
@@ -66,6 +70,10 @@ func GetGlobCommInst() *Communicator {
 // New create a new Communicator instance.
 func New(chain *chain.Chain, txPool *txpool.TxPool, powPool *powpool.PowPool, configTopic string, magic [4]byte) *Communicator {
 	ctx, cancel := context.WithCancel(context.Background())
+	blockSources, err := lru.New(maxBlockSources)
+	if err != nil {
+		fmt.Println("block sources init error:", err)
+	}
 	c := &Communicator{
 		chain:          chain,
 		txPool:         txPool,
@@ -77,6 +85,8 @@ func New(chain *chain.Chain, txPool *txpool.TxPool, powPool *powpool.PowPool, co
 		announcementCh: make(chan *announcement),
 		configTopic:    configTopic,
 		syncTrigCh:     make(chan bool),
+		bans:           newBanList(),
+		blockSources:   blockSources,
 		magic:          magic,
 	}
 
@@ -94,6 +104,17 @@ func (c *Communicator) TriggerSync() {
 	c.syncTrigCh <- true
 }
 
+// TryTriggerSync triggers a synchronization unless one is in progress,
+// returns false if the trigger is not accepted.
+func (c *Communicator) TryTriggerSync() bool {
+	select {
+	case c.syncTrigCh <- true:
+		return true
+	default:
+		return false
+	}
+}
+
 // Sync start synchronization process.
 func (c *Communicator) Sync(handler HandleBlockStream, qcHandler HandleQC) {
 	const initSyncInterval = 2 * time.Second
@@ -129,11 +150,7 @@ func (c *Communicator) Sync(handler HandleBlockStream, qcHandler HandleQC) {
 				log.Info("Triggered synchronization start")
 
 				best := c.chain.BestBlock().Header()
-				// choose peer which has the head block with higher total score
-				peer := c.peerSet.Slice().Find(func(peer *Peer) bool {
-					_, totalScore := peer.Head()
-					return totalScore >= best.TotalScore()
-				})
+				peer := c.selectSyncPeer(best.TotalScore())
 				if peer != nil {
 					log.Info("trigger sync with peer", "peer", peer.RemoteAddr().String())
 					if err := c.sync(peer, best.Number(), handler, qcHandler); err != nil {
@@ -146,11 +163,7 @@ func (c *Communicator) Sync(handler HandleBlockStream, qcHandler HandleQC) {
 				log.Debug("synchronization start")
 
 				best := c.chain.BestBlock().Header()
-				// choose peer which has the head block with higher total score
-				peer := c.peerSet.Slice().Find(func(peer *Peer) bool {
-					_, totalScore := peer.Head()
-					return totalScore >= best.TotalScore()
-				})
+				peer := c.selectSyncPeer(best.TotalScore())
 				if peer == nil {
 					// XXX: original setting was 3, changed to 1 for cold start
 					if c.peerSet.Len() < 1 {
@@ -209,12 +222,71 @@ func (c *Communicator) Stop() {
 	c.goes.Wait()
 }
 
+// selectSyncPeer chooses the peer to sync with among those having the head block with higher total score.
+// Peers are preferred by score, then by latency.
+func (c *Communicator) selectSyncPeer(totalScore uint64) *Peer {
+	peers := c.peerSet.Slice().Filter(func(peer *Peer) bool {
+		_, ts := peer.Head()
+		return ts >= totalScore
+	})
+	if len(peers) == 0 {
+		return nil
+	}
+	sortForSync(peers)
+	return peers[0]
+}
+
+// penalize adjusts score of the peer, and bans it if the score drops below threshold.
+func (c *Communicator) penalize(peer *Peer, delta int, reason string) {
+	score := peer.adjustScore(delta)
+	peer.logger.Debug("peer penalized", "reason", reason, "score", score)
+	if score <= banThreshold {
+		peer.logger.Info("peer banned", "reason", reason, "duration", banDuration)
+		c.bans.ban(peer.ID(), time.Now().Add(banDuration))
+		peer.Disconnect(p2p.DiscUselessPeer)
+	}
+}
+
+// markBlockSource remembers the peer which sent the block.
+func (c *Communicator) markBlockSource(peer *Peer, blk *block.Block) {
+	c.blockSources.Add(blk.Header().ID(), peer)
+}
+
+// ReportBadBlock penalizes the peer which sent the block, it's called when the
+// block fails validation.
+func (c *Communicator) ReportBadBlock(id meter.Bytes32) {
+	v, ok := c.blockSources.Get(id)
+	if !ok {
+		return
+	}
+	c.blockSources.Remove(id)
+	c.penalize(v.(*Peer), scoreInvalidBlock, "invalid block")
+}
+
+// BannedPeers returns peers currently banned.
+func (c *Communicator) BannedPeers() []*BannedPeer {
+	return c.bans.entries()
+}
+
+// LoadBannedPeers restores bans, usually loaded from peers cache.
+func (c *Communicator) LoadBannedPeers(bans []*BannedPeer) {
+	now := uint64(time.Now().Unix())
+	for _, b := range bans {
+		if b.Until > now {
+			c.bans.ban(b.ID, time.Unix(int64(b.Until), 0))
+		}
+	}
+}
+
 type txsToSync struct {
 	txs    tx.Transactions
 	synced bool
 }
 
 func (c *Communicator) servePeer(p *p2p.Peer, rw p2p.MsgReadWriter) error {
+	if c.bans.isBanned(p.ID()) {
+		return errors.New("peer banned")
+	}
 	peer, dir := newPeer(p, rw, c.magic)
 	curIP := peer.RemoteAddr().String()
 	lastIndex := strings.LastIndex(curIP, ":")
@@ -276,10 +348,12 @@ func (c *Communicator) runPeer(peer *Peer, dir string) {
 
 	peer.UpdateHead(status.BestBlockID, status.TotalScore)
 	c.peerSet.Add(peer, dir)
+	updatePeerCountGauge(c.peerSet.DirectionCount())
 	peer.logger.Debug(fmt.Sprintf("peer added (%v)", c.peerSet.Len()))
 
 	defer func() {
 		c.peerSet.Remove(peer.ID())
+		updatePeerCountGauge(c.peerSet.DirectionCount())
 		peer.logger.Debug(fmt.Sprintf("peer removed (%v)", c.peerSet.Len()))
 	}()
 
@@ -365,6 +439,8 @@ func (c *Communicator) PeersStats() []*PeerStats {
 			NetAddr:     peer.RemoteAddr().String(),
 			Inbound:     peer.Inbound(),
 			Duration:    uint64(time.Duration(peer.Duration()) / time.Second),
+			Score:       peer.Score(),
+			Latency:     uint64(peer.Latency() / time.Millisecond),
 		})
 	}
 	sort.Slice(stats, func(i, j int) bool {
@@ -0,0 +1,325 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package comm
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/dfinlab/meter/block"
+	"github.com/dfinlab/meter/co"
+	"github.com/dfinlab/meter/comm/proto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
+)
+
+const (
+	downloadChunkSize = 1024 // blocks per range fetched from one peer, same as the max blocks served per request
+	maxDownloadPeers  = 8    // max peers fetched from concurrently
+	maxPendingChunks  = 12   // max chunks fetched ahead of the one being delivered
+
+	// max block sources kept, covers the blocks fetched and queued for execution
+	maxBlockSources = (maxPendingChunks + 2) * downloadChunkSize
+)
+
+// chunk is a range of blocks [from, from+downloadChunkSize) fetched from a peer.
+// Less blocks than the chunk size means the end of the chain is reached.
+type chunk struct {
+	from   uint32
+	blocks []*block.Block
+	peer   *Peer
+	err    error
+}
+
+// downloader fetches disjoint ranges of blocks from several peers concurrently,
+// and delivers them in order after verification.
+type downloader struct {
+	c       *Communicator
+	primary *Peer // the peer chosen to sync with, which is also the fallback of other peers
+	peers   Peers
+	from    uint32
+}
+
+func newDownloader(c *Communicator, primary *Peer, fromNum uint32) *downloader {
+	best := c.chain.BestBlock().Header()
+	others := c.peerSet.Slice().Filter(func(peer *Peer) bool {
+		if peer == primary {
+			return false
+		}
+		_, totalScore := peer.Head()
+		return totalScore >= best.TotalScore()
+	})
+	sortForSync(others)
+	if len(others) > maxDownloadPeers-1 {
+		others = others[:maxDownloadPeers-1]
+	}
+	return &downloader{
+		c:       c,
+		primary: primary,
+		peers:   append(Peers{primary}, others...),
+		from:    fromNum,
+	}
+}
+
+// run downloads blocks and sends them to blockCh in order, until the end of the chain reached.
+func (d *downloader) run(ctx context.Context, blockCh chan<- *block.Block, qcHandler HandleQC) error {
+	if err := d.syncQC(ctx, qcHandler); err != nil {
+		return err
+	}
+
+	// the common ancestor, which the first downloaded block should extend
+	prev, err := d.c.chain.GetTrunkBlock(d.from - 1)
+	if err != nil {
+		return errors.WithMessage(err, "get ancestor")
+	}
+
+	fetchCtx, cancel := context.WithCancel(ctx)
+	tasks := make(chan uint32)
+	results := make(chan *chunk)
+	var workers co.Goes
+	defer func() {
+		cancel()
+		workers.Wait()
+	}()
+	for _, peer := range d.peers {
+		peer := peer
+		workers.Go(func() {
+			for {
+				select {
+				case <-fetchCtx.Done():
+					return
+				case from := <-tasks:
+					ck := d.fetchChunk(fetchCtx, peer, from)
+					select {
+					case <-fetchCtx.Done():
+						return
+					case results <- ck:
+					}
+				}
+			}
+		})
+	}
+
+	var (
+		pending     = make(map[uint32]*chunk)
+		next        = d.from // start of the next chunk to deliver
+		scheduled   = d.from // start of the next chunk to schedule
+		inflight    = 0
+		endReached  = false
+		contributed = make(map[*Peer]bool)
+	)
+	defer func() {
+		for peer := range contributed {
+			peer.adjustScore(scoreSynced)
+		}
+	}()
+
+	for {
+		var taskCh chan<- uint32
+		if !endReached && inflight+len(pending) < maxPendingChunks {
+			taskCh = tasks
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case taskCh <- scheduled:
+			scheduled += downloadChunkSize
+			inflight++
+		case ck := <-results:
+			inflight--
+			if ck.err != nil {
+				return ck.err
+			}
+			if len(ck.blocks) < downloadChunkSize {
+				endReached = true
+			}
+			pending[ck.from] = ck
+		}
+
+		// deliver chunks in order
+		for {
+			ck, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+
+			if len(ck.blocks) == 0 {
+				if ck.from == d.from {
+					// the peer advertised a better head but has nothing to serve
+					if _, totalScore := d.primary.Head(); totalScore > d.c.chain.BestBlock().Header().TotalScore() {
+						d.c.penalize(d.primary, scoreBadHead, "empty blocks")
+					}
+				}
+				return d.syncQC(ctx, qcHandler)
+			}
+			// blocks inside the chunk are verified by fetch, only the joint is left
+			if err := verifyLink(prev, ck.blocks[0]); err != nil {
+				d.c.penalize(ck.peer, scoreInvalidBlock, "broken chain")
+				return errors.WithMessage(err, fmt.Sprintf("chunk from %v", ck.peer.RemoteAddr()))
+			}
+			prev = ck.blocks[len(ck.blocks)-1]
+			for _, blk := range ck.blocks {
+				ck.peer.MarkBlock(blk.Header().ID())
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case blockCh <- blk:
+				}
+			}
+			contributed[ck.peer] = true
+			next += downloadChunkSize
+
+			if len(ck.blocks) < downloadChunkSize {
+				return d.syncQC(ctx, qcHandler)
+			}
+		}
+	}
+}
+
+// syncQC fetches the best QC from the primary peer.
+func (d *downloader) syncQC(ctx context.Context, qcHandler HandleQC) error {
+	qc, err := proto.GetBestQC(ctx, d.primary)
+	if err != nil {
+		if ctx.Err() == nil {
+			d.c.penalize(d.primary, scoreTimeout, "get best qc")
+		}
+		return err
+	}
+	updated, err := qcHandler(ctx, qc)
+	if err != nil {
+		return err
+	}
+	if updated {
+		d.primary.logger.Debug("best qc updated", "qc", qc.String())
+	}
+	return nil
+}
+
+// fetchChunk fetches the chunk from the peer. It falls back to the primary peer if
+// the peer fails or can't serve the whole chunk, since only the primary peer decides
+// where the chain ends.
+func (d *downloader) fetchChunk(ctx context.Context, peer *Peer, from uint32) *chunk {
+	blocks, err := d.fetch(ctx, peer, from)
+	if peer != d.primary && (err != nil || len(blocks) < downloadChunkSize) {
+		peer.logger.Debug("failed to fetch whole chunk, fallback to primary peer", "from", from, "err", err)
+		peer = d.primary
+		blocks, err = d.fetch(ctx, peer, from)
+	}
+	return &chunk{from, blocks, peer, err}
+}
+
+// fetch gets blocks of the chunk starting at from, and verifies them before execution.
+func (d *downloader) fetch(ctx context.Context, peer *Peer, from uint32) ([]*block.Block, error) {
+	var blocks []*block.Block
+	for len(blocks) < downloadChunkSize {
+		num := from + uint32(len(blocks))
+		result, err := proto.GetBlocksFromNumber(ctx, peer, num)
+		if err != nil {
+			if ctx.Err() == nil {
+				d.c.penalize(peer, scoreTimeout, "get blocks")
+			}
+			return nil, err
+		}
+		if len(result) == 0 {
+			break
+		}
+		for _, raw := range result {
+			if len(blocks) >= downloadChunkSize {
+				break
+			}
+			var blk block.Block
+			if err := rlp.DecodeBytes(raw, &blk); err != nil {
+				d.c.penalize(peer, scoreInvalidBlock, "invalid block")
+				return nil, errors.Wrap(err, "invalid block")
+			}
+			if blk.Header().Number() != num {
+				d.c.penalize(peer, scoreInvalidBlock, "broken sequence")
+				return nil, errors.New("broken sequence")
+			}
+			num++
+			blocks = append(blocks, &blk)
+		}
+	}
+
+	if err := verifyBlocks(blocks); err != nil {
+		d.c.penalize(peer, scoreInvalidBlock, "invalid block")
+		return nil, err
+	}
+	for _, blk := range blocks {
+		d.c.markBlockSource(peer, blk)
+	}
+	return blocks, nil
+}
+
+// verifyBlocks checks headers and QCs of blocks, and recovers signers of headers and txs
+// in parallel, so that the costly work is done ahead of execution.
+func verifyBlocks(blocks []*block.Block) error {
+	var (
+		lock sync.Mutex
+		errs []error
+	)
+	<-co.Parallel(func(queue chan<- func()) {
+		for _, blk := range blocks {
+			h := blk.Header()
+			queue <- func() {
+				h.ID()
+				if _, err := h.Signer(); err != nil {
+					lock.Lock()
+					errs = append(errs, errors.WithMessage(err, fmt.Sprintf("block %v signer", h.Number())))
+					lock.Unlock()
+				}
+			}
+			for _, tx := range blk.Transactions() {
+				tx := tx
+				queue <- func() {
+					tx.ID()
+					tx.UnprovedWork()
+					tx.IntrinsicGas()
+					tx.Signer()
+				}
+			}
+		}
+	})
+	if len(errs) > 0 {
+		return errs[0]
+	}
+
+	for i, blk := range blocks {
+		h := blk.Header()
+		if h.GasUsed() > h.GasLimit() {
+			return errors.Errorf("block %v gas used exceeds limit", h.Number())
+		}
+		if blk.QC == nil || blk.QC.QCHeight >= h.Number() {
+			return errors.Errorf("block %v refers to an invalid qc", h.Number())
+		}
+		if i > 0 {
+			if err := verifyLink(blocks[i-1], blk); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// verifyLink checks that the block extends its parent.
+func verifyLink(parentBlk *block.Block, blk *block.Block) error {
+	parent, h := parentBlk.Header(), blk.Header()
+	switch {
+	case h.ParentID() != parent.ID():
+		return errors.Errorf("block %v parent mismatch", h.Number())
+	case h.Timestamp() <= parent.Timestamp():
+		return errors.Errorf("block %v timestamp behind parent", h.Number())
+	case h.TotalScore() <= parent.TotalScore():
+		return errors.Errorf("block %v total score invalid", h.Number())
+	case !block.GasLimit(h.GasLimit()).IsValid(parent.GasLimit()):
+		return errors.Errorf("block %v gas limit invalid", h.Number())
+	case parentBlk.QC != nil && blk.QC.QCHeight < parentBlk.QC.QCHeight:
+		return errors.Errorf("block %v qc behind parent", h.Number())
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package comm
+
+import (
+	"testing"
+
+	"github.com/dfinlab/meter/block"
+	"github.com/dfinlab/meter/meter"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestBlocks(t *testing.T, n int) []*block.Block {
+	key, _ := crypto.GenerateKey()
+	var (
+		blocks   []*block.Block
+		parentID = meter.Bytes32{}
+	)
+	for i := 0; i < n; i++ {
+		b := new(block.Builder).
+			ParentID(parentID).
+			Timestamp(uint64(1000 + i*10)).
+			TotalScore(uint64(i + 1)).
+			GasLimit(10000000).
+			Build()
+		sig, err := crypto.Sign(b.Header().SigningHash().Bytes(), key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b = b.WithSignature(sig)
+		b.SetQC(&block.QuorumCert{QCHeight: b.Header().Number() - 1})
+		blocks = append(blocks, b)
+		parentID = b.Header().ID()
+	}
+	return blocks
+}
+
+func TestVerifyBlocks(t *testing.T) {
+	blocks := newTestBlocks(t, 5)
+	assert.Nil(t, verifyBlocks(blocks))
+	assert.Nil(t, verifyLink(blocks[1], blocks[2]))
+
+	assert.NotNil(t, verifyLink(blocks[0], blocks[2]), "parent mismatch")
+	assert.NotNil(t, verifyBlocks([]*block.Block{blocks[0], blocks[2]}))
+
+	// qc should refer to an ancestor
+	blocks[3].SetQC(&block.QuorumCert{QCHeight: blocks[3].Header().Number()})
+	assert.NotNil(t, verifyBlocks(blocks))
+
+	// qc height should not go backward
+	blocks[3].SetQC(&block.QuorumCert{QCHeight: 0})
+	assert.NotNil(t, verifyLink(blocks[2], blocks[3]))
+}
+
+func TestVerifyBlocksBadSignature(t *testing.T) {
+	blocks := newTestBlocks(t, 2)
+	blocks[1] = blocks[1].WithSignature([]byte{1, 2, 3})
+	blocks[1].SetQC(&block.QuorumCert{})
+	assert.NotNil(t, verifyBlocks(blocks))
+}
@@ -25,6 +25,7 @@ func (c *Communicator) handleRPC(peer *Peer, msg *p2p.Msg, write func(interface{
 
 	log := peer.logger.New("msg", proto.MsgName(msg.Code))
 	log.Debug("received RPC call")
+	msgReceivedCounter.WithLabelValues(proto.MsgName(msg.Code)).Inc()
 	defer func() {
 		if err != nil {
 			log.Debug("failed to handle RPC call", "err", err)
@@ -52,6 +53,7 @@ func (c *Communicator) handleRPC(peer *Peer, msg *p2p.Msg, write func(interface{
 
 		peer.MarkBlock(newBlock.Header().ID())
 		peer.UpdateHead(newBlock.Header().ID(), newBlock.Header().TotalScore())
+		c.markBlockSource(peer, newBlock)
 		c.newBlockFeed.Send(&NewBlockEvent{Block: newBlock})
 		write(&struct{}{})
 	case proto.MsgNewBlockID:
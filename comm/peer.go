@@ -6,12 +6,15 @@
 package comm
 
 import (
+	"context"
 	crand "crypto/rand"
 	"encoding/binary"
 	"fmt"
 	"math/rand"
 	"sync"
+	"time"
 
+	"github.com/dfinlab/meter/comm/proto"
 	"github.com/dfinlab/meter/meter"
 	"github.com/dfinlab/meter/p2psrv/rpc"
 	"github.com/ethereum/go-ethereum/common/mclock"
@@ -47,6 +50,7 @@ func init() {
 type Peer struct {
 	*p2p.Peer
 	*rpc.RPC
+	peerScore
 	logger log15.Logger
 
 	createdTime    mclock.AbsTime
@@ -148,6 +152,23 @@ func (p *Peer) String() string {
 	return fmt.Sprintf("%s(%d)", p.head.id.String(), p.head.totalScore)
 }
 
+// Notify sends a notification to the peer.
+func (p *Peer) Notify(ctx context.Context, msgCode uint64, arg interface{}) error {
+	msgSentCounter.WithLabelValues(proto.MsgName(msgCode)).Inc()
+	return p.RPC.Notify(ctx, msgCode, arg)
+}
+
+// Call sends a call to the peer and waits for result.
+func (p *Peer) Call(ctx context.Context, msgCode uint64, arg interface{}, result interface{}) error {
+	msgSentCounter.WithLabelValues(proto.MsgName(msgCode)).Inc()
+	start := time.Now()
+	if err := p.RPC.Call(ctx, msgCode, arg, result); err != nil {
+		return err
+	}
+	p.updateLatency(time.Since(start))
+	return nil
+}
+
 // Peers slice of peers
 type Peers []*Peer
 
@@ -0,0 +1,19 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package comm
+
+import "github.com/dfinlab/meter/metric"
+
+var (
+	peerCountGauge     = metric.NewGaugeVec("p2p_peers", "Number of connected peers by direction", "dir")
+	msgReceivedCounter = metric.NewCounterVec("p2p_messages_received_total", "Counter of received messages by code", "msg")
+	msgSentCounter     = metric.NewCounterVec("p2p_messages_sent_total", "Counter of sent messages by code", "msg")
+)
+
+func updatePeerCountGauge(counter DirectionCount) {
+	peerCountGauge.WithLabelValues("inbound").Set(float64(counter.Inbound))
+	peerCountGauge.WithLabelValues("outbound").Set(float64(counter.Outbound))
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package comm
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/p2p/discover"
+)
+
+// score changes on peer behaviors.
+const (
+	scoreInvalidBlock = -50 // sent undecodable, out of sequence or invalid blocks
+	scoreBadHead      = -30 // advertised a head it could not serve
+	scoreTimeout      = -20 // failed to respond a request in time
+	scoreSynced       = 5   // served blocks for sync
+
+	maxScore     = 100
+	banThreshold = -100
+	banDuration  = 30 * time.Minute
+
+	// weight of the latest sample in the latency average, in 1/10
+	latencyWeight = 2
+)
+
+// peerScore tracks the score and response latency of a peer.
+type peerScore struct {
+	lock    sync.Mutex
+	score   int
+	latency time.Duration
+}
+
+// Score returns the current score.
+func (s *peerScore) Score() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.score
+}
+
+// adjustScore adds delta to the score, and returns the new score.
+func (s *peerScore) adjustScore(delta int) int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.score += delta
+	if s.score > maxScore {
+		s.score = maxScore
+	}
+	return s.score
+}
+
+// Latency returns the moving average of response latency.
+func (s *peerScore) Latency() time.Duration {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.latency
+}
+
+func (s *peerScore) updateLatency(d time.Duration) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.latency == 0 {
+		s.latency = d
+	} else {
+		s.latency = (s.latency*(10-latencyWeight) + d*latencyWeight) / 10
+	}
+}
+
+// BannedPeer is a peer banned until the given time.
+type BannedPeer struct {
+	ID    discover.NodeID
+	Until uint64 // unix timestamp
+}
+
+// banList holds temporarily banned peers.
+type banList struct {
+	lock sync.Mutex
+	m    map[discover.NodeID]time.Time
+}
+
+func newBanList() *banList {
+	return &banList{m: make(map[discover.NodeID]time.Time)}
+}
+
+func (b *banList) ban(id discover.NodeID, until time.Time) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.m[id] = until
+}
+
+func (b *banList) isBanned(id discover.NodeID) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	until, ok := b.m[id]
+	if !ok {
+		return false
+	}
+	if time.Now().After(until) {
+		delete(b.m, id)
+		return false
+	}
+	return true
+}
+
+// entries returns the bans not yet expired.
+func (b *banList) entries() []*BannedPeer {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	now := time.Now()
+	entries := make([]*BannedPeer, 0, len(b.m))
+	for id, until := range b.m {
+		if now.After(until) {
+			delete(b.m, id)
+			continue
+		}
+		entries = append(entries, &BannedPeer{id, uint64(until.Unix())})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Until < entries[j].Until
+	})
+	return entries
+}
+
+// sortForSync sorts peers by score descending, then by latency ascending.
+// Peers with unknown latency are put after the measured ones of the same score.
+func sortForSync(peers Peers) {
+	sort.SliceStable(peers, func(i, j int) bool {
+		si, sj := peers[i].Score(), peers[j].Score()
+		if si != sj {
+			return si > sj
+		}
+		li, lj := peers[i].Latency(), peers[j].Latency()
+		if li == 0 || lj == 0 {
+			return lj == 0 && li != 0
+		}
+		return li < lj
+	})
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package comm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dfinlab/meter/block"
+	"github.com/dfinlab/meter/meter"
+	"github.com/ethereum/go-ethereum/p2p/discover"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeerScore(t *testing.T) {
+	var s peerScore
+	assert.Equal(t, 0, s.Score())
+	assert.Equal(t, scoreTimeout, s.adjustScore(scoreTimeout))
+	s.adjustScore(maxScore * 2)
+	assert.Equal(t, maxScore, s.Score(), "should be capped")
+
+	s.updateLatency(100 * time.Millisecond)
+	assert.Equal(t, 100*time.Millisecond, s.Latency())
+	s.updateLatency(200 * time.Millisecond)
+	assert.Equal(t, 120*time.Millisecond, s.Latency())
+}
+
+func TestBanList(t *testing.T) {
+	b := newBanList()
+	id1 := discover.NodeID{1}
+	id2 := discover.NodeID{2}
+
+	b.ban(id1, time.Now().Add(time.Minute))
+	b.ban(id2, time.Now().Add(-time.Minute))
+
+	assert.True(t, b.isBanned(id1))
+	assert.False(t, b.isBanned(id2), "should be expired")
+	assert.False(t, b.isBanned(discover.NodeID{3}))
+
+	entries := b.entries()
+	assert.Equal(t, 1, len(entries))
+	assert.Equal(t, id1, entries[0].ID)
+}
+
+func TestSortForSync(t *testing.T) {
+	newPeer := func(score int, latency time.Duration) *Peer {
+		p := &Peer{}
+		p.score = score
+		p.latency = latency
+		return p
+	}
+	var (
+		p1 = newPeer(10, 0)
+		p2 = newPeer(10, 50*time.Millisecond)
+		p3 = newPeer(10, 20*time.Millisecond)
+		p4 = newPeer(-20, 10*time.Millisecond)
+		p5 = newPeer(30, 300*time.Millisecond)
+	)
+	peers := Peers{p1, p2, p3, p4, p5}
+	sortForSync(peers)
+	assert.Equal(t, Peers{p5, p3, p2, p1, p4}, peers)
+}
+
+func TestReportBadBlock(t *testing.T) {
+	c := New(nil, nil, nil, "", [4]byte{})
+	peer := &Peer{logger: log}
+	blk := new(block.Builder).ParentID(meter.BytesToBytes32([]byte("parent"))).Build()
+
+	c.ReportBadBlock(blk.Header().ID())
+	assert.Equal(t, 0, peer.Score(), "unknown source")
+
+	c.markBlockSource(peer, blk)
+	c.ReportBadBlock(blk.Header().ID())
+	assert.Equal(t, scoreInvalidBlock, peer.Score())
+
+	c.ReportBadBlock(blk.Header().ID())
+	assert.Equal(t, scoreInvalidBlock, peer.Score(), "penalized once")
+}
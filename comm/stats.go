@@ -24,4 +24,6 @@ type PeerStats struct {
 	NetAddr     string
 	Inbound     bool
 	Duration    uint64 // in seconds
+	Score       int
+	Latency     uint64 // in milliseconds
 }
@@ -12,7 +12,6 @@ import (
 	"github.com/dfinlab/meter/block"
 	"github.com/dfinlab/meter/co"
 	"github.com/dfinlab/meter/comm/proto"
-	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/pkg/errors"
 )
 
@@ -24,6 +23,8 @@ func (c *Communicator) sync(peer *Peer, headNum uint32, handler HandleBlockStrea
 	return c.download(peer, ancestor+1, handler, qcHandler)
 }
 
+// download fetches blocks from the peer and other peers with better head concurrently,
+// and streams them to the handler in order.
 func (c *Communicator) download(peer *Peer, fromNum uint32, handler HandleBlockStream, qcHandler HandleQC) error {
 
 	// it's important to set cap to 2
@@ -41,72 +42,8 @@ func (c *Communicator) download(peer *Peer, fromNum uint32, handler HandleBlockS
 	})
 	goes.Go(func() {
 		defer close(blockCh)
-		var blocks []*block.Block
-		for {
-			qc, err := proto.GetBestQC(ctx, peer)
-			// fmt.Println("HANDLE QC:", qc.String(), ", ERROR=", err)
-			if err != nil {
-				errCh <- err
-				return
-			}
-			updated, err := qcHandler(ctx, qc)
-			if err != nil {
-				errCh <- err
-			}
-			if updated {
-				fmt.Println("GOT QC: ", qc.String(), ", from:", peer.RemoteAddr().String())
-			}
-
-			result, err := proto.GetBlocksFromNumber(ctx, peer, fromNum)
-			if err != nil {
-				errCh <- err
-				return
-			}
-			if len(result) > 0 {
-				fmt.Println("GOT Block, ", len(result), "blocks, from height:", fromNum, ", from:", peer.RemoteAddr().String())
-			}
-			if len(result) == 0 {
-				return
-			}
-
-			blocks = blocks[:0]
-			for _, raw := range result {
-				var blk block.Block
-				if err := rlp.DecodeBytes(raw, &blk); err != nil {
-					errCh <- errors.Wrap(err, "invalid block")
-					return
-				}
-				if blk.Header().Number() != fromNum {
-					errCh <- errors.New("broken sequence")
-					return
-				}
-				fromNum++
-				blocks = append(blocks, &blk)
-			}
-
-			<-co.Parallel(func(queue chan<- func()) {
-				for _, blk := range blocks {
-					h := blk.Header()
-					queue <- func() { h.ID() }
-					for _, tx := range blk.Transactions() {
-						tx := tx
-						queue <- func() {
-							tx.ID()
-							tx.UnprovedWork()
-							tx.IntrinsicGas()
-						}
-					}
-				}
-			})
-
-			for _, blk := range blocks {
-				peer.MarkBlock(blk.Header().ID())
-				select {
-				case <-ctx.Done():
-					return
-				case blockCh <- blk:
-				}
-			}
+		if err := newDownloader(c, peer, fromNum).run(ctx, blockCh, qcHandler); err != nil {
+			errCh <- err
 		}
 	})
 	goes.Wait()
@@ -127,6 +64,9 @@ func (c *Communicator) findCommonAncestor(peer *Peer, headNum uint32) (uint32, e
 	isOverlapped := func(num uint32) (bool, error) {
 		result, err := proto.GetBlockIDByNumber(c.ctx, peer, num)
 		if err != nil {
+			if c.ctx.Err() == nil {
+				c.penalize(peer, scoreTimeout, "get block id")
+			}
 			return false, err
 		}
 		id, err := c.chain.GetTrunkBlockID(num)
@@ -112,7 +112,7 @@ func (cl *ConsensusLeader) CreateAnnounceMsgPeers() []*ConsensusPeer {
 			continue
 		}
 		// initialize PeerConn
-		p := newConsensusPeer(v.Name, v.NetAddr.IP, v.NetAddr.Port, cl.csReactor.magic)
+		p := newConsensusPeer(v.Name, v.NetAddr.IP, v.NetAddr.Port, cl.csReactor.magic, cl.csReactor.transport())
 		csPeers = append(csPeers, p)
 	}
 	return csPeers
@@ -126,7 +126,7 @@ func (cl *ConsensusLeader) CreateNotaryMsgPeers() []*ConsensusPeer {
 			continue
 		}
 		// initialize PeerConn
-		p := newConsensusPeer(cm.Name, cm.NetAddr.IP, cm.NetAddr.Port, cl.csReactor.magic)
+		p := newConsensusPeer(cm.Name, cm.NetAddr.IP, cm.NetAddr.Port, cl.csReactor.magic, cl.csReactor.transport())
 		csPeers = append(csPeers, p)
 	}
 	return csPeers
@@ -178,7 +178,7 @@ func (cl *ConsensusLeader) GenerateAnnounceMsg(height uint32, round uint32) bool
 	}
 
 	// sign message with ecdsa key
-	msgSig, err := cl.csReactor.SignConsensusMsg(msg.SigningHash().Bytes())
+	msgSig, err := cl.csReactor.SignConsensusMsg(msg)
 	if err != nil {
 		cl.csReactor.logger.Error("Sign message failed", "error", err)
 		return false
@@ -270,7 +270,7 @@ func (cl *ConsensusLeader) GenerateNotaryAnnounceMsg() bool {
 	}
 
 	// sign message with ecdsa key
-	msgSig, err := cl.csReactor.SignConsensusMsg(msg.SigningHash().Bytes())
+	msgSig, err := cl.csReactor.SignConsensusMsg(msg)
 	if err != nil {
 		cl.csReactor.logger.Error("Sign message failed", "error", err)
 		return false
@@ -103,7 +103,7 @@ func (conR *ConsensusReactor) NewCommitteeTimeout() {
 		size := len(conR.newCommittee.Committee.Validators)
 		nl := conR.newCommittee.Committee.Validators[conR.newCommittee.Round%uint32(size)]
 
-		leader := newConsensusPeer(nl.Name, nl.NetAddr.IP, nl.NetAddr.Port, conR.magic)
+		leader := newConsensusPeer(nl.Name, nl.NetAddr.IP, nl.NetAddr.Port, conR.magic, conR.transport())
 		leaderPubKey := nl.PubKey
 		conR.sendNewCommitteeMessage(leader, leaderPubKey, conR.newCommittee.KblockHeight,
 			conR.newCommittee.Nonce, conR.newCommittee.Round)
@@ -181,7 +181,9 @@ func (conR *ConsensusReactor) NewCommitteeUpdateRound(round uint32) {
 func (conR *ConsensusReactor) updateCurEpoch(epoch uint64) {
 	if epoch > conR.curEpoch {
 		oldVal := conR.curEpoch
+		conR.epochMtx.Lock()
 		conR.curEpoch = epoch
+		conR.epochMtx.Unlock()
 		curEpochGauge.Set(float64(conR.curEpoch))
 		conR.logger.Info("Epoch updated", "to", conR.curEpoch, "from", oldVal)
 	}
@@ -219,12 +221,17 @@ func (conR *ConsensusReactor) sendNewCommitteeMessage(peer *ConsensusPeer, leade
 
 	// sign message with bls key
 	signMsg := conR.BuildNewCommitteeSignMsg(leaderPubKey, nextEpochID, uint64(conR.curHeight))
-	blsSig, msgHash := conR.csCommon.SignMessage2([]byte(signMsg))
+	msgHash := conR.csCommon.Hash256Msg([]byte(signMsg))
+	blsSig, err := conR.signer.SignBlsMessage([]byte(signMsg))
+	if err != nil {
+		conR.logger.Error("Sign message failed", "error", err)
+		return false
+	}
 	msg.BlsSignature = blsSig
 	msg.SignedMsgHash = msgHash
 
 	// sign message with ecdsa key
-	ecdsaSigBytes, err := conR.SignConsensusMsg(msg.SigningHash().Bytes())
+	ecdsaSigBytes, err := conR.SignConsensusMsg(msg)
 	if err != nil {
 		conR.logger.Error("Sign message failed", "error", err)
 		return false
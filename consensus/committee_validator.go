@@ -46,7 +46,7 @@ type ConsensusValidator struct {
 
 func (cv *ConsensusValidator) SendMsgToPeer(msg *ConsensusMessage, netAddr types.NetAddress) bool {
 	name := cv.csReactor.GetDelegateNameByIP(netAddr.IP)
-	csPeer := newConsensusPeer(name, netAddr.IP, netAddr.Port, cv.csReactor.magic)
+	csPeer := newConsensusPeer(name, netAddr.IP, netAddr.Port, cv.csReactor.magic, cv.csReactor.transport())
 	return cv.csReactor.asyncSendCommitteeMsg(msg, false, csPeer)
 }
 
@@ -91,7 +91,7 @@ func (cv *ConsensusValidator) GenerateCommitMessage(sig bls.Signature, msgHash [
 	}
 
 	// sign message
-	msgSig, err := cv.csReactor.SignConsensusMsg(msg.SigningHash().Bytes())
+	msgSig, err := cv.csReactor.SignConsensusMsg(msg)
 	if err != nil {
 		cv.csReactor.logger.Error("Sign message failed", "error", err)
 		return nil
@@ -186,7 +186,11 @@ func (cv *ConsensusValidator) ProcessAnnounceCommittee(announceMsg *AnnounceComm
 
 	// I am in committee, sends the commit message to join the CommitCommitteeMessage
 	signMsg := cv.csReactor.BuildAnnounceSignMsg(lv.PubKey, announceMsg.EpochID(), uint64(ch.Height), uint32(ch.Round))
-	sign, msgHash := cv.csReactor.csCommon.SignMessage([]byte(signMsg))
+	sign, msgHash, err := cv.csReactor.SignBlsMsg([]byte(signMsg))
+	if err != nil {
+		cv.csReactor.logger.Error("Sign message failed", "error", err)
+		return false
+	}
 	msg := cv.GenerateCommitMessage(sign, msgHash, cv.csReactor.newCommittee.Round)
 
 	var m ConsensusMessage = msg
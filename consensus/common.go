@@ -43,7 +43,10 @@ func (cc *ConsensusCommon) Destroy() bool {
 	}
 
 	cc.PubKey.Free()
-	cc.PrivKey.Free()
+	// private key is absent with remote signer
+	if cc.PrivKey != (bls.PrivateKey{}) {
+		cc.PrivKey.Free()
+	}
 	cc.system.Free()
 	cc.pairing.Free()
 	cc.params.Free()
@@ -21,17 +21,14 @@ import (
 
 	"github.com/dfinlab/meter/block"
 	"github.com/dfinlab/meter/chain"
-	"github.com/dfinlab/meter/comm"
 	bls "github.com/dfinlab/meter/crypto/multi_sig"
 	cmn "github.com/dfinlab/meter/libs/common"
-	"github.com/dfinlab/meter/logdb"
 	"github.com/dfinlab/meter/meter"
 	"github.com/dfinlab/meter/packer"
 	"github.com/dfinlab/meter/powpool"
 	"github.com/dfinlab/meter/runtime"
 	"github.com/dfinlab/meter/state"
 	"github.com/dfinlab/meter/tx"
-	"github.com/dfinlab/meter/txpool"
 	"github.com/dfinlab/meter/xenv"
 )
 
@@ -558,7 +555,7 @@ func (pb *ProposedBlockInfo) String() string {
 }
 
 // Build MBlock
-func (conR *ConsensusReactor) BuildMBlock(parentBlock *block.Block) *ProposedBlockInfo {
+func (conR *ConsensusReactor) BuildMBlock(parentBlock *block.Block, round uint32) *ProposedBlockInfo {
 	best := parentBlock
 	now := uint64(time.Now().Unix())
 	/*
@@ -570,7 +567,7 @@ func (conR *ConsensusReactor) BuildMBlock(parentBlock *block.Block) *ProposedBlo
 	*/
 
 	startTime := mclock.Now()
-	pool := txpool.GetGlobTxPoolInst()
+	pool := conR.txPool()
 	if pool == nil {
 		conR.logger.Error("get tx pool failed ...")
 		panic("get tx pool failed ...")
@@ -593,7 +590,7 @@ func (conR *ConsensusReactor) BuildMBlock(parentBlock *block.Block) *ProposedBlo
 		return true
 	}
 
-	p := packer.GetGlobPackerInst()
+	p := conR.packer()
 	if p == nil {
 		conR.logger.Error("get packer failed ...")
 		panic("get packer failed")
@@ -628,7 +625,7 @@ func (conR *ConsensusReactor) BuildMBlock(parentBlock *block.Block) *ProposedBlo
 		}
 	}
 
-	newBlock, stage, receipts, err := flow.Pack(&conR.myPrivKey, block.BLOCK_TYPE_M_BLOCK, conR.lastKBlockHeight)
+	newBlock, stage, receipts, err := flow.PackWithSigner(conR.signer, conR.curEpoch, round, block.BLOCK_TYPE_M_BLOCK, conR.lastKBlockHeight)
 	if err != nil {
 		conR.logger.Error("build block failed", "error", err)
 		return nil
@@ -640,7 +637,7 @@ func (conR *ConsensusReactor) BuildMBlock(parentBlock *block.Block) *ProposedBlo
 	return &ProposedBlockInfo{newBlock, stage, &receipts, txsToRemoved, txsToReturned, checkPoint, MBlockType}
 }
 
-func (conR *ConsensusReactor) BuildKBlock(parentBlock *block.Block, data *block.KBlockData, rewards []powpool.PowReward) *ProposedBlockInfo {
+func (conR *ConsensusReactor) BuildKBlock(parentBlock *block.Block, round uint32, data *block.KBlockData, rewards []powpool.PowReward) *ProposedBlockInfo {
 	best := parentBlock
 	now := uint64(time.Now().Unix())
 	/*
@@ -683,7 +680,7 @@ func (conR *ConsensusReactor) BuildKBlock(parentBlock *block.Block, data *block.
 		txs = append(txs, tx)
 	}
 
-	pool := txpool.GetGlobTxPoolInst()
+	pool := conR.txPool()
 	if pool == nil {
 		conR.logger.Error("get tx pool failed ...")
 		panic("get tx pool failed ...")
@@ -696,7 +693,7 @@ func (conR *ConsensusReactor) BuildKBlock(parentBlock *block.Block, data *block.
 		return true
 	}
 
-	p := packer.GetGlobPackerInst()
+	p := conR.packer()
 	if p == nil {
 		conR.logger.Warn("get packer failed ...")
 		panic("get packer failed")
@@ -730,7 +727,7 @@ func (conR *ConsensusReactor) BuildKBlock(parentBlock *block.Block, data *block.
 		}
 	}
 
-	newBlock, stage, receipts, err := flow.Pack(&conR.myPrivKey, block.BLOCK_TYPE_K_BLOCK, conR.lastKBlockHeight)
+	newBlock, stage, receipts, err := flow.PackWithSigner(conR.signer, conR.curEpoch, round, block.BLOCK_TYPE_K_BLOCK, conR.lastKBlockHeight)
 	if err != nil {
 		conR.logger.Error("build block failed...", "error", err)
 		return nil
@@ -745,19 +742,19 @@ func (conR *ConsensusReactor) BuildKBlock(parentBlock *block.Block, data *block.
 	return &ProposedBlockInfo{newBlock, stage, &receipts, txsToRemoved, txsToReturned, checkPoint, KBlockType}
 }
 
-func (conR *ConsensusReactor) BuildStopCommitteeBlock(parentBlock *block.Block) *ProposedBlockInfo {
+func (conR *ConsensusReactor) BuildStopCommitteeBlock(parentBlock *block.Block, round uint32) *ProposedBlockInfo {
 	best := parentBlock
 	now := uint64(time.Now().Unix())
 
 	startTime := mclock.Now()
-	pool := txpool.GetGlobTxPoolInst()
+	pool := conR.txPool()
 	if pool == nil {
 		conR.logger.Error("get tx pool failed ...")
 		panic("get tx pool failed ...")
 		return nil
 	}
 
-	p := packer.GetGlobPackerInst()
+	p := conR.packer()
 	if p == nil {
 		conR.logger.Error("get packer failed ...")
 		panic("get packer failed")
@@ -780,7 +777,7 @@ func (conR *ConsensusReactor) BuildStopCommitteeBlock(parentBlock *block.Block)
 		return nil
 	}
 
-	newBlock, stage, receipts, err := flow.Pack(&conR.myPrivKey, block.BLOCK_TYPE_S_BLOCK, conR.lastKBlockHeight)
+	newBlock, stage, receipts, err := flow.PackWithSigner(conR.signer, conR.curEpoch, round, block.BLOCK_TYPE_S_BLOCK, conR.lastKBlockHeight)
 	if err != nil {
 		conR.logger.Error("build block failed", "error", err)
 		return nil
@@ -936,7 +933,7 @@ func (conR *ConsensusReactor) FinalizeCommitBlock(blkInfo *ProposedBlockInfo, be
 	}
 
 	*****/
-	batch := logdb.GetGlobalLogDBInstance().Prepare(blk.Header())
+	batch := conR.logDB().Prepare(blk.Header())
 	for i, tx := range blk.Transactions() {
 		origin, _ := tx.Signer()
 		txBatch := batch.ForTransaction(tx.ID(), origin)
@@ -987,7 +984,7 @@ func (conR *ConsensusReactor) FinalizeCommitBlock(blkInfo *ProposedBlockInfo, be
 	conR.chain.UpdateBestQC(bestQC, chain.LocalCommit)
 
 	// XXX: broadcast the new block to all peers
-	comm.GetGlobCommInst().BroadcastBlock(blk)
+	conR.syncer().BroadcastBlock(blk)
 	// successfully added the block, update the current hight of consensus
 	conR.logger.Info("Block committed", "height", blk.Header().Number(), "id", blk.Header().ID())
 	fmt.Println(blk.String())
@@ -6,11 +6,8 @@
 package consensus
 
 import (
-	"bytes"
 	"fmt"
 	"net"
-	"net/http"
-	"time"
 
 	"github.com/dfinlab/meter/types"
 	"github.com/inconshreveable/log15"
@@ -22,34 +19,31 @@ type ConsensusPeer struct {
 	netAddr types.NetAddress
 	logger  log15.Logger
 	magic   [4]byte
+
+	transport Transport
 }
 
-func newConsensusPeer(name string, ip net.IP, port uint16, magic [4]byte) *ConsensusPeer {
+func newConsensusPeer(name string, ip net.IP, port uint16, magic [4]byte, transport Transport) *ConsensusPeer {
 	return &ConsensusPeer{
 		name: name,
 		netAddr: types.NetAddress{
 			IP:   ip,
 			Port: port,
 		},
-		logger: log15.New("pkg", "peer", "peer", name, "ip", ip.String()),
-		magic:  magic,
+		logger:    log15.New("pkg", "peer", "peer", name, "ip", ip.String()),
+		magic:     magic,
+		transport: transport,
 	}
 }
 
 func (peer *ConsensusPeer) sendPacemakerMsg(rawData []byte, msgSummary string, relay bool) error {
-	// full size message may taker longer time (> 2s) to complete the tranport.
-	var netClient = &http.Client{
-		Timeout: time.Second * 4, // 2
-	}
-
 	prefix := "Send>>"
 	if relay {
 		prefix = "Relay>>"
 	}
 	peer.logger.Info(prefix+" "+msgSummary, "size", len(rawData))
 
-	url := "http://" + peer.netAddr.IP.String() + ":8670/pacemaker"
-	_, err := netClient.Post(url, "application/json", bytes.NewBuffer(rawData))
+	err := peer.transport.SendPacemakerMsg(peer.netAddr, rawData)
 	if err != nil {
 		peer.logger.Error("Failed to send message to peer", "err", err)
 		return err
@@ -58,17 +52,12 @@ func (peer *ConsensusPeer) sendPacemakerMsg(rawData []byte, msgSummary string, r
 }
 
 func (peer *ConsensusPeer) sendCommitteeMsg(rawData []byte, msgSummary string, relay bool) error {
-	var netClient = &http.Client{
-		Timeout: time.Second * 4,
-	}
-
 	prefix := "Send>>"
 	if relay {
 		prefix = "Relay>>"
 	}
 	peer.logger.Info(prefix+" "+msgSummary, "size", len(rawData))
-	url := "http://" + peer.netAddr.IP.String() + ":8670/committee"
-	_, err := netClient.Post(url, "application/json", bytes.NewBuffer(rawData))
+	err := peer.transport.SendCommitteeMsg(peer.netAddr, rawData)
 	if err != nil {
 		peer.logger.Error("Failed to send message to peer", "err", err)
 		return err
@@ -57,6 +57,7 @@ type ConsensusMessage interface {
 	MsgType() byte
 	Header() *ConsensusMsgCommonHeader
 	SigningHash() meter.Bytes32
+	SigningData() []byte
 }
 
 func RegisterConsensusMessages(cdc *amino.Codec) {
@@ -140,18 +141,21 @@ type AnnounceCommitteeMessage struct {
 
 // SigningHash computes hash of all header fields excluding signature.
 func (m *AnnounceCommitteeMessage) SigningHash() (hash meter.Bytes32) {
-	hw := meter.NewBlake2b()
+	return meter.Blake2b(m.SigningData())
+}
+
+// SigningData returns the rlp encoded header fields excluding signature, which is hashed to sign.
+func (m *AnnounceCommitteeMessage) SigningData() []byte {
 	data := append(m.CSMsgCommonHeader.fields(),
 		m.AnnouncerID, m.AnnouncerBlsPK,
 		m.CommitteeSize, m.Nonce, m.KBlockHeight, m.POWBlockHeight,
 		m.VotingBitArray, m.VotingMsgHash, m.VotingAggSig,
 	)
-	err := rlp.Encode(hw, data)
+	raw, err := rlp.EncodeToBytes(data)
 	if err != nil {
 		fmt.Println("RLP Encode Error: ", err)
 	}
-	hw.Sum(hash[:0])
-	return
+	return raw
 }
 
 // String returns a string representation.
@@ -185,17 +189,20 @@ type CommitCommitteeMessage struct {
 
 // SigningHash computes hash of all header fields excluding signature.
 func (m *CommitCommitteeMessage) SigningHash() (hash meter.Bytes32) {
-	hw := meter.NewBlake2b()
+	return meter.Blake2b(m.SigningData())
+}
+
+// SigningData returns the rlp encoded header fields excluding signature, which is hashed to sign.
+func (m *CommitCommitteeMessage) SigningData() []byte {
 	data := append(m.CSMsgCommonHeader.fields(),
 		m.CommitterID, m.CommitterBlsPK, m.CommitterIndex,
 		m.BlsSignature, m.SignedMsgHash,
 	)
-	err := rlp.Encode(hw, data)
+	raw, err := rlp.EncodeToBytes(data)
 	if err != nil {
 		fmt.Println("RLP Encode Error: ", err)
 	}
-	hw.Sum(hash[:0])
-	return
+	return raw
 }
 
 // String returns a string representation.
@@ -239,19 +246,22 @@ type NotaryAnnounceMessage struct {
 
 // SigningHash computes hash of all header fields excluding signature.
 func (m *NotaryAnnounceMessage) SigningHash() (hash meter.Bytes32) {
-	hw := meter.NewBlake2b()
+	return meter.Blake2b(m.SigningData())
+}
+
+// SigningData returns the rlp encoded header fields excluding signature, which is hashed to sign.
+func (m *NotaryAnnounceMessage) SigningData() []byte {
 	data := append(m.CSMsgCommonHeader.fields(),
 		m.AnnouncerID, m.AnnouncerBlsPK,
 		m.VotingBitArray, m.VotingMsgHash, m.VotingAggSig,
 		m.NotarizeBitArray, m.NotarizeMsgHash, m.NotarizeAggSig,
 		m.CommitteeSize, m.CommitteeMembers,
 	)
-	err := rlp.Encode(hw, data)
+	raw, err := rlp.EncodeToBytes(data)
 	if err != nil {
 		fmt.Println("RLP Encode Error: ", err)
 	}
-	hw.Sum(hash[:0])
-	return
+	return raw
 }
 
 // String returns a string representation.
@@ -291,17 +301,20 @@ type NewCommitteeMessage struct {
 
 // SigningHash computes hash of all header fields excluding signature.
 func (m *NewCommitteeMessage) SigningHash() (hash meter.Bytes32) {
-	hw := meter.NewBlake2b()
+	return meter.Blake2b(m.SigningData())
+}
+
+// SigningData returns the rlp encoded header fields excluding signature, which is hashed to sign.
+func (m *NewCommitteeMessage) SigningData() []byte {
 	data := append(m.CSMsgCommonHeader.fields(),
 		m.NewLeaderID, m.ValidatorID, m.ValidatorBlsPK,
 		m.NextEpochID, m.Nonce, m.KBlockHeight, m.SignedMsgHash, m.BlsSignature,
 	)
-	err := rlp.Encode(hw, data)
+	raw, err := rlp.EncodeToBytes(data)
 	if err != nil {
 		fmt.Println("RLP Encode Error: ", err)
 	}
-	hw.Sum(hash[:0])
-	return
+	return raw
 }
 
 // String returns a string representation.
@@ -340,19 +353,22 @@ type PMProposalMessage struct {
 
 // SigningHash computes hash of all header fields excluding signature.
 func (m *PMProposalMessage) SigningHash() (hash meter.Bytes32) {
-	hw := meter.NewBlake2b()
+	return meter.Blake2b(m.SigningData())
+}
+
+// SigningData returns the rlp encoded header fields excluding signature, which is hashed to sign.
+func (m *PMProposalMessage) SigningData() []byte {
 	data := append(m.CSMsgCommonHeader.fields(),
 		m.ParentHeight, m.ParentRound,
 		m.ProposerID, m.ProposerBlsPK,
 		m.ProposedSize, m.ProposedBlock, m.ProposedBlockType,
 		m.KBlockHeight, m.TimeoutCert,
 	)
-	err := rlp.Encode(hw, data)
+	raw, err := rlp.EncodeToBytes(data)
 	if err != nil {
 		fmt.Println("RLP Encode Error: ", err)
 	}
-	hw.Sum(hash[:0])
-	return
+	return raw
 }
 
 // String returns a string representation.
@@ -395,17 +411,20 @@ type PMVoteMessage struct {
 
 // SigningHash computes hash of all header fields excluding signature.
 func (m *PMVoteMessage) SigningHash() (hash meter.Bytes32) {
-	hw := meter.NewBlake2b()
+	return meter.Blake2b(m.SigningData())
+}
+
+// SigningData returns the rlp encoded header fields excluding signature, which is hashed to sign.
+func (m *PMVoteMessage) SigningData() []byte {
 	data := append(m.CSMsgCommonHeader.fields(),
 		m.VoterIndex, m.VoterID, m.VoterBlsPK,
 		m.BlsSignature, m.SignedMessageHash,
 	)
-	err := rlp.Encode(hw, data)
+	raw, err := rlp.EncodeToBytes(data)
 	if err != nil {
 		fmt.Println("RLP Encode Error: ", err)
 	}
-	hw.Sum(hash[:0])
-	return
+	return raw
 }
 
 // String returns a string representation.
@@ -445,19 +464,22 @@ type PMNewViewMessage struct {
 
 // SigningHash computes hash of all header fields excluding signature.
 func (m *PMNewViewMessage) SigningHash() (hash meter.Bytes32) {
-	hw := meter.NewBlake2b()
+	return meter.Blake2b(m.SigningData())
+}
+
+// SigningData returns the rlp encoded header fields excluding signature, which is hashed to sign.
+func (m *PMNewViewMessage) SigningData() []byte {
 	data := append(m.CSMsgCommonHeader.fields(),
 		m.QCHeight, m.QCRound, m.QCHigh, m.Reason,
 		m.TimeoutHeight, m.TimeoutRound, m.TimeoutCounter,
 		m.PeerID, m.PeerIndex,
 		m.SignedMessageHash, m.PeerSignature,
 	)
-	err := rlp.Encode(hw, data)
+	raw, err := rlp.EncodeToBytes(data)
 	if err != nil {
 		fmt.Println("RLP Encode Error: ", err)
 	}
-	hw.Sum(hash[:0])
-	return
+	return raw
 }
 
 // String returns a string representation.
@@ -486,19 +508,22 @@ type PMQueryProposalMessage struct {
 
 // SigningHash computes hash of all header fields excluding signature.
 func (m *PMQueryProposalMessage) SigningHash() (hash meter.Bytes32) {
-	hw := meter.NewBlake2b()
+	return meter.Blake2b(m.SigningData())
+}
+
+// SigningData returns the rlp encoded header fields excluding signature, which is hashed to sign.
+func (m *PMQueryProposalMessage) SigningData() []byte {
 	data := append(m.CSMsgCommonHeader.fields(),
 		m.FromHeight,
 		m.ToHeight,
 		m.Round,
 		m.ReturnAddr,
 	)
-	err := rlp.Encode(hw, data)
+	raw, err := rlp.EncodeToBytes(data)
 	if err != nil {
 		fmt.Println("RLP Encode Error: ", err)
 	}
-	hw.Sum(hash[:0])
-	return
+	return raw
 }
 
 // String returns a string representation.
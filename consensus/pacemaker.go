@@ -69,6 +69,8 @@ type Pacemaker struct {
 
 	// HotStuff fields
 	lastVotingHeight uint32
+	lastVotingRound  uint32
+	restored         *PMSafetyState
 	QCHigh           *pmQuorumCert
 	blockLeaf        *pmBlock
 	blockExecuted    *pmBlock
@@ -79,6 +81,7 @@ type Pacemaker struct {
 	roundTimeoutCh chan PMRoundTimeoutInfo
 	cmdCh          chan *PMCmdInfo
 	beatCh         chan *PMBeatInfo
+	statusCh       chan chan *PMStatus
 
 	// Timeout
 	roundTimer         *time.Timer
@@ -98,6 +101,7 @@ func NewPaceMaker(conR *ConsensusReactor) *Pacemaker {
 		cmdCh:          make(chan *PMCmdInfo, 2),
 		beatCh:         make(chan *PMBeatInfo, 2),
 		roundTimeoutCh: make(chan PMRoundTimeoutInfo, 2),
+		statusCh:       make(chan chan *PMStatus),
 		roundTimer:     nil,
 		proposalMap:    NewProposalMap(),
 		pendingList:    NewPendingList(),
@@ -190,6 +194,7 @@ func (p *Pacemaker) Update(bnew *pmBlock) error {
 
 	if blockPrime.Height > p.blockLocked.Height {
 		p.blockLocked = blockPrime // commit phase on b'
+		p.persistLock()
 	}
 
 	/* commit requires direct parent */
@@ -360,8 +365,9 @@ func (p *Pacemaker) OnReceiveProposal(mi *consensusMsgInfo) error {
 	}
 
 	bnew := p.proposalMap.Get(height)
-	if ((bnew.Height > p.lastVotingHeight) &&
-		(p.IsExtendedFromBLocked(bnew) || bnew.Justify.QC.QCHeight > p.blockLocked.Height)) || validTimeout {
+	if (((bnew.Height > p.lastVotingHeight) &&
+		(p.IsExtendedFromBLocked(bnew) || bnew.Justify.QC.QCHeight > p.blockLocked.Height)) || validTimeout) &&
+		p.satisfyRestoredState(bnew) {
 
 		if validTimeout {
 			p.updateCurrentRound(bnew.Round, UpdateOnTimeoutCertProposal)
@@ -394,9 +400,12 @@ func (p *Pacemaker) OnReceiveProposal(mi *consensusMsgInfo) error {
 			if err != nil {
 				return err
 			}
+			// persist before sending, a restarted node must remember this vote
+			if err := p.persistVote(bnew.Height, bnew.Round); err != nil {
+				return err
+			}
 			// send vote message to leader
 			p.SendConsensusMessage(proposalMsg.CSMsgCommonHeader.Round, msg, false)
-			p.lastVotingHeight = bnew.Height
 		}
 	}
 
@@ -816,6 +825,16 @@ func (p *Pacemaker) Start(newCommittee bool, mode PMMode) {
 	}
 	p.QCHigh = qcInit
 
+	// without the saved state the last vote is unknown, don't vote before catching up
+	state, err := p.csReactor.pmState.Load()
+	if err != nil {
+		p.logger.Error("load pacemaker state failed, start in catch-up mode", "err", err)
+		p.mode = PMModeCatchUp
+	} else if p.restoreState(state, p.csReactor.curEpoch) && p.mode == PMModeNormal {
+		p.logger.Info("saved QCHigh is ahead, start in catch-up mode")
+		p.mode = PMModeCatchUp
+	}
+
 	p.stopped = false
 	pmRunningGauge.Set(1)
 
@@ -837,7 +856,7 @@ func (p *Pacemaker) SendCatchUpQuery() {
 	}
 
 	leader := p.csReactor.curCommittee.Validators[0]
-	leaderPeer := newConsensusPeer(leader.Name, leader.NetAddr.IP, leader.NetAddr.Port, p.csReactor.magic)
+	leaderPeer := newConsensusPeer(leader.Name, leader.NetAddr.IP, leader.NetAddr.Port, p.csReactor.magic, p.csReactor.transport())
 	err = p.sendQueryProposalMsg(p.blockLocked.Height, 0, p.currentRound, bestQC.EpochID, leaderPeer)
 	if err != nil {
 		fmt.Println("could not send query proposal, error:", err)
@@ -880,6 +899,8 @@ func (p *Pacemaker) mainLoop() {
 			p.OnRoundTimeout(ti)
 		case b := <-p.beatCh:
 			err = p.OnBeat(b.height, b.round, b.reason)
+		case ch := <-p.statusCh:
+			ch <- p.status()
 		case m := <-p.pacemakerMsgCh:
 			if m.Msg.EpochID() != p.csReactor.curEpoch {
 				p.logger.Info("receives message w/ mismatched epoch ID", "epoch", m.Msg.EpochID(), "myEpoch", p.csReactor.curEpoch, "type", getConcreteName(m.Msg))
@@ -949,6 +970,8 @@ func (p *Pacemaker) SendKblockInfo(b *pmBlock) {
 func (p *Pacemaker) reset() {
 	pmRoleGauge.Set(0)
 	p.lastVotingHeight = 0
+	p.lastVotingRound = 0
+	p.restored = nil
 	p.QCHigh = nil
 	p.blockLeaf = nil
 	p.blockExecuted = nil
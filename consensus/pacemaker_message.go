@@ -27,7 +27,7 @@ func (p *Pacemaker) proposeBlock(parentBlock *block.Block, height, round uint32,
 	var proposalKBlock bool = false
 	var powResults *powpool.PowResult
 	if (height-p.startHeight) >= p.minMBlocks && !timeout {
-		proposalKBlock, powResults = powpool.GetGlobPowPoolInst().GetPowDecision()
+		proposalKBlock, powResults = p.csReactor.powPool().GetPowDecision()
 	}
 
 	var blockBytes []byte
@@ -37,9 +37,9 @@ func (p *Pacemaker) proposeBlock(parentBlock *block.Block, height, round uint32,
 	if proposalKBlock {
 		data := &block.KBlockData{uint64(powResults.Nonce), powResults.Raw}
 		rewards := powResults.Rewards
-		blkInfo = p.csReactor.BuildKBlock(parentBlock, data, rewards)
+		blkInfo = p.csReactor.BuildKBlock(parentBlock, round, data, rewards)
 	} else {
-		blkInfo = p.csReactor.BuildMBlock(parentBlock)
+		blkInfo = p.csReactor.BuildMBlock(parentBlock, round)
 		lastKBlockHeight := blkInfo.ProposedBlock.Header().LastKBlockHeight()
 		blockNumber := blkInfo.ProposedBlock.Header().Number()
 		if round == 0 || blockNumber == lastKBlockHeight+1 {
@@ -58,7 +58,7 @@ func (p *Pacemaker) proposeStopCommitteeBlock(parentBlock *block.Block, height,
 	var blockBytes []byte
 	var blkInfo *ProposedBlockInfo
 
-	blkInfo = p.csReactor.BuildStopCommitteeBlock(parentBlock)
+	blkInfo = p.csReactor.BuildStopCommitteeBlock(parentBlock, round)
 	p.packQuorumCert(blkInfo.ProposedBlock, qc)
 	blockBytes = block.BlockEncodeBytes(blkInfo.ProposedBlock)
 
@@ -118,8 +118,8 @@ func (p *Pacemaker) BuildProposalMessage(height, round uint32, bnew *pmBlock, tc
 		TimeoutCert: tc,
 	}
 
-	// sign message
-	msgSig, err := p.csReactor.SignConsensusMsg(msg.SigningHash().Bytes())
+	// sign message, the signer refuses to propose another block at the same height and round
+	msgSig, err := p.csReactor.signer.SignProposal(p.csReactor.curEpoch, height, round, meter.Blake2b(blockBytes), msg.SigningData())
 	if err != nil {
 		p.logger.Error("Sign message failed", "error", err)
 		return nil, err
@@ -137,7 +137,12 @@ func (p *Pacemaker) BuildVoteForProposalMessage(proposalMsg *PMProposalMessage,
 	ch := proposalMsg.CSMsgCommonHeader
 
 	signMsg := p.csReactor.BuildProposalBlockSignMsg(uint32(proposalMsg.ProposedBlockType), uint64(ch.Height), &blockID, &txsRoot, &stateRoot)
-	sign, msgHash := p.csReactor.csCommon.SignMessage([]byte(signMsg))
+	msgHash := p.csReactor.csCommon.Hash256Msg([]byte(signMsg))
+	blsSig, err := p.csReactor.signer.SignVote(ch.EpochID, ch.Height, ch.Round, []byte(signMsg))
+	if err != nil {
+		p.logger.Error("Sign vote failed", "error", err)
+		return nil, err
+	}
 	p.logger.Debug("Built PMVoteMessage", "signMsg", signMsg)
 
 	cmnHdr := ConsensusMsgCommonHeader{
@@ -156,13 +161,13 @@ func (p *Pacemaker) BuildVoteForProposalMessage(proposalMsg *PMProposalMessage,
 
 		VoterID:           crypto.FromECDSAPub(&p.csReactor.myPubKey),
 		VoterBlsPK:        p.csReactor.csCommon.GetSystem().PubKeyToBytes(*p.csReactor.csCommon.GetPublicKey()),
-		BlsSignature:      p.csReactor.csCommon.GetSystem().SigToBytes(sign),
+		BlsSignature:      blsSig,
 		VoterIndex:        uint32(index),
 		SignedMessageHash: msgHash,
 	}
 
 	// sign message
-	msgSig, err := p.csReactor.SignConsensusMsg(msg.SigningHash().Bytes())
+	msgSig, err := p.csReactor.SignConsensusMsg(msg)
 	if err != nil {
 		p.logger.Error("Sign message failed", "error", err)
 		return nil, err
@@ -189,7 +194,11 @@ func (p *Pacemaker) BuildNewViewMessage(nextHeight, nextRound uint32, qcHigh *pm
 
 	signMsg := p.BuildNewViewSignMsg(p.csReactor.myPubKey, reason, nextHeight, nextRound, qcHigh.QC)
 
-	sign, msgHash := p.csReactor.csCommon.SignMessage([]byte(signMsg))
+	sign, msgHash, err := p.csReactor.SignBlsMsg([]byte(signMsg))
+	if err != nil {
+		p.logger.Error("Sign message failed", "error", err)
+		return nil, err
+	}
 
 	qcBytes, err := rlp.EncodeToBytes(qcHigh.QC)
 	if err != nil {
@@ -215,7 +224,7 @@ func (p *Pacemaker) BuildNewViewMessage(nextHeight, nextRound uint32, qcHigh *pm
 		msg.TimeoutCounter = ti.counter
 	}
 	// sign message
-	msgSig, err := p.csReactor.SignConsensusMsg(msg.SigningHash().Bytes())
+	msgSig, err := p.csReactor.SignConsensusMsg(msg)
 	if err != nil {
 		p.logger.Error("Sign message failed", "error", err)
 		return nil, err
@@ -251,7 +260,7 @@ func (p *Pacemaker) BuildQueryProposalMessage(fromHeight, toHeight, round uint32
 	}
 
 	// sign message
-	msgSig, err := p.csReactor.SignConsensusMsg(msg.SigningHash().Bytes())
+	msgSig, err := p.csReactor.SignConsensusMsg(msg)
 	if err != nil {
 		p.logger.Error("Sign message failed", "error", err)
 		return nil, err
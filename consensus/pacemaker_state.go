@@ -0,0 +1,246 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package consensus
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/dfinlab/meter/block"
+	"github.com/dfinlab/meter/meter"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
+)
+
+// PMSafetyState is the safety-critical state of pacemaker. It is persisted before
+// each vote and restored on start, so a restarted node never votes twice at the
+// same height, nor votes against the block it locked on.
+type PMSafetyState struct {
+	EpochID         uint64
+	LastVotedHeight uint32
+	LastVotedRound  uint32
+	LockedHeight    uint32
+	LockedRound     uint32
+	LockedID        meter.Bytes32
+	QCHigh          *block.QuorumCert
+}
+
+func (s *PMSafetyState) String() string {
+	return fmt.Sprintf("PMSafetyState{Epoch:%v, LastVoted:(H:%v,R:%v), Locked:(H:%v,R:%v,ID:%v), QCHigh:%v}",
+		s.EpochID, s.LastVotedHeight, s.LastVotedRound, s.LockedHeight, s.LockedRound, s.LockedID, s.QCHigh.CompactString())
+}
+
+// PMStateStore persists the safety state of pacemaker.
+type PMStateStore interface {
+	// Load returns the last saved state, nil if nothing saved.
+	Load() (*PMSafetyState, error)
+	// Save replaces the saved state atomically.
+	Save(s *PMSafetyState) error
+}
+
+type pmStateFile struct {
+	path string
+}
+
+// NewPMStateStore creates the store persisted to the file at path.
+func NewPMStateStore(path string) PMStateStore {
+	return &pmStateFile{path}
+}
+
+func (f *pmStateFile) Load() (*PMSafetyState, error) {
+	data, err := ioutil.ReadFile(f.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var s PMSafetyState
+	if err := rlp.DecodeBytes(data, &s); err != nil {
+		return nil, errors.WithMessage(err, "decode pacemaker state")
+	}
+	return &s, nil
+}
+
+// Save writes to a temp file and renames it, a crash while saving leaves the
+// previous state intact. The directory is synced as well, or the rename might
+// not survive a crash.
+func (f *pmStateFile) Save(s *PMSafetyState) error {
+	data, err := rlp.EncodeToBytes(s)
+	if err != nil {
+		return err
+	}
+	tmp := f.path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, f.path); err != nil {
+		return err
+	}
+	dir, err := os.Open(filepath.Dir(f.path))
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	return dir.Sync()
+}
+
+type pmStateMem struct {
+	lock  sync.Mutex
+	state *PMSafetyState
+}
+
+// NewMemPMStateStore creates the store without persistence, state survives
+// pacemaker restarts but not process restarts.
+func NewMemPMStateStore() PMStateStore {
+	return &pmStateMem{}
+}
+
+func (m *pmStateMem) Load() (*PMSafetyState, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.state == nil {
+		return nil, nil
+	}
+	s := *m.state
+	return &s, nil
+}
+
+func (m *pmStateMem) Save(s *PMSafetyState) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	cpy := *s
+	m.state = &cpy
+	return nil
+}
+
+func pmBlockID(b *pmBlock) meter.Bytes32 {
+	if b == nil || len(b.ProposedBlock) == 0 {
+		return meter.Bytes32{}
+	}
+	blk, err := block.BlockDecodeFromBytes(b.ProposedBlock)
+	if err != nil {
+		return meter.Bytes32{}
+	}
+	return blk.Header().ID()
+}
+
+// safetyState snapshots the current safety state with the given vote.
+func (p *Pacemaker) safetyState(votedHeight, votedRound uint32) *PMSafetyState {
+	s := &PMSafetyState{
+		EpochID:         p.csReactor.curEpoch,
+		LastVotedHeight: votedHeight,
+		LastVotedRound:  votedRound,
+		QCHigh:          &block.QuorumCert{},
+	}
+	if p.blockLocked != nil {
+		s.LockedHeight, s.LockedRound, s.LockedID = p.blockLocked.Height, p.blockLocked.Round, pmBlockID(p.blockLocked)
+	}
+	if r := p.restored; r != nil {
+		if r.LastVotedHeight > s.LastVotedHeight {
+			s.LastVotedHeight, s.LastVotedRound = r.LastVotedHeight, r.LastVotedRound
+		}
+		if r.LockedHeight > s.LockedHeight {
+			s.LockedHeight, s.LockedRound, s.LockedID = r.LockedHeight, r.LockedRound, r.LockedID
+		}
+	}
+	if p.QCHigh != nil && p.QCHigh.QC != nil {
+		s.QCHigh = p.QCHigh.QC
+	}
+	return s
+}
+
+// persistVote saves the safety state with the vote at height and round. The vote
+// must not be sent if it fails.
+func (p *Pacemaker) persistVote(height, round uint32) error {
+	if err := p.csReactor.pmState.Save(p.safetyState(height, round)); err != nil {
+		return errors.WithMessage(err, "save pacemaker state")
+	}
+	p.lastVotingHeight = height
+	p.lastVotingRound = round
+	p.releaseRestored()
+	return nil
+}
+
+// persistLock saves the safety state after b_lock moved forward.
+func (p *Pacemaker) persistLock() {
+	if err := p.csReactor.pmState.Save(p.safetyState(p.lastVotingHeight, p.lastVotingRound)); err != nil {
+		p.logger.Error("save pacemaker state failed", "err", err)
+	}
+	p.releaseRestored()
+}
+
+// restoreState applies the saved safety state of the same epoch on start. The
+// restored vote and lock are kept aside from lastVotingHeight and b_lock, since
+// the proposals they refer to are not tracked after restart. It returns true if
+// the saved QCHigh is ahead of the start point, which means the pacemaker has to
+// catch up before voting.
+func (p *Pacemaker) restoreState(s *PMSafetyState, epoch uint64) bool {
+	if s == nil {
+		return false
+	}
+	if s.EpochID != epoch {
+		p.logger.Info("ignore pacemaker state of another epoch", "state", s.String(), "epoch", epoch)
+		return false
+	}
+	p.logger.Info("restore pacemaker state", "state", s.String())
+
+	p.restored = s
+	p.releaseRestored()
+	return s.QCHigh != nil && s.QCHigh.QCHeight > p.QCHigh.QC.QCHeight
+}
+
+// releaseRestored drops the restored state once the live state passed it.
+func (p *Pacemaker) releaseRestored() {
+	r := p.restored
+	if r == nil {
+		return
+	}
+	if p.lastVotingHeight >= r.LastVotedHeight && p.blockLocked.Height >= r.LockedHeight {
+		p.restored = nil
+	}
+}
+
+// satisfyRestoredState checks the proposal against the vote and lock restored
+// from the safety state. A height already voted can only be voted again in a
+// higher round, which requires a timeout cert. The lock holds if b extends the
+// locked block, or b's justify is higher than the locked block.
+func (p *Pacemaker) satisfyRestoredState(b *pmBlock) bool {
+	r := p.restored
+	if r == nil {
+		return true
+	}
+	if b.Height < r.LastVotedHeight || (b.Height == r.LastVotedHeight && b.Round <= r.LastVotedRound) {
+		return false
+	}
+	if r.LockedHeight <= p.blockLocked.Height {
+		return true
+	}
+	if b.Justify != nil && b.Justify.QC.QCHeight > r.LockedHeight {
+		return true
+	}
+	for i, tmp := 0, b; i < 10 && tmp != nil && tmp.Height >= r.LockedHeight; i, tmp = i+1, tmp.Parent {
+		if tmp.Height == r.LockedHeight {
+			return pmBlockID(tmp) == r.LockedID
+		}
+	}
+	return false
+}
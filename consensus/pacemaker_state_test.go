@@ -0,0 +1,225 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package consensus
+
+import (
+	"crypto/ecdsa"
+	"encoding/binary"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dfinlab/meter/block"
+	"github.com/dfinlab/meter/meter"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingPMStateStore struct{}
+
+func (failingPMStateStore) Load() (*PMSafetyState, error) { return nil, nil }
+func (failingPMStateStore) Save(*PMSafetyState) error     { return errors.New("disk full") }
+
+type pmTestEnv struct {
+	t     *testing.T
+	key   *ecdsa.PrivateKey
+	store PMStateStore
+	epoch uint64
+}
+
+func newPMTestEnv(t *testing.T) *pmTestEnv {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &pmTestEnv{t: t, key: key, store: NewMemPMStateStore(), epoch: 1}
+}
+
+// newBlock creates a proposal at height extending parent, with justify QC at qcHeight.
+// Blocks differ in ts if they are at the same height.
+func (env *pmTestEnv) newBlock(parent *pmBlock, height, round, qcHeight uint32, ts uint64) *pmBlock {
+	var parentID meter.Bytes32
+	binary.BigEndian.PutUint32(parentID[:], height-1)
+	blk := new(block.Builder).ParentID(parentID).Timestamp(ts).Build()
+	sig, err := crypto.Sign(blk.Header().SigningHash().Bytes(), env.key)
+	if err != nil {
+		env.t.Fatal(err)
+	}
+	blk = blk.WithSignature(sig).SetQC(&block.QuorumCert{QCHeight: qcHeight, EpochID: env.epoch})
+	return &pmBlock{
+		Height:        height,
+		Round:         round,
+		Parent:        parent,
+		Justify:       newPMQuorumCert(blk.QC, parent),
+		ProposedBlock: block.BlockEncodeBytes(blk),
+	}
+}
+
+// start creates a pacemaker on the store like Pacemaker.Start does at bInit,
+// and returns whether it has to catch up.
+func (env *pmTestEnv) start(bInit *pmBlock) (*Pacemaker, bool) {
+	p := NewPaceMaker(&ConsensusReactor{pmState: env.store, curEpoch: env.epoch})
+	p.blockLocked = bInit
+	p.blockExecuted = bInit
+	p.blockLeaf = bInit
+	p.QCHigh = newPMQuorumCert(&block.QuorumCert{QCHeight: bInit.Height, EpochID: env.epoch}, bInit)
+	p.proposalMap.Add(bInit)
+
+	state, err := env.store.Load()
+	if err != nil {
+		env.t.Fatal(err)
+	}
+	return p, p.restoreState(state, env.epoch)
+}
+
+func TestPMStateFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pmstate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "pacemaker-state.rlp")
+	store := NewPMStateStore(path)
+
+	s, err := store.Load()
+	assert.Nil(t, err)
+	assert.Nil(t, s, "nothing saved")
+
+	saved := &PMSafetyState{
+		EpochID:         3,
+		LastVotedHeight: 11,
+		LastVotedRound:  2,
+		LockedHeight:    9,
+		LockedRound:     1,
+		LockedID:        meter.BytesToBytes32([]byte("locked")),
+		QCHigh:          &block.QuorumCert{QCHeight: 10, QCRound: 1, EpochID: 3},
+	}
+	assert.Nil(t, store.Save(saved))
+
+	// crash while saving the next state leaves a partial temp file
+	assert.Nil(t, ioutil.WriteFile(path+".tmp", []byte{0xde, 0xad}, 0600))
+
+	s, err = NewPMStateStore(path).Load()
+	assert.Nil(t, err)
+	assert.Equal(t, saved.String(), s.String())
+	assert.Equal(t, saved.LockedID, s.LockedID)
+
+	assert.Nil(t, ioutil.WriteFile(path, []byte{0xde, 0xad}, 0600))
+	_, err = store.Load()
+	assert.NotNil(t, err, "corrupted state")
+}
+
+func TestPMRestartBeforeVote(t *testing.T) {
+	env := newPMTestEnv(t)
+	b10 := env.newBlock(nil, 10, 0, 9, 100)
+
+	p, catchUp := env.start(b10)
+	assert.False(t, catchUp)
+	assert.Nil(t, p.restored)
+	assert.True(t, p.satisfyRestoredState(env.newBlock(b10, 11, 0, 10, 110)))
+}
+
+func TestPMRestartAfterVote(t *testing.T) {
+	env := newPMTestEnv(t)
+	b10 := env.newBlock(nil, 10, 0, 9, 100)
+
+	p, _ := env.start(b10)
+	assert.Nil(t, p.persistVote(11, 1))
+
+	// crash after the vote is persisted, no matter it's sent or not
+	p, catchUp := env.start(b10)
+	assert.False(t, catchUp)
+	assert.Equal(t, uint32(0), p.lastVotingHeight, "proposals before restart are not tracked")
+
+	assert.False(t, p.satisfyRestoredState(env.newBlock(b10, 11, 1, 10, 111)), "same height and round")
+	assert.False(t, p.satisfyRestoredState(env.newBlock(b10, 11, 0, 10, 112)), "lower round")
+	assert.True(t, p.satisfyRestoredState(env.newBlock(b10, 11, 2, 10, 113)), "higher round")
+	b12 := env.newBlock(b10, 12, 2, 10, 120)
+	assert.True(t, p.satisfyRestoredState(b12))
+
+	// the restored vote is released once the live vote passed it
+	assert.Nil(t, p.persistVote(12, 2))
+	assert.Nil(t, p.restored)
+
+	s, _ := env.store.Load()
+	assert.Equal(t, uint32(12), s.LastVotedHeight)
+	assert.Equal(t, uint32(2), s.LastVotedRound)
+}
+
+func TestPMRestartAfterLock(t *testing.T) {
+	env := newPMTestEnv(t)
+	b10 := env.newBlock(nil, 10, 0, 9, 100)
+	b11 := env.newBlock(b10, 11, 1, 10, 110)
+	b12 := env.newBlock(b11, 12, 2, 11, 120)
+
+	p, _ := env.start(b10)
+	assert.Nil(t, p.persistVote(13, 3))
+	p.blockLocked = b12
+	p.persistLock()
+
+	// crash after locked on b12, before it's committed
+	p, _ = env.start(b10)
+	assert.NotNil(t, p.restored)
+
+	conflict := env.newBlock(b11, 12, 4, 11, 121)
+	assert.False(t, p.satisfyRestoredState(env.newBlock(conflict, 14, 4, 12, 140)), "conflicts with the lock")
+	assert.True(t, p.satisfyRestoredState(env.newBlock(b12, 14, 4, 12, 141)), "extends the lock")
+	assert.True(t, p.satisfyRestoredState(env.newBlock(conflict, 14, 4, 13, 142)), "justify higher than the lock")
+
+	// crash again before voting, the restored lock must survive
+	p, _ = env.start(b10)
+	assert.NotNil(t, p.restored)
+	s, _ := env.store.Load()
+	assert.Equal(t, uint32(12), s.LockedHeight)
+	assert.Equal(t, pmBlockID(b12), s.LockedID)
+
+	// the restored lock is released once the live lock passed it
+	p.lastVotingHeight = 14
+	p.blockLocked = env.newBlock(b12, 13, 3, 12, 130)
+	p.persistLock()
+	assert.Nil(t, p.restored)
+}
+
+func TestPMRestartQCHighAhead(t *testing.T) {
+	env := newPMTestEnv(t)
+	b10 := env.newBlock(nil, 10, 0, 9, 100)
+	b11 := env.newBlock(b10, 11, 1, 10, 110)
+
+	p, _ := env.start(b10)
+	p.QCHigh = newPMQuorumCert(&block.QuorumCert{QCHeight: 11, QCRound: 1, EpochID: env.epoch}, b11)
+	assert.Nil(t, p.persistVote(12, 2))
+
+	// crash before the QC is committed into chain
+	_, catchUp := env.start(b10)
+	assert.True(t, catchUp)
+}
+
+func TestPMRestartNewEpoch(t *testing.T) {
+	env := newPMTestEnv(t)
+	b10 := env.newBlock(nil, 10, 0, 9, 100)
+
+	p, _ := env.start(b10)
+	assert.Nil(t, p.persistVote(12, 2))
+
+	// heights of the stop-committee blocks are proposed again in the next epoch
+	env.epoch++
+	p, catchUp := env.start(b10)
+	assert.False(t, catchUp)
+	assert.Nil(t, p.restored)
+}
+
+func TestPMVoteNotPersisted(t *testing.T) {
+	env := newPMTestEnv(t)
+	env.store = failingPMStateStore{}
+	b10 := env.newBlock(nil, 10, 0, 9, 100)
+
+	p, _ := env.start(b10)
+	assert.NotNil(t, p.persistVote(11, 1))
+	assert.Equal(t, uint32(0), p.lastVotingHeight)
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package consensus
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+// PMBlockStatus is the brief of a block in pacemaker.
+type PMBlockStatus struct {
+	Height    uint32 `json:"height"`
+	Round     uint32 `json:"round"`
+	Decided   bool   `json:"decided"`
+	Processed bool   `json:"processed"`
+	Error     string `json:"error,omitempty"`
+}
+
+// PMQCStatus is the brief of a quorum cert in pacemaker.
+type PMQCStatus struct {
+	Height  uint32 `json:"height"`
+	Round   uint32 `json:"round"`
+	EpochID uint64 `json:"epochID"`
+}
+
+// PMPendingStatus is a message pending for its parent.
+type PMPendingStatus struct {
+	Height  uint32 `json:"height"`
+	Message string `json:"message"`
+}
+
+// PMStatus is a snapshot of pacemaker for diagnosis.
+type PMStatus struct {
+	Running          bool               `json:"running"`
+	Mode             string             `json:"mode"`
+	StartHeight      uint32             `json:"startHeight"`
+	StartRound       uint32             `json:"startRound"`
+	CurrentRound     uint32             `json:"currentRound"`
+	LastVotingHeight uint32             `json:"lastVotingHeight"`
+	TimeoutCounter   uint64             `json:"timeoutCounter"`
+	QCHigh           *PMQCStatus        `json:"qcHigh"`
+	BlockLeaf        *PMBlockStatus     `json:"blockLeaf"`
+	BlockExecuted    *PMBlockStatus     `json:"blockExecuted"`
+	BlockLocked      *PMBlockStatus     `json:"blockLocked"`
+	Proposals        []*PMBlockStatus   `json:"proposals"`
+	Pendings         []*PMPendingStatus `json:"pendings"`
+}
+
+func newPMBlockStatus(b *pmBlock) *PMBlockStatus {
+	if b == nil {
+		return nil
+	}
+	s := &PMBlockStatus{
+		Height:    b.Height,
+		Round:     b.Round,
+		Decided:   b.Decided,
+		Processed: b.SuccessProcessed,
+	}
+	if b.ProcessError != nil {
+		s.Error = b.ProcessError.Error()
+	}
+	return s
+}
+
+// statusTimeout is how long Status waits for the main loop, which may be busy
+// processing a proposal.
+const statusTimeout = 5 * time.Second
+
+var errPacemakerNotRunning = errors.New("pacemaker is not running or busy")
+
+// Status returns a snapshot of the pacemaker. The snapshot is built on the main loop,
+// as the maps are not safe for concurrent access.
+func (p *Pacemaker) Status() (*PMStatus, error) {
+	ch := make(chan *PMStatus, 1)
+	timer := time.NewTimer(statusTimeout)
+	defer timer.Stop()
+
+	select {
+	case p.statusCh <- ch:
+	case <-timer.C:
+		return nil, errPacemakerNotRunning
+	}
+	return <-ch, nil
+}
+
+// status must be called on the main loop.
+func (p *Pacemaker) status() *PMStatus {
+	s := &PMStatus{
+		Running:          !p.stopped,
+		Mode:             p.mode.String(),
+		StartHeight:      p.startHeight,
+		StartRound:       p.startRound,
+		CurrentRound:     p.currentRound,
+		LastVotingHeight: p.lastVotingHeight,
+		TimeoutCounter:   p.timeoutCounter,
+		BlockLeaf:        newPMBlockStatus(p.blockLeaf),
+		BlockExecuted:    newPMBlockStatus(p.blockExecuted),
+		BlockLocked:      newPMBlockStatus(p.blockLocked),
+		Proposals:        make([]*PMBlockStatus, 0),
+		Pendings:         make([]*PMPendingStatus, 0),
+	}
+	if qc := p.QCHigh; qc != nil && qc.QC != nil {
+		s.QCHigh = &PMQCStatus{qc.QC.QCHeight, qc.QC.QCRound, qc.QC.EpochID}
+	}
+	for _, key := range p.proposalMap.keys {
+		if b := p.proposalMap.Get(key); b != nil {
+			s.Proposals = append(s.Proposals, newPMBlockStatus(b))
+		}
+	}
+	for height, mi := range p.pendingList.messages {
+		s.Pendings = append(s.Pendings, &PMPendingStatus{height, mi.Msg.String()})
+	}
+	sort.Slice(s.Pendings, func(i, j int) bool {
+		return s.Pendings[i].Height < s.Pendings[j].Height
+	})
+	return s
+}
+
+// PacemakerStatus returns a snapshot of the pacemaker. It fails if the pacemaker
+// is not created, or its main loop doesn't respond in time.
+func (conR *ConsensusReactor) PacemakerStatus() (*PMStatus, error) {
+	if conR.csPacemaker == nil {
+		return nil, errors.New("pacemaker is not created")
+	}
+	return conR.csPacemaker.Status()
+}
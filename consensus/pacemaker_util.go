@@ -9,14 +9,11 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
-	"io/ioutil"
-	"net/http"
 	"strings"
 	"time"
 
 	"github.com/dfinlab/meter/block"
 	"github.com/dfinlab/meter/tx"
-	"github.com/dfinlab/meter/txpool"
 )
 
 const (
@@ -52,19 +49,12 @@ func (p *Pacemaker) AddressBlock(height uint32) *pmBlock {
 	return nil
 }
 
-func (p *Pacemaker) receivePacemakerMsg(w http.ResponseWriter, r *http.Request) {
-	defer r.Body.Close()
-
+func (p *Pacemaker) receivePacemakerMsg(data []byte) {
 	// handle no msg if pacemaker is stopped already
 	if p.stopped {
 		return
 	}
 
-	data, err := ioutil.ReadAll(r.Body)
-	if err != nil {
-		p.logger.Error("Unrecognized payload", "err", err)
-		return
-	}
 	mi, err := p.csReactor.UnmarshalMsg(data)
 	if err != nil {
 		p.logger.Error("Unmarshal error", "err", err)
@@ -145,7 +135,7 @@ func (p *Pacemaker) GetRelayPeers(round uint32) []*ConsensusPeer {
 		}
 		member := p.csReactor.curActualCommittee[index]
 		name := p.csReactor.GetDelegateNameByIP(member.NetAddr.IP)
-		peers = append(peers, newConsensusPeer(name, member.NetAddr.IP, member.NetAddr.Port, p.csReactor.magic))
+		peers = append(peers, newConsensusPeer(name, member.NetAddr.IP, member.NetAddr.Port, p.csReactor.magic, p.csReactor.transport()))
 	}
 	return peers
 }
@@ -193,7 +183,7 @@ func (p *Pacemaker) ValidateProposal(b *pmBlock) error {
 	}
 	parentHeader := parentBlock.Header()
 
-	pool := txpool.GetGlobTxPoolInst()
+	pool := p.csReactor.txPool()
 	if pool == nil {
 		p.logger.Error("get tx pool failed ...")
 		panic("get tx pool failed ...")
@@ -260,7 +250,7 @@ func (p *Pacemaker) ValidateProposal(b *pmBlock) error {
 
 func (p *Pacemaker) getProposerByRound(round uint32) *ConsensusPeer {
 	proposer := p.csReactor.getRoundProposer(round)
-	return newConsensusPeer(proposer.Name, proposer.NetAddr.IP, 8080, p.csReactor.magic)
+	return newConsensusPeer(proposer.Name, proposer.NetAddr.IP, 8080, p.csReactor.magic, p.csReactor.transport())
 }
 
 // ------------------------------------------------------
@@ -269,7 +259,7 @@ func (p *Pacemaker) getProposerByRound(round uint32) *ConsensusPeer {
 func (p *Pacemaker) SendConsensusMessage(round uint32, msg ConsensusMessage, copyMyself bool) bool {
 	myNetAddr := p.csReactor.GetMyNetAddr()
 	myName := p.csReactor.GetMyName()
-	myself := newConsensusPeer(myName, myNetAddr.IP, myNetAddr.Port, p.csReactor.magic)
+	myself := newConsensusPeer(myName, myNetAddr.IP, myNetAddr.Port, p.csReactor.magic, p.csReactor.transport())
 
 	peers := make([]*ConsensusPeer, 0)
 	switch msg.(type) {
@@ -388,7 +378,7 @@ func (p *Pacemaker) sendQueryProposalMsg(fromHeight, toHeight, queryRound uint32
 		for _, cm := range p.csReactor.curActualCommittee {
 			if myNetAddr.IP.Equal(cm.NetAddr.IP) == false {
 				p.logger.Warn("Query PMProposal with new node", "NetAddr", cm.NetAddr)
-				peer = newConsensusPeer(cm.Name, cm.NetAddr.IP, cm.NetAddr.Port, p.csReactor.magic)
+				peer = newConsensusPeer(cm.Name, cm.NetAddr.IP, cm.NetAddr.Port, p.csReactor.magic, p.csReactor.transport())
 				break
 			}
 		}
@@ -0,0 +1,174 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package consensus
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/dfinlab/meter/meter"
+	"github.com/dfinlab/meter/script"
+	"github.com/dfinlab/meter/script/auction"
+	"github.com/dfinlab/meter/state"
+	"github.com/dfinlab/meter/tx"
+	"github.com/dfinlab/meter/xenv"
+)
+
+// KBlockPreview is the outcome of the auction, staking and account lock governing
+// txs of the next kblock, executed on a copy of best state which is never committed.
+type KBlockPreview struct {
+	Epoch      uint64        // epoch closed by the kblock
+	Number     uint32        // number of the kblock
+	ParentID   meter.Bytes32 // id of best block
+	Best       *state.State  // best state
+	State      *state.State  // best state after the txs
+	AuctionDue bool          // auction txs are included in the kblock
+	AuctionEnd bool          // active auction is closed
+}
+
+// PreviewKBlock builds the auction, staking and account lock governing txs as the
+// kblock proposer does and executes them on a copy of best state. If closeAuction
+// is set, the active auction is closed even if it's not due, to project the
+// clearing price. Infraction statistics of the current epoch are not included,
+// the jail list is taken as it is in best state.
+// It's expensive, use PreviewCache to serve requests.
+func (conR *ConsensusReactor) PreviewKBlock(closeAuction bool) (*KBlockPreview, error) {
+	// snapshot of the reactor state, which is updated by consensus routines
+	conR.epochMtx.RLock()
+	curEpoch := conR.curEpoch
+	var validators []*meter.Address
+	if conR.curCommittee != nil {
+		validators = make([]*meter.Address, 0, len(conR.curCommittee.Validators))
+		for _, v := range conR.curCommittee.Validators {
+			addr := v.Address
+			validators = append(validators, &addr)
+		}
+	}
+	conR.epochMtx.RUnlock()
+
+	if meter.IsMainChainEdison(curEpoch) == true {
+		return nil, errors.New("staking and auction are not supported in edison")
+	}
+	if validators == nil {
+		return nil, errors.New("committee is not initialized...")
+	}
+	se := script.GetScriptGlobInst()
+	if se == nil {
+		return nil, errors.New("script engine is not initialized...")
+	}
+	auc := auction.GetAuctionGlobInst()
+	if auc == nil {
+		return nil, errors.New("auction is not initialized...")
+	}
+
+	best := conR.chain.BestBlock()
+	bestState, err := conR.stateCreator.NewState(best.Header().StateRoot())
+	if err != nil {
+		return nil, err
+	}
+	st, err := conR.stateCreator.NewState(best.Header().StateRoot())
+	if err != nil {
+		return nil, err
+	}
+	p := &KBlockPreview{
+		Epoch:    curEpoch,
+		Number:   best.Header().Number() + 1,
+		ParentID: best.Header().ID(),
+		Best:     bestState,
+		State:    st,
+	}
+
+	// same order as the kblock, auction goes before governing
+	txs := tx.Transactions{}
+	cb := auc.GetAuctionCB(bestState)
+	height, epoch := uint64(best.Header().Number()+1), uint64(best.GetBlockEpoch()+1)
+	if trx := conR.TryBuildAuctionTxs(height, epoch); trx != nil {
+		p.AuctionDue = true
+		p.AuctionEnd = cb.IsActive()
+		txs = append(txs, trx)
+	} else if closeAuction && cb.IsActive() {
+		p.AuctionEnd = true
+		trx := new(tx.Builder).
+			Gas(meter.BaseTxGas * 10).
+			Clause(tx.NewClause(&auction.AuctionAccountAddr).WithValue(big.NewInt(0)).WithToken(tx.TOKEN_METER_GOV).WithData(BuildAuctionStop(cb.StartHeight, cb.StartEpoch, cb.EndHeight, cb.EndEpoch, &cb.AuctionID))).
+			Build()
+		txs = append(txs, trx)
+	}
+	txs = append(txs, conR.buildStakingGoverningTx(conR.buildGoverningData(curEpoch, validators, uint32(conR.config.MaxDelegateSize))))
+	txs = append(txs, conR.buildAccountLockGoverningTx(conR.buildAccountLockGoverningData(curEpoch)))
+
+	// the kblock is the next block of best
+	blockCtx := &xenv.BlockContext{
+		Beneficiary: best.Header().Beneficiary(),
+		Number:      best.Header().Number() + 1,
+		Time:        uint64(time.Now().Unix()),
+		GasLimit:    best.Header().GasLimit(),
+		TotalScore:  best.Header().TotalScore(),
+	}
+	for _, trx := range txs {
+		if err := previewTx(se, trx, blockCtx, st); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// previewTx executes the script clauses of the kblock tx, which has no origin.
+func previewTx(se *script.ScriptEngine, trx *tx.Transaction, blockCtx *xenv.BlockContext, st *state.State) error {
+	txCtx := &xenv.TransactionContext{
+		ID:         trx.ID(),
+		Origin:     meter.Address{},
+		GasPrice:   big.NewInt(0),
+		ProvedWork: big.NewInt(0),
+		BlockRef:   trx.BlockRef(),
+		Expiration: trx.Expiration(),
+	}
+	gas := trx.Gas()
+	for i, c := range trx.Clauses() {
+		_, leftOverGas, err := se.HandleScriptData(c.Data()[4:], c.To(), txCtx, blockCtx, gas, st)
+		if err != nil {
+			return fmt.Errorf("preview clause #%v: %v", i, err)
+		}
+		gas = leftOverGas
+	}
+	return nil
+}
+
+// PreviewCache keeps the converted kblock preview of best block. The preview is
+// computed at most once per best block, and one at a time, however often it's
+// requested.
+type PreviewCache struct {
+	closeAuction bool
+	convert      func(p *KBlockPreview) interface{}
+
+	lock     sync.Mutex
+	parentID meter.Bytes32
+	result   interface{}
+}
+
+// NewPreviewCache creates the cache of the preview converted by convert.
+func NewPreviewCache(closeAuction bool, convert func(p *KBlockPreview) interface{}) *PreviewCache {
+	return &PreviewCache{closeAuction: closeAuction, convert: convert}
+}
+
+// Get returns the converted preview of best block.
+func (c *PreviewCache) Get(conR *ConsensusReactor) (interface{}, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.result != nil && c.parentID == conR.chain.BestBlock().Header().ID() {
+		return c.result, nil
+	}
+	p, err := conR.PreviewKBlock(c.closeAuction)
+	if err != nil {
+		return nil, err
+	}
+	c.parentID, c.result = p.ParentID, c.convert(p)
+	return c.result, nil
+}
@@ -5,35 +5,14 @@
 
 package consensus
 
-import "github.com/prometheus/client_golang/prometheus"
+import "github.com/dfinlab/meter/metric"
 
 var (
-	pmRoundGauge = prometheus.NewGauge(prometheus.GaugeOpts{
-		Name: "pacemaker_round",
-		Help: "Current round of pacemaker",
-	})
-	pmRunningGauge = prometheus.NewGauge(prometheus.GaugeOpts{
-		Name: "pacemaker_running",
-		Help: "status of pacemaker (0-false, 1-true)",
-	})
-	curEpochGauge = prometheus.NewGauge(prometheus.GaugeOpts{
-		Name: "current_epoch",
-		Help: "Current epoch of consensus",
-	})
-	inCommitteeGauge = prometheus.NewGauge(prometheus.GaugeOpts{
-		Name: "in_committee",
-		Help: "is this node in committee",
-	})
-	pmRoleGauge = prometheus.NewGauge(prometheus.GaugeOpts{
-		Name: "pacemaker_role",
-		Help: "Role in pacemaker",
-	})
-	lastKBlockHeightGauge = prometheus.NewGauge(prometheus.GaugeOpts{
-		Name: "last_kblock_height",
-		Help: "Height of last k-block",
-	})
-	blocksCommitedCounter = prometheus.NewCounter(prometheus.CounterOpts{
-		Name: "blocks_commited_total",
-		Help: "Counter of commited blocks locally",
-	})
+	pmRoundGauge          = metric.NewGauge("pacemaker_round", "Current round of pacemaker")
+	pmRunningGauge        = metric.NewGauge("pacemaker_running", "status of pacemaker (0-false, 1-true)")
+	curEpochGauge         = metric.NewGauge("current_epoch", "Current epoch of consensus")
+	inCommitteeGauge      = metric.NewGauge("in_committee", "is this node in committee")
+	pmRoleGauge           = metric.NewGauge("pacemaker_role", "Role in pacemaker")
+	lastKBlockHeightGauge = metric.NewGauge("last_kblock_height", "Height of last k-block")
+	blocksCommitedCounter = metric.NewCounter("blocks_commited_total", "Counter of commited blocks locally")
 )
@@ -29,17 +29,16 @@ import (
 	"sync"
 	"time"
 
-	"github.com/prometheus/client_golang/prometheus"
 	cli "gopkg.in/urfave/cli.v1"
 
 	"github.com/dfinlab/meter/block"
 	"github.com/dfinlab/meter/chain"
-	"github.com/dfinlab/meter/comm"
 	bls "github.com/dfinlab/meter/crypto/multi_sig"
 	"github.com/dfinlab/meter/genesis"
 	"github.com/dfinlab/meter/meter"
 	"github.com/dfinlab/meter/powpool"
 	"github.com/dfinlab/meter/script/staking"
+	"github.com/dfinlab/meter/signer"
 	"github.com/dfinlab/meter/state"
 	"github.com/dfinlab/meter/types"
 	crypto "github.com/ethereum/go-ethereum/crypto"
@@ -109,8 +108,7 @@ type ConsensusReactor struct {
 	SyncDone bool
 
 	// copy of master/node
-	myPubKey      ecdsa.PublicKey  // this is my public identification !!
-	myPrivKey     ecdsa.PrivateKey // copy of private key
+	myPubKey      ecdsa.PublicKey // this is my public identification !!
 	myBeneficiary meter.Address
 
 	// still references above consensuStae, reactor if this node is
@@ -137,6 +135,8 @@ type ConsensusReactor struct {
 	curEpoch         uint64
 	curHeight        uint32 // come from parentBlockID first 4 bytes uint32
 	mtx              sync.RWMutex
+	delegatesMtx     sync.RWMutex // guards config.InitDelegates, reloaded by admin
+	epochMtx         sync.RWMutex // guards writes of curEpoch and curCommittee, read by kblock preview
 
 	// TODO: remove this, not used anymore
 	kBlockData *block.KBlockData
@@ -160,6 +160,10 @@ type ConsensusReactor struct {
 	magic        [4]byte
 	inCommittee  bool
 	allDelegates []*types.Delegate
+
+	deps    Dependencies
+	signer  signer.Signer
+	pmState PMStateStore
 }
 
 // Glob Instance
@@ -174,18 +178,9 @@ func SetConsensusGlobInst(inst *ConsensusReactor) {
 // NewConsensusReactor returns a new ConsensusReactor with the given
 // consensusState.
 func NewConsensusReactor(ctx *cli.Context, chain *chain.Chain, state *state.Creator, privKey *ecdsa.PrivateKey, pubKey *ecdsa.PublicKey, magic [4]byte, blsCommon *BlsCommon, initDelegates []*types.Delegate) *ConsensusReactor {
-	conR := &ConsensusReactor{
-		chain:        chain,
-		stateCreator: state,
-		logger:       log15.New("pkg", "reactor"),
-		SyncDone:     false,
-		magic:        magic,
-		msgCache:     NewMsgCache(1024),
-		inCommittee:  false,
-	}
-
+	var config ConsensusConfig
 	if ctx != nil {
-		conR.config = ConsensusConfig{
+		config = ConsensusConfig{
 			ForceLastKFrame:    ctx.Bool("force-last-kframe"),
 			SkipSignatureCheck: ctx.Bool("skip-signature-check"),
 			InitCfgdDelegates:  ctx.Bool("init-configured-delegates"),
@@ -196,6 +191,26 @@ func NewConsensusReactor(ctx *cli.Context, chain *chain.Chain, state *state.Crea
 			InitDelegates:      initDelegates,
 		}
 	}
+	conR := newConsensusReactor(config, chain, state, privKey, pubKey, magic, blsCommon)
+	SetConsensusGlobInst(conR)
+	return conR
+}
+
+// newConsensusReactor creates the reactor without touching the global instance,
+// so several reactors are able to live in the same process.
+func newConsensusReactor(config ConsensusConfig, chain *chain.Chain, state *state.Creator, privKey *ecdsa.PrivateKey, pubKey *ecdsa.PublicKey, magic [4]byte, blsCommon *BlsCommon) *ConsensusReactor {
+	conR := &ConsensusReactor{
+		chain:        chain,
+		stateCreator: state,
+		config:       config,
+		logger:       log15.New("pkg", "reactor"),
+		SyncDone:     false,
+		magic:        magic,
+		msgCache:     NewMsgCache(1024),
+		inCommittee:  false,
+		deps:         Dependencies{Transport: &httpTransport{}},
+		pmState:      NewMemPMStateStore(),
+	}
 
 	//initialize message channel
 	conR.peerMsgQueue = make(chan consensusMsgInfo, CHAN_DEFAULT_BUF_SIZE)
@@ -224,13 +239,6 @@ func NewConsensusReactor(ctx *cli.Context, chain *chain.Chain, state *state.Crea
 		curEpochGauge.Set(float64(0))
 	}
 
-	prometheus.MustRegister(pmRoundGauge)
-	prometheus.MustRegister(curEpochGauge)
-	prometheus.MustRegister(lastKBlockHeightGauge)
-	prometheus.MustRegister(blocksCommitedCounter)
-	prometheus.MustRegister(inCommitteeGauge)
-	prometheus.MustRegister(pmRoleGauge)
-
 	lastKBlockHeightGauge.Set(float64(conR.lastKBlockHeight))
 
 	//initialize Delegates
@@ -239,13 +247,28 @@ func NewConsensusReactor(ctx *cli.Context, chain *chain.Chain, state *state.Crea
 
 	conR.rcvdNewCommittee = make(map[NewCommitteeKey]*NewCommittee, 10)
 
-	conR.myPrivKey = *privKey
 	conR.myPubKey = *pubKey
+	// keys are held by the signer, which is set later without private key
+	if privKey != nil {
+		conR.signer = signer.NewLocal(privKey, blsCommon.system, blsCommon.PrivKey, blsCommon.PubKey, nil)
+	}
 
-	SetConsensusGlobInst(conR)
 	return conR
 }
 
+// SetSigner sets the signer of blocks and consensus messages, which must sign
+// with the keys of this node. It must be called before OnStart, and is required
+// if the reactor is created without private key.
+func (conR *ConsensusReactor) SetSigner(s signer.Signer) {
+	conR.signer = s
+}
+
+// SetPMStateStore replaces the store of pacemaker safety state, which is kept
+// in memory by default. It must be called before OnStart.
+func (conR *ConsensusReactor) SetPMStateStore(store PMStateStore) {
+	conR.pmState = store
+}
+
 // OnStart implements BaseService by subscribing to events, which later will be
 // broadcasted to other peers and starting state if we're not in fast sync.
 func (conR *ConsensusReactor) OnStart() error {
@@ -471,7 +494,9 @@ func (conR *ConsensusReactor) NewValidatorSetByNonce(nonce uint64) (uint, bool)
 	committee, role, index, inCommittee := conR.CalcCommitteeByNonce(nonce)
 	// fmt.Println("CALCULATED COMMITEE", "role=", role, "index=", index)
 	// fmt.Println(committee)
+	conR.epochMtx.Lock()
 	conR.curCommittee = committee
+	conR.epochMtx.Unlock()
 	if inCommittee == true {
 		conR.csMode = CONSENSUS_MODE_COMMITTEE
 		conR.curCommitteeIndex = uint32(index)
@@ -672,7 +697,7 @@ func (conR *ConsensusReactor) GetRelayPeers(round int) ([]*ConsensusPeer, error)
 		}
 		member := conR.curCommittee.Validators[index]
 		name := conR.GetDelegateNameByIP(member.NetAddr.IP)
-		peers = append(peers, newConsensusPeer(name, member.NetAddr.IP, member.NetAddr.Port, conR.magic))
+		peers = append(peers, newConsensusPeer(name, member.NetAddr.IP, member.NetAddr.Port, conR.magic, conR.transport()))
 	}
 	return peers, nil
 }
@@ -698,7 +723,7 @@ func (conR *ConsensusReactor) receiveRoutine() {
 	}
 	*******/
 	//wait for synchronization is done
-	communicator := comm.GetGlobCommInst()
+	communicator := conR.syncer()
 	if communicator == nil {
 		conR.logger.Error("get communicator instance failed ...")
 		return
@@ -777,7 +802,7 @@ func (conR *ConsensusReactor) UnmarshalMsg(data []byte) (*consensusMsgInfo, erro
 		return nil, ErrUnrecognizedPayload
 	}
 	peerName := conR.GetDelegateNameByIP(peerIP)
-	peer := newConsensusPeer(peerName, peerIP, uint16(peerPort), conR.magic)
+	peer := newConsensusPeer(peerName, peerIP, uint16(peerPort), conR.magic, conR.transport())
 	rawMsg, err := hex.DecodeString(params["message"])
 	if err != nil {
 		fmt.Println("could not decode string: ", params["message"])
@@ -794,8 +819,19 @@ func (conR *ConsensusReactor) UnmarshalMsg(data []byte) (*consensusMsgInfo, erro
 }
 
 func (conR *ConsensusReactor) ReceivePacemakerMsg(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		conR.logger.Error("Unrecognized payload", "err", err)
+		return
+	}
+	conR.DeliverPacemakerMsg(data)
+}
+
+// DeliverPacemakerMsg handles the raw pacemaker message received by transport.
+func (conR *ConsensusReactor) DeliverPacemakerMsg(data []byte) {
 	if conR.csPacemaker != nil {
-		conR.csPacemaker.receivePacemakerMsg(w, r)
+		conR.csPacemaker.receivePacemakerMsg(data)
 	} else {
 		conR.logger.Warn("pacemaker is not initialized, dropped message")
 	}
@@ -808,6 +844,11 @@ func (conR *ConsensusReactor) ReceiveCommitteeMsg(w http.ResponseWriter, r *http
 		fmt.Println(err)
 		return
 	}
+	conR.DeliverCommitteeMsg(data)
+}
+
+// DeliverCommitteeMsg handles the raw committee message received by transport.
+func (conR *ConsensusReactor) DeliverCommitteeMsg(data []byte) {
 	mi, err := conR.UnmarshalMsg(data)
 	if err != nil {
 		fmt.Println(err)
@@ -979,7 +1020,7 @@ func (conR *ConsensusReactor) GetMyPeers() ([]*ConsensusPeer, error) {
 	myNetAddr := conR.GetMyNetAddr()
 	for _, member := range conR.curActualCommittee {
 		if member.NetAddr.IP.String() != myNetAddr.IP.String() {
-			peers = append(peers, newConsensusPeer(member.Name, member.NetAddr.IP, member.NetAddr.Port, conR.magic))
+			peers = append(peers, newConsensusPeer(member.Name, member.NetAddr.IP, member.NetAddr.Port, conR.magic, conR.transport()))
 		}
 	}
 	return peers, nil
@@ -1046,8 +1087,9 @@ func (conR *ConsensusReactor) GetLatestCommitteeList() ([]*ApiCommitteeMember, e
 }
 
 //============================================
-func (conR *ConsensusReactor) SignConsensusMsg(msgHash []byte) (sig []byte, err error) {
-	sig, err = crypto.Sign(msgHash, &conR.myPrivKey)
+// SignConsensusMsg signs the consensus message other than proposal.
+func (conR *ConsensusReactor) SignConsensusMsg(msg ConsensusMessage) (sig []byte, err error) {
+	sig, err = conR.signer.SignMessage(msg.SigningData())
 	if err != nil {
 		return []byte{}, err
 	}
@@ -1055,6 +1097,17 @@ func (conR *ConsensusReactor) SignConsensusMsg(msgHash []byte) (sig []byte, err
 	return sig, nil
 }
 
+// SignBlsMsg signs the sha256 hash of msg with the bls key
+func (conR *ConsensusReactor) SignBlsMsg(msg []byte) (bls.Signature, [32]byte, error) {
+	msgHash := sha256.Sum256(msg)
+	sigBytes, err := conR.signer.SignBlsMessage(msg)
+	if err != nil {
+		return bls.Signature{}, msgHash, err
+	}
+	sig, err := conR.csCommon.GetSystem().SigFromBytes(sigBytes)
+	return sig, msgHash, err
+}
+
 //----------------------------------------------------------------------------
 // Sign New Committee
 // "New Committee Message: Leader <pubkey 64(hexdump 32x2) bytes> EpochID <16 (8x2)bytes> Height <16 (8x2) bytes>
@@ -1175,7 +1228,7 @@ func HandleScheduleLeader(conR *ConsensusReactor, epochID uint64, height uint32,
 			// mine is ahead of kblock, stop
 			return false
 		}
-		com := comm.GetGlobCommInst()
+		com := conR.syncer()
 		if com == nil {
 			conR.logger.Error("get global comm inst failed")
 			return false
@@ -1259,7 +1312,7 @@ func (conR *ConsensusReactor) JoinEstablishedCommittee(kBlock *block.Block, repl
 
 	if inCommittee {
 		conR.logger.Info("I am in committee!!!")
-		pool := powpool.GetGlobPowPoolInst()
+		pool := conR.powPool()
 		pool.Wash()
 		pool.InitialAddKframe(info)
 		conR.logger.Info("PowPool initial added kblock", "kblock height", kBlock.Header().Number(), "powHeight", info.PowHeight)
@@ -1345,7 +1398,7 @@ func (conR *ConsensusReactor) ConsensusHandleReceivedNonce(kBlockHeight uint32,
 			kblock, _ := conR.chain.GetTrunkBlock(uint32(kBlockHeight))
 			info = powpool.NewPowBlockInfoFromPosKBlock(kblock)
 		}
-		pool := powpool.GetGlobPowPoolInst()
+		pool := conR.powPool()
 		pool.Wash()
 		pool.InitialAddKframe(info)
 		conR.logger.Info("PowPool initial added kblock", "kblock height", kBlockHeight, "powHeight", info.PowHeight)
@@ -1394,7 +1447,7 @@ func (conR *ConsensusReactor) ConsensusHandleReceivedNonce(kBlockHeight uint32,
 		conR.NewCommitteeInit(kBlockHeight, nonce, replay)
 		newCommittee := conR.newCommittee
 		nl := newCommittee.Committee.Validators[int(newCommittee.Round)%len(newCommittee.Committee.Validators)]
-		leader := newConsensusPeer(nl.Name, nl.NetAddr.IP, nl.NetAddr.Port, conR.magic)
+		leader := newConsensusPeer(nl.Name, nl.NetAddr.IP, nl.NetAddr.Port, conR.magic, conR.transport())
 		leaderPubKey := nl.PubKey
 		conR.sendNewCommitteeMessage(leader, leaderPubKey, newCommittee.KblockHeight,
 			newCommittee.Nonce, newCommittee.Round)
@@ -1419,7 +1472,7 @@ func (conR *ConsensusReactor) startPacemaker(newCommittee bool, mode PMMode) err
 		bestBlock := conR.chain.BestBlock()
 		conR.logger.Info("Checking the QCHeight and Block height...", "QCHeight", bestQC.QCHeight, "bestHeight", bestBlock.Header().Number())
 		if bestQC.QCHeight != bestBlock.Header().Number() {
-			com := comm.GetGlobCommInst()
+			com := conR.syncer()
 			if com == nil {
 				conR.logger.Error("get global comm inst failed")
 				return errors.New("pacemaker does not started")
@@ -1605,14 +1658,14 @@ func (conR *ConsensusReactor) GetConsensusDelegates() ([]*types.Delegate, int, i
 	// special handle for flag --init-configured-delegates
 	var delegates []*types.Delegate
 	if forceDelegates == true {
-		delegates = conR.config.InitDelegates
+		delegates = conR.getInitDelegates()
 		fmt.Println("Load delegates from delegates.json")
 	} else {
 		delegatesIntern, err := staking.GetInternalDelegateList()
 		delegates = conR.convertFromIntern(delegatesIntern)
 		fmt.Println("Load delegates from staking candidates")
 		if err != nil || len(delegates) < conR.config.MinCommitteeSize {
-			delegates = conR.config.InitDelegates
+			delegates = conR.getInitDelegates()
 			fmt.Println("Load delegates from delegates.json as fallback, error loading staking candiates")
 		}
 	}
@@ -1624,6 +1677,20 @@ func (conR *ConsensusReactor) GetConsensusDelegates() ([]*types.Delegate, int, i
 	return delegates, delegateSize, committeeSize
 }
 
+// SetInitDelegates replaces delegates loaded from delegates.json, which takes effect
+// from the next committee.
+func (conR *ConsensusReactor) SetInitDelegates(delegates []*types.Delegate) {
+	conR.delegatesMtx.Lock()
+	defer conR.delegatesMtx.Unlock()
+	conR.config.InitDelegates = delegates
+}
+
+func (conR *ConsensusReactor) getInitDelegates() []*types.Delegate {
+	conR.delegatesMtx.RLock()
+	defer conR.delegatesMtx.RUnlock()
+	return conR.config.InitDelegates
+}
+
 func (conR *ConsensusReactor) GetDelegateNameByIP(ip net.IP) string {
 	for _, d := range conR.allDelegates {
 		if d.NetAddr.IP.String() == ip.String() {
@@ -209,7 +209,7 @@ func (conR *ConsensusReactor) TryBuildAuctionTxs(height, epoch uint64) *tx.Trans
 		lastEndHeight = cb.EndHeight
 		lastEndEpoch = cb.EndEpoch
 	} else {
-		summaryList, err := auction.GetAuctionSummaryList()
+		summaryList, err := auction.GetLastAuctionSummaries(1)
 		if err != nil {
 			conR.logger.Error("get summary list failed", "error", err)
 			return nil //TBD: still create Tx?
@@ -259,7 +259,7 @@ func (conR *ConsensusReactor) GetKBlockValidatorRewards() (*big.Int, error) {
 	}
 	ValidatorBenefitRatio := builtin.Params.Native(state).Get(meter.KeyValidatorBenefitRatio)
 
-	summaryList, err := auction.GetAuctionSummaryList()
+	summaryList, err := auction.GetLastAuctionSummaries(N)
 	if err != nil {
 		conR.logger.Error("get summary list failed", "error", err)
 		return big.NewInt(0), err
@@ -293,17 +293,21 @@ func (conR *ConsensusReactor) GetKBlockValidatorRewards() (*big.Int, error) {
 }
 
 func (conR *ConsensusReactor) BuildGoverningData(delegateSize uint32) (ret []byte) {
+	validators := []*meter.Address{}
+	for _, c := range conR.curCommittee.Validators {
+		addr := &c.Address
+		validators = append(validators, addr)
+	}
+	return conR.buildGoverningData(conR.curEpoch, validators, delegateSize)
+}
+
+func (conR *ConsensusReactor) buildGoverningData(epoch uint64, validators []*meter.Address, delegateSize uint32) (ret []byte) {
 	ret = []byte{}
 
 	validatorRewards, err := conR.GetKBlockValidatorRewards()
 	if err != nil {
 		conR.logger.Error("get validator rewards failed", err.Error())
 	}
-	validators := []*meter.Address{}
-	for _, c := range conR.curCommittee.Validators {
-		addr := &c.Address
-		validators = append(validators, addr)
-	}
 
 	extraBytes, err := rlp.EncodeToBytes(validators)
 	if err != nil {
@@ -313,7 +317,7 @@ func (conR *ConsensusReactor) BuildGoverningData(delegateSize uint32) (ret []byt
 
 	body := &staking.StakingBody{
 		Opcode:    staking.OP_GOVERNING,
-		Version:   uint32(conR.curEpoch),
+		Version:   uint32(epoch),
 		Option:    delegateSize,
 		Amount:    validatorRewards,
 		Timestamp: uint64(time.Now().Unix()),
@@ -347,6 +351,10 @@ func (conR *ConsensusReactor) BuildGoverningData(delegateSize uint32) (ret []byt
 
 // for distribute validator rewards, recalc the delegates list ...
 func (conR *ConsensusReactor) TryBuildStakingGoverningTx() *tx.Transaction {
+	return conR.buildStakingGoverningTx(conR.BuildGoverningData(uint32(conR.config.MaxDelegateSize)))
+}
+
+func (conR *ConsensusReactor) buildStakingGoverningTx(data []byte) *tx.Transaction {
 	// 1. signer is nil
 	// 1. located first transaction in kblock.
 	builder := new(tx.Builder)
@@ -358,7 +366,7 @@ func (conR *ConsensusReactor) TryBuildStakingGoverningTx() *tx.Transaction {
 		DependsOn(nil).
 		Nonce(12345678)
 
-	builder.Clause(tx.NewClause(&staking.StakingModuleAddr).WithValue(big.NewInt(0)).WithToken(tx.TOKEN_METER_GOV).WithData(conR.BuildGoverningData(uint32(conR.config.MaxDelegateSize))))
+	builder.Clause(tx.NewClause(&staking.StakingModuleAddr).WithValue(big.NewInt(0)).WithToken(tx.TOKEN_METER_GOV).WithData(data))
 
 	builder.Build().IntrinsicGas()
 	return builder.Build()
@@ -366,11 +374,15 @@ func (conR *ConsensusReactor) TryBuildStakingGoverningTx() *tx.Transaction {
 
 /////// account lock governing
 func (conR *ConsensusReactor) BuildAccoutLockGovningData() (ret []byte) {
+	return conR.buildAccountLockGoverningData(conR.curEpoch)
+}
+
+func (conR *ConsensusReactor) buildAccountLockGoverningData(epoch uint64) (ret []byte) {
 	ret = []byte{}
 
 	body := &accountlock.AccountLockBody{
 		Opcode:  accountlock.OP_GOVERNING,
-		Version: uint32(conR.curEpoch),
+		Version: uint32(epoch),
 		Option:  uint32(0),
 	}
 	payload, err := rlp.EncodeToBytes(body)
@@ -399,6 +411,10 @@ func (conR *ConsensusReactor) BuildAccoutLockGovningData() (ret []byte) {
 }
 
 func (conR *ConsensusReactor) TryBuildAccountLockGoverningTx() *tx.Transaction {
+	return conR.buildAccountLockGoverningTx(conR.BuildAccoutLockGovningData())
+}
+
+func (conR *ConsensusReactor) buildAccountLockGoverningTx(data []byte) *tx.Transaction {
 	// 1. signer is nil
 	// 1. transaction in kblock.
 	builder := new(tx.Builder)
@@ -409,7 +425,7 @@ func (conR *ConsensusReactor) TryBuildAccountLockGoverningTx() *tx.Transaction {
 		Gas(meter.BaseTxGas * 10). //buffer for builder.Build().IntrinsicGas()
 		DependsOn(nil).
 		Nonce(12345678)
-	builder.Clause(tx.NewClause(&accountlock.AccountLockAddr).WithValue(big.NewInt(0)).WithToken(tx.TOKEN_METER_GOV).WithData(conR.BuildAccoutLockGovningData()))
+	builder.Clause(tx.NewClause(&accountlock.AccountLockAddr).WithValue(big.NewInt(0)).WithToken(tx.TOKEN_METER_GOV).WithData(data))
 
 	builder.Build().IntrinsicGas()
 	return builder.Build()
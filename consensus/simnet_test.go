@@ -0,0 +1,265 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package consensus
+
+import (
+	crand "crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/dfinlab/meter/block"
+	"github.com/dfinlab/meter/meter"
+	"github.com/dfinlab/meter/types"
+)
+
+var errSimUnreachable = errors.New("sim: peer unreachable")
+
+type simMsgKind int
+
+const (
+	simPacemakerMsg simMsgKind = iota
+	simCommitteeMsg
+)
+
+// simEnvelope is a single message on the simulated wire, peers are keyed by ip.
+type simEnvelope struct {
+	from string
+	to   string
+	kind simMsgKind
+	data []byte
+}
+
+// simInterceptor rewrites the outgoing messages of a node. Returning an empty
+// slice drops the message, returning more than one duplicates it.
+type simInterceptor func(env simEnvelope) []simEnvelope
+
+// simNetwork is the in-memory replacement of the http transport between
+// consensus peers, with injectable latency, drops and partitions.
+type simNetwork struct {
+	mtx sync.Mutex
+	rnd *rand.Rand
+
+	nodes        map[string]*ConsensusReactor
+	groups       map[string]int
+	interceptors map[string]simInterceptor
+
+	latency  time.Duration
+	jitter   time.Duration
+	dropRate float64
+
+	delivered int
+	dropped   int
+}
+
+func newSimNetwork(seed int64) *simNetwork {
+	return &simNetwork{
+		rnd:          rand.New(rand.NewSource(seed)),
+		nodes:        make(map[string]*ConsensusReactor),
+		groups:       make(map[string]int),
+		interceptors: make(map[string]simInterceptor),
+	}
+}
+
+func (n *simNetwork) register(ip string, conR *ConsensusReactor) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	n.nodes[ip] = conR
+}
+
+func (n *simNetwork) transport(ip string) Transport {
+	return &simTransport{net: n, from: ip}
+}
+
+// SetLatency delays every message by d plus a random jitter up to j.
+func (n *simNetwork) SetLatency(d, j time.Duration) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	n.latency, n.jitter = d, j
+}
+
+// SetDropRate silently drops the given fraction of messages.
+func (n *simNetwork) SetDropRate(rate float64) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	n.dropRate = rate
+}
+
+// Partition splits the network, nodes could only reach the nodes in the same
+// group. Nodes not listed stay in group 0.
+func (n *simNetwork) Partition(groups ...[]string) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	n.groups = make(map[string]int)
+	for i, g := range groups {
+		for _, ip := range g {
+			n.groups[ip] = i + 1
+		}
+	}
+}
+
+// Heal removes all partitions.
+func (n *simNetwork) Heal() {
+	n.Partition()
+}
+
+// Intercept installs the interceptor on the outgoing messages of ip.
+func (n *simNetwork) Intercept(ip string, fn simInterceptor) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	if fn == nil {
+		delete(n.interceptors, ip)
+		return
+	}
+	n.interceptors[ip] = fn
+}
+
+func (n *simNetwork) Stats() (delivered, dropped int) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	return n.delivered, n.dropped
+}
+
+func (n *simNetwork) send(env simEnvelope) error {
+	n.mtx.Lock()
+	intercept := n.interceptors[env.from]
+	n.mtx.Unlock()
+
+	envs := []simEnvelope{env}
+	if intercept != nil {
+		envs = intercept(env)
+	}
+	for _, e := range envs {
+		if err := n.route(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *simNetwork) route(env simEnvelope) error {
+	n.mtx.Lock()
+	dest, ok := n.nodes[env.to]
+	if !ok || n.groups[env.from] != n.groups[env.to] {
+		n.dropped++
+		n.mtx.Unlock()
+		return errSimUnreachable
+	}
+	if n.dropRate > 0 && n.rnd.Float64() < n.dropRate {
+		n.dropped++
+		n.mtx.Unlock()
+		return nil
+	}
+	delay := n.latency
+	if n.jitter > 0 {
+		delay += time.Duration(n.rnd.Int63n(int64(n.jitter)))
+	}
+	n.delivered++
+	n.mtx.Unlock()
+
+	deliver := func() {
+		switch env.kind {
+		case simPacemakerMsg:
+			dest.DeliverPacemakerMsg(env.data)
+		case simCommitteeMsg:
+			dest.DeliverCommitteeMsg(env.data)
+		}
+	}
+	// message handling may block on the receiving queues, never block the sender
+	if delay > 0 {
+		time.AfterFunc(delay, deliver)
+	} else {
+		go deliver()
+	}
+	return nil
+}
+
+type simTransport struct {
+	net  *simNetwork
+	from string
+}
+
+func (t *simTransport) SendPacemakerMsg(to types.NetAddress, rawData []byte) error {
+	return t.net.send(simEnvelope{from: t.from, to: to.IP.String(), kind: simPacemakerMsg, data: rawData})
+}
+
+func (t *simTransport) SendCommitteeMsg(to types.NetAddress, rawData []byte) error {
+	return t.net.send(simEnvelope{from: t.from, to: to.IP.String(), kind: simCommitteeMsg, data: rawData})
+}
+
+// decodeSimMsg decodes the consensus message carried by the marshaled payload.
+func decodeSimMsg(data []byte) (ConsensusMessage, error) {
+	var params map[string]string
+	if err := json.Unmarshal(data, &params); err != nil {
+		return nil, err
+	}
+	rawMsg, err := hex.DecodeString(params["message"])
+	if err != nil {
+		return nil, err
+	}
+	return decodeMsg(rawMsg)
+}
+
+// withholdVotes makes the node a silent voter, it still proposes and relays.
+func withholdVotes() simInterceptor {
+	return func(env simEnvelope) []simEnvelope {
+		if msg, err := decodeSimMsg(env.data); err == nil {
+			if _, ok := msg.(*PMVoteMessage); ok {
+				return nil
+			}
+		}
+		return []simEnvelope{env}
+	}
+}
+
+// equivocateVotes makes the node send a second, properly signed vote for a
+// conflicting block along with each of its votes.
+func equivocateVotes(conR *ConsensusReactor) simInterceptor {
+	return func(env simEnvelope) []simEnvelope {
+		envs := []simEnvelope{env}
+		msg, err := decodeSimMsg(env.data)
+		if err != nil {
+			return envs
+		}
+		vote, ok := msg.(*PMVoteMessage)
+		if !ok {
+			return envs
+		}
+		forged, err := forgeConflictingVote(conR, vote)
+		if err != nil {
+			return envs
+		}
+		return append(envs, simEnvelope{from: env.from, to: env.to, kind: env.kind, data: forged})
+	}
+}
+
+func forgeConflictingVote(conR *ConsensusReactor, vote *PMVoteMessage) ([]byte, error) {
+	var blockID, txsRoot, stateRoot meter.Bytes32
+	if _, err := crand.Read(blockID[:]); err != nil {
+		return nil, err
+	}
+
+	height := vote.CSMsgCommonHeader.Height
+	signMsg := conR.BuildProposalBlockSignMsg(block.BLOCK_TYPE_M_BLOCK, uint64(height), &blockID, &txsRoot, &stateRoot)
+	sign, msgHash := conR.csCommon.SignMessage([]byte(signMsg))
+
+	forged := *vote
+	forged.CSMsgCommonHeader.Timestamp = time.Now()
+	forged.BlsSignature = conR.csCommon.GetSystem().SigToBytes(sign)
+	forged.SignedMessageHash = msgHash
+
+	msgSig, err := conR.SignConsensusMsg(&forged)
+	if err != nil {
+		return nil, err
+	}
+	forged.CSMsgCommonHeader.SetMsgSignature(msgSig)
+
+	var msg ConsensusMessage = &forged
+	return conR.MarshalMsg(&msg)
+}
@@ -0,0 +1,570 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package consensus
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dfinlab/meter/block"
+	"github.com/dfinlab/meter/chain"
+	bls "github.com/dfinlab/meter/crypto/multi_sig"
+	"github.com/dfinlab/meter/genesis"
+	"github.com/dfinlab/meter/logdb"
+	"github.com/dfinlab/meter/lvldb"
+	"github.com/dfinlab/meter/meter"
+	"github.com/dfinlab/meter/packer"
+	"github.com/dfinlab/meter/powpool"
+	"github.com/dfinlab/meter/state"
+	"github.com/dfinlab/meter/txpool"
+	"github.com/dfinlab/meter/types"
+)
+
+// simSyncer reports the node as synced from the beginning. Blocks are only
+// exchanged through consensus in the simulation.
+type simSyncer struct {
+	synced chan struct{}
+}
+
+func newSimSyncer() *simSyncer {
+	s := &simSyncer{synced: make(chan struct{})}
+	close(s.synced)
+	return s
+}
+
+func (s *simSyncer) Synced() <-chan struct{}         { return s.synced }
+func (s *simSyncer) TriggerSync()                    {}
+func (s *simSyncer) BroadcastBlock(blk *block.Block) {}
+
+// simPowPool is shared by all nodes. Once triggered, the proposers are told to
+// propose a K-block until the new committee washes the pool.
+type simPowPool struct {
+	mtx     sync.Mutex
+	pending bool
+	nonce   uint32
+}
+
+func (p *simPowPool) Trigger() {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.pending = true
+	p.nonce++
+}
+
+func (p *simPowPool) GetPowDecision() (bool, *powpool.PowResult) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if !p.pending {
+		return false, nil
+	}
+	return true, &powpool.PowResult{Nonce: p.nonce, Difficaulties: big.NewInt(1)}
+}
+
+func (p *simPowPool) Wash() error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.pending = false
+	return nil
+}
+
+func (p *simPowPool) InitialAddKframe(info *powpool.PowBlockInfo) error { return nil }
+func (p *simPowPool) ReplayFrom(startHeight int32) error                { return nil }
+
+type simNode struct {
+	name    string
+	ip      string
+	chain   *chain.Chain
+	reactor *ConsensusReactor
+}
+
+func (n *simNode) bestHeight() uint32 {
+	return n.chain.BestBlock().Header().Number()
+}
+
+type simCluster struct {
+	t     *testing.T
+	net   *simNetwork
+	pow   *simPowPool
+	nodes []*simNode
+}
+
+// newSimCluster builds size nodes on devnet genesis, each with its own chain,
+// tx pool and packer, all of them as configured delegates.
+func newSimCluster(t *testing.T, size int) *simCluster {
+	accounts := genesis.DevAccounts()
+	if size > len(accounts) {
+		t.Fatalf("cluster size %v exceeds dev accounts", size)
+	}
+
+	gene := genesis.NewDevnet()
+	meter.InitBlockChainConfig(gene.ID(), "dev")
+
+	// all nodes share the BLS system, keys are generated per node
+	blsCommon := NewBlsCommon()
+	if blsCommon == nil {
+		t.Fatal("init BLS failed")
+	}
+
+	blsCommons := make([]*BlsCommon, 0, size)
+	delegates := make([]*types.Delegate, 0, size)
+	for i := 0; i < size; i++ {
+		pubKey, privKey, err := bls.GenKeys(blsCommon.system)
+		if err != nil {
+			t.Fatal(err)
+		}
+		blsCommons = append(blsCommons, NewBlsCommonFromParams(pubKey, privKey, blsCommon.system, blsCommon.params, blsCommon.pairing))
+
+		acc := accounts[i]
+		d := types.NewDelegate([]byte(fmt.Sprintf("sim%d", i)), acc.Address, acc.PrivateKey.PublicKey, pubKey, 1e18, 0)
+		d.NetAddr = types.NetAddress{IP: net.IPv4(10, 0, 0, byte(i+1)), Port: 8670}
+		delegates = append(delegates, d)
+	}
+
+	config := ConsensusConfig{
+		InitCfgdDelegates: true,
+		EpochMBlockCount:  MIN_MBLOCKS_AN_EPOCH,
+		MinCommitteeSize:  size,
+		MaxCommitteeSize:  size,
+		MaxDelegateSize:   size,
+		InitDelegates:     delegates,
+	}
+
+	c := &simCluster{
+		t:   t,
+		net: newSimNetwork(1),
+		pow: &simPowPool{},
+	}
+	syncer := newSimSyncer()
+	magic := [4]byte{0x73, 0x69, 0x6d, 0x00}
+	for i := 0; i < size; i++ {
+		db, err := lvldb.NewMem()
+		if err != nil {
+			t.Fatal(err)
+		}
+		logDB, err := logdb.NewMem()
+		if err != nil {
+			t.Fatal(err)
+		}
+		stateCreator := state.NewCreator(db)
+		genesisBlock, _, err := gene.Build(stateCreator)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ch, err := chain.New(db, genesisBlock, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		acc := accounts[i]
+		ip := delegates[i].NetAddr.IP.String()
+		conR := newConsensusReactor(config, ch, stateCreator, acc.PrivateKey, &acc.PrivateKey.PublicKey, magic, blsCommons[i])
+		conR.SetDependencies(Dependencies{
+			Transport: c.net.transport(ip),
+			Syncer:    syncer,
+			PowPool:   c.pow,
+			TxPool:    txpool.New(ch, stateCreator, txpool.Options{Limit: 10000, LimitPerAccount: 16, MaxLifetime: 20 * time.Minute}),
+			Packer:    packer.New(ch, stateCreator, acc.Address, &acc.Address),
+			LogDB:     logDB,
+		})
+		c.net.register(ip, conR)
+		c.nodes = append(c.nodes, &simNode{name: string(delegates[i].Name), ip: ip, chain: ch, reactor: conR})
+	}
+	return c
+}
+
+func (c *simCluster) Start() {
+	for _, n := range c.nodes {
+		n.reactor.OnStart()
+	}
+}
+
+// Stop halts the pacemakers. Reactors are not stopped since that frees the
+// shared BLS system.
+func (c *simCluster) Stop() {
+	for _, n := range c.nodes {
+		if n.reactor.csPacemaker != nil {
+			n.reactor.csPacemaker.Stop()
+		}
+	}
+}
+
+func (c *simCluster) node(i int) *simNode {
+	return c.nodes[i]
+}
+
+// minHeight is the best height of the slowest node among the given ones,
+// or among all nodes if none is given.
+func (c *simCluster) minHeight(nodes ...*simNode) uint32 {
+	if len(nodes) == 0 {
+		nodes = c.nodes
+	}
+	min := nodes[0].bestHeight()
+	for _, n := range nodes[1:] {
+		if h := n.bestHeight(); h < min {
+			min = h
+		}
+	}
+	return min
+}
+
+func (c *simCluster) waitFor(timeout time.Duration, desc string, cond func() bool) {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+	c.t.Fatalf("timeout waiting for %v", desc)
+}
+
+// waitHeight asserts liveness, the given nodes must all reach the height.
+func (c *simCluster) waitHeight(height uint32, timeout time.Duration, nodes ...*simNode) {
+	c.waitFor(timeout, fmt.Sprintf("height %v", height), func() bool {
+		return c.minHeight(nodes...) >= height
+	})
+}
+
+// checkSafety asserts no two nodes finalized different blocks at the same height.
+func (c *simCluster) checkSafety() {
+	max := uint32(0)
+	for _, n := range c.nodes {
+		if h := n.bestHeight(); h > max {
+			max = h
+		}
+	}
+	for h := uint32(1); h <= max; h++ {
+		var (
+			ref   meter.Bytes32
+			owner string
+		)
+		for _, n := range c.nodes {
+			if n.bestHeight() < h {
+				continue
+			}
+			id, err := n.chain.GetTrunkBlockID(h)
+			if err != nil {
+				c.t.Fatal(err)
+			}
+			if owner == "" {
+				ref, owner = id, n.name
+				continue
+			}
+			if id != ref {
+				c.t.Fatalf("safety violated at height %v: %v has %v, %v has %v", h, owner, ref, n.name, id)
+			}
+		}
+	}
+}
+
+func newStartedSimCluster(t *testing.T, size int) *simCluster {
+	if testing.Short() {
+		t.Skip("skip consensus simulation in short mode")
+	}
+	c := newSimCluster(t, size)
+	c.Start()
+	return c
+}
+
+func TestSimCommit(t *testing.T) {
+	c := newStartedSimCluster(t, 4)
+	defer c.Stop()
+
+	c.waitHeight(8, time.Minute)
+	c.checkSafety()
+}
+
+func TestSimLatencyAndDrops(t *testing.T) {
+	c := newStartedSimCluster(t, 4)
+	defer c.Stop()
+
+	c.net.SetLatency(50*time.Millisecond, 150*time.Millisecond)
+	c.net.SetDropRate(0.05)
+
+	c.waitHeight(6, 3*time.Minute)
+	c.checkSafety()
+}
+
+func TestSimRoundTimeout(t *testing.T) {
+	c := newStartedSimCluster(t, 4)
+	defer c.Stop()
+
+	c.waitHeight(3, time.Minute)
+
+	// isolate one node, the rounds it proposes have to time out
+	isolated := c.node(3)
+	c.net.Partition([]string{isolated.ip})
+	height := c.minHeight(c.nodes[:3]...)
+	c.waitHeight(height+2*uint32(len(c.nodes)), 5*time.Minute, c.nodes[:3]...)
+	c.checkSafety()
+
+	c.net.Heal()
+	c.checkSafety()
+}
+
+func TestSimPartitionWithoutQuorum(t *testing.T) {
+	c := newStartedSimCluster(t, 4)
+	defer c.Stop()
+
+	c.waitHeight(3, time.Minute)
+
+	// no side holds 2/3 of the committee
+	c.net.Partition([]string{c.node(0).ip, c.node(1).ip}, []string{c.node(2).ip, c.node(3).ip})
+	time.Sleep(2 * RoundInterval)
+	height := c.minHeight()
+	time.Sleep(2 * RoundTimeoutInterval)
+	c.checkSafety()
+	for _, n := range c.nodes {
+		// a block committed before the partition may still land
+		if n.bestHeight() > height+1 {
+			t.Fatalf("%v committed blocks without quorum", n.name)
+		}
+	}
+
+	c.net.Heal()
+	c.waitHeight(height+3, 5*time.Minute)
+	c.checkSafety()
+}
+
+func TestSimWithheldVotes(t *testing.T) {
+	c := newStartedSimCluster(t, 4)
+	defer c.Stop()
+
+	c.net.Intercept(c.node(0).ip, withholdVotes())
+
+	c.waitHeight(8, 3*time.Minute)
+	c.checkSafety()
+}
+
+func TestSimEquivocation(t *testing.T) {
+	c := newStartedSimCluster(t, 4)
+	defer c.Stop()
+
+	byzantine := c.node(1)
+	c.net.Intercept(byzantine.ip, equivocateVotes(byzantine.reactor))
+
+	c.waitHeight(8, 3*time.Minute)
+	c.checkSafety()
+}
+
+func TestSimCommitteeRotation(t *testing.T) {
+	c := newStartedSimCluster(t, 4)
+	defer c.Stop()
+
+	c.waitHeight(MIN_MBLOCKS_AN_EPOCH+1, time.Minute)
+	epoch := c.node(0).chain.BestBlock().GetBlockEpoch()
+
+	c.pow.Trigger()
+	c.waitFor(3*time.Minute, "K-block", func() bool {
+		for _, n := range c.nodes {
+			if n.chain.BestBlock().Header().LastKBlockHeight() == 0 {
+				return false
+			}
+		}
+		return true
+	})
+
+	// the new committee keeps committing blocks in the next epoch
+	kBlockHeight := c.node(0).chain.BestBlock().Header().LastKBlockHeight()
+	c.waitHeight(kBlockHeight+3, 3*time.Minute)
+	c.checkSafety()
+
+	for _, n := range c.nodes {
+		kblk, err := n.chain.GetTrunkBlock(kBlockHeight)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if kblk.Header().BlockType() != block.BLOCK_TYPE_K_BLOCK {
+			t.Fatalf("%v: block %v is not a K-block", n.name, kBlockHeight)
+		}
+		if e := n.chain.BestBlock().GetBlockEpoch(); e <= epoch {
+			t.Fatalf("%v: epoch not rotated, got %v, was %v", n.name, e, epoch)
+		}
+	}
+}
+
+type simCrashPoint int
+
+const (
+	simCrashBeforeVote    simCrashPoint = iota // vote is neither persisted nor sent
+	simCrashAfterVote                          // vote is persisted but not sent
+	simCrashAfterVoteSent                      // vote is persisted and sent
+	simCrashAfterLock                          // lock is persisted
+)
+
+type simVoteKey struct {
+	epoch  uint64
+	height uint32
+	round  uint32
+}
+
+// simCrashStore is the pacemaker state store of the node to crash. Once armed,
+// the node crashes at the first save of the crash point: nothing it saves or
+// sends reaches the outside until the pacemaker restarts and loads the state.
+type simCrashStore struct {
+	PMStateStore
+	point simCrashPoint
+
+	lock    sync.Mutex
+	prev    PMSafetyState
+	armed   bool
+	down    bool
+	vote    simVoteKey // the vote saved at crash
+	crashed chan struct{}
+}
+
+func newSimCrashStore(store PMStateStore, point simCrashPoint) *simCrashStore {
+	return &simCrashStore{PMStateStore: store, point: point, crashed: make(chan struct{}, 1)}
+}
+
+func (s *simCrashStore) Arm() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.armed = true
+}
+
+func (s *simCrashStore) Load() (*PMSafetyState, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	// the pacemaker restarted
+	s.down = false
+	return s.PMStateStore.Load()
+}
+
+func (s *simCrashStore) Save(state *PMSafetyState) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.down {
+		return errors.New("sim: crashed")
+	}
+
+	vote := state.LastVotedHeight != s.prev.LastVotedHeight || state.LastVotedRound != s.prev.LastVotedRound
+	lock := !vote && state.LockedHeight > s.prev.LockedHeight
+	crash := s.armed && ((s.point == simCrashAfterLock && lock) || (s.point != simCrashAfterLock && vote))
+	if crash {
+		s.armed, s.down = false, true
+		s.vote = simVoteKey{state.EpochID, state.LastVotedHeight, state.LastVotedRound}
+		s.crashed <- struct{}{}
+		if s.point == simCrashBeforeVote {
+			return errors.New("sim: crashed")
+		}
+	}
+	if err := s.PMStateStore.Save(state); err != nil {
+		return err
+	}
+	s.prev = *state
+	return nil
+}
+
+// sendable reports whether the message is sent before the crash.
+func (s *simCrashStore) sendable(msg ConsensusMessage) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if !s.down {
+		return true
+	}
+	vote, ok := msg.(*PMVoteMessage)
+	if !ok || s.point != simCrashAfterVoteSent {
+		return false
+	}
+	h := vote.CSMsgCommonHeader
+	return simVoteKey{h.EpochID, h.Height, h.Round} == s.vote
+}
+
+// testSimRestart crashes one node at the crash point and restarts its pacemaker
+// from the saved state. Proposals keep going through OnReceiveProposal, the
+// restarted node must catch up and never vote twice in the same round.
+func testSimRestart(t *testing.T, point simCrashPoint) {
+	if testing.Short() {
+		t.Skip("skip consensus simulation in short mode")
+	}
+	dir, err := ioutil.TempDir("", "pmrestart")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	c := newSimCluster(t, 4)
+	victim := c.node(3)
+	store := newSimCrashStore(NewPMStateStore(filepath.Join(dir, "pacemaker-state.rlp")), point)
+	victim.reactor.SetPMStateStore(store)
+
+	var (
+		mtx   sync.Mutex
+		votes = make(map[simVoteKey]int)
+	)
+	c.net.Intercept(victim.ip, func(env simEnvelope) []simEnvelope {
+		msg, err := decodeSimMsg(env.data)
+		if err != nil {
+			return []simEnvelope{env}
+		}
+		if vote, ok := msg.(*PMVoteMessage); ok {
+			h := vote.CSMsgCommonHeader
+			mtx.Lock()
+			votes[simVoteKey{h.EpochID, h.Height, h.Round}]++
+			mtx.Unlock()
+		}
+		if !store.sendable(msg) {
+			return nil
+		}
+		return []simEnvelope{env}
+	})
+
+	c.Start()
+	defer c.Stop()
+
+	c.waitHeight(3, time.Minute)
+	store.Arm()
+	select {
+	case <-store.crashed:
+	case <-time.After(time.Minute):
+		t.Fatal("timeout waiting for crash")
+	}
+	victim.reactor.csPacemaker.Restart(PMModeNormal)
+	crashed := store.vote
+
+	c.waitHeight(victim.bestHeight()+4, 3*time.Minute)
+	c.checkSafety()
+
+	mtx.Lock()
+	defer mtx.Unlock()
+	votedAfter := false
+	for k, n := range votes {
+		if n > 1 {
+			t.Fatalf("voted %v times at epoch %v height %v round %v", n, k.epoch, k.height, k.round)
+		}
+		if k.epoch == crashed.epoch && k.height > crashed.height {
+			votedAfter = true
+		}
+	}
+	if !votedAfter {
+		t.Fatal("no vote after restart")
+	}
+}
+
+func TestSimRestartBeforeVote(t *testing.T) {
+	testSimRestart(t, simCrashBeforeVote)
+}
+
+func TestSimRestartAfterVote(t *testing.T) {
+	testSimRestart(t, simCrashAfterVote)
+}
+
+func TestSimRestartAfterVoteSent(t *testing.T) {
+	testSimRestart(t, simCrashAfterVoteSent)
+}
+
+func TestSimRestartAfterLock(t *testing.T) {
+	testSimRestart(t, simCrashAfterLock)
+}
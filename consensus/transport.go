@@ -0,0 +1,127 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package consensus
+
+import (
+	"bytes"
+	"net/http"
+	"time"
+
+	"github.com/dfinlab/meter/block"
+	"github.com/dfinlab/meter/comm"
+	"github.com/dfinlab/meter/logdb"
+	"github.com/dfinlab/meter/packer"
+	"github.com/dfinlab/meter/powpool"
+	"github.com/dfinlab/meter/txpool"
+	"github.com/dfinlab/meter/types"
+)
+
+// Transport delivers the marshaled consensus messages to a peer.
+type Transport interface {
+	SendPacemakerMsg(to types.NetAddress, rawData []byte) error
+	SendCommitteeMsg(to types.NetAddress, rawData []byte) error
+}
+
+// httpTransport posts messages to the observe server of the peer.
+type httpTransport struct{}
+
+func (t *httpTransport) SendPacemakerMsg(to types.NetAddress, rawData []byte) error {
+	// full size message may taker longer time (> 2s) to complete the tranport.
+	return t.post("http://"+to.IP.String()+":8670/pacemaker", rawData)
+}
+
+func (t *httpTransport) SendCommitteeMsg(to types.NetAddress, rawData []byte) error {
+	return t.post("http://"+to.IP.String()+":8670/committee", rawData)
+}
+
+func (t *httpTransport) post(url string, rawData []byte) error {
+	var netClient = &http.Client{
+		Timeout: time.Second * 4, // 2
+	}
+	_, err := netClient.Post(url, "application/json", bytes.NewBuffer(rawData))
+	return err
+}
+
+// Syncer is the part of communicator the reactor depends on.
+type Syncer interface {
+	Synced() <-chan struct{}
+	TriggerSync()
+	BroadcastBlock(blk *block.Block)
+}
+
+// PowDecider is the part of pow pool the reactor depends on.
+type PowDecider interface {
+	GetPowDecision() (bool, *powpool.PowResult)
+	Wash() error
+	InitialAddKframe(info *powpool.PowBlockInfo) error
+	ReplayFrom(startHeight int32) error
+}
+
+// Dependencies are the node services used by the reactor. Fields left unset
+// fall back to the global instances, which is what a full node does. Setting
+// all of them allows several reactors to run inside one process.
+type Dependencies struct {
+	Transport Transport
+	Syncer    Syncer
+	PowPool   PowDecider
+	TxPool    *txpool.TxPool
+	Packer    *packer.Packer
+	LogDB     *logdb.LogDB
+}
+
+// SetDependencies overrides the node services used by the reactor.
+// It must be called before OnStart.
+func (conR *ConsensusReactor) SetDependencies(deps Dependencies) {
+	if deps.Transport == nil {
+		deps.Transport = conR.deps.Transport
+	}
+	conR.deps = deps
+}
+
+func (conR *ConsensusReactor) transport() Transport {
+	return conR.deps.Transport
+}
+
+func (conR *ConsensusReactor) syncer() Syncer {
+	if conR.deps.Syncer != nil {
+		return conR.deps.Syncer
+	}
+	if c := comm.GetGlobCommInst(); c != nil {
+		return c
+	}
+	return nil
+}
+
+func (conR *ConsensusReactor) powPool() PowDecider {
+	if conR.deps.PowPool != nil {
+		return conR.deps.PowPool
+	}
+	if p := powpool.GetGlobPowPoolInst(); p != nil {
+		return p
+	}
+	return nil
+}
+
+func (conR *ConsensusReactor) txPool() *txpool.TxPool {
+	if conR.deps.TxPool != nil {
+		return conR.deps.TxPool
+	}
+	return txpool.GetGlobTxPoolInst()
+}
+
+func (conR *ConsensusReactor) packer() *packer.Packer {
+	if conR.deps.Packer != nil {
+		return conR.deps.Packer
+	}
+	return packer.GetGlobPackerInst()
+}
+
+func (conR *ConsensusReactor) logDB() *logdb.LogDB {
+	if conR.deps.LogDB != nil {
+		return conR.deps.LogDB
+	}
+	return logdb.GetGlobalLogDBInstance()
+}
@@ -39,6 +39,18 @@ int search(pbc_param_ptr params, unsigned int d, unsigned int bitlimit) {
 	}
 	return pbc_cm_search_d(callback, params, d, bitlimit);
 }
+int element_in_subgroup(element_ptr e) {
+	element_t t;
+	int ok;
+	if (element_is1(e)) {
+		return 0;
+	}
+	element_init_same_as(t, e);
+	element_pow_mpz(t, e, e->field->order);
+	ok = element_is1(t);
+	element_clear(t);
+	return ok;
+}
 */
 import "C"
 
@@ -336,6 +348,25 @@ func Sign(hash [sha256.Size]byte, secret PrivateKey) Signature {
 
 }
 
+// popHash returns the message digest signed to prove the possession of the key.
+func popHash(key PublicKey) [sha256.Size]byte {
+	return sha256.Sum256(append([]byte("BLS Proof of Possession:"), key.system.PubKeyToBytes(key)...))
+}
+
+// ProvePossession signs the public key with the private key of the signer. The
+// proof is required to aggregate signatures of the same message, which are
+// otherwise open to rogue key attacks. This function allocates C structures on
+// the C heap using malloc. It is the responsibility of the caller to prevent
+// memory leaks by arranging for the C structures to be freed.
+func ProvePossession(secret PrivateKey, key PublicKey) Signature {
+	return Sign(popHash(key), secret)
+}
+
+// VerifyPossession verifies the proof of possession of the public key.
+func VerifyPossession(proof Signature, key PublicKey) bool {
+	return Verify(proof, popHash(key), key)
+}
+
 // Verify a signature on the message digest using the public key of the signer.
 func Verify(signature Signature, hash [sha256.Size]byte, key PublicKey) bool {
 
@@ -571,14 +602,22 @@ func (system System) PubKeyToBytes(pubKey PublicKey) []byte {
 }
 
 // Convert a byte slice to a PublicKey.
+// Subgroup membership is not checked, see InSubgroup.
 func (system System) PubKeyFromBytes(bytes []byte) (PublicKey, error) {
 	gx, err := system.SigFromBytes(bytes)
 	if err != nil {
-		errors.New("bls.FromBytes: get PublicKey failed.")
+		return PublicKey{}, errors.New("bls.FromBytes: get PublicKey failed.")
 	}
 	return PublicKey{system, gx}, nil
 }
 
+// InSubgroup checks whether the public key is a member of the prime order
+// subgroup other than the identity. Keys from untrusted sources must be checked
+// before use.
+func (key PublicKey) InSubgroup() bool {
+	return key.gx.InSubgroup()
+}
+
 // Convert a PrivateKey to a byte slice
 func (system System) PrivKeyToBytes(privKey PrivateKey) []byte {
 	return system.PrivSigToBytes(privKey.x)
@@ -588,11 +627,17 @@ func (system System) PrivKeyToBytes(privKey PrivateKey) []byte {
 func (system System) PrivKeyFromBytes(bytes []byte) (PrivateKey, error) {
 	x, err := system.PrivSigFromBytes(bytes)
 	if err != nil {
-		errors.New("bls.FromBytes: get PrivateKey failed.")
+		return PrivateKey{}, errors.New("bls.FromBytes: get PrivateKey failed.")
 	}
 	return PrivateKey{system, x}, nil
 }
 
+// InSubgroup checks whether the element is a member of the prime order subgroup
+// of its group, other than the identity. Elements decoded from bytes may not be.
+func (element Element) InSubgroup() bool {
+	return C.element_in_subgroup(element.get) == 1
+}
+
 // Free the memory occupied by the element. The element cannot be used after
 // calling this function.
 func (element Element) Free() {
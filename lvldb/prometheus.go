@@ -0,0 +1,66 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package lvldb
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	readBytesDesc    = prometheus.NewDesc("leveldb_read_bytes_total", "Bytes read from disk by level db", []string{"db"}, nil)
+	writeBytesDesc   = prometheus.NewDesc("leveldb_write_bytes_total", "Bytes written to disk by level db", []string{"db"}, nil)
+	openedTablesDesc = prometheus.NewDesc("leveldb_opened_tables", "Number of opened tables of level db", []string{"db"}, nil)
+	cachedBlocksDesc = prometheus.NewDesc("leveldb_cached_block_bytes", "Size of cached blocks of level db", []string{"db"}, nil)
+	aliveItersDesc   = prometheus.NewDesc("leveldb_alive_iterators", "Number of alive iterators of level db", []string{"db"}, nil)
+)
+
+// statsCollector exports the properties of level db on scraping.
+type statsCollector struct {
+	ldb  *LevelDB
+	name string
+}
+
+// StatsCollector returns a prometheus collector of the db stats, labeled by name.
+func (ldb *LevelDB) StatsCollector(name string) prometheus.Collector {
+	return &statsCollector{ldb, name}
+}
+
+func (c *statsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- readBytesDesc
+	ch <- writeBytesDesc
+	ch <- openedTablesDesc
+	ch <- cachedBlocksDesc
+	ch <- aliveItersDesc
+}
+
+func (c *statsCollector) Collect(ch chan<- prometheus.Metric) {
+	if iostats, err := c.ldb.db.GetProperty("leveldb.iostats"); err == nil {
+		var read, write float64
+		if _, err := fmt.Sscanf(iostats, "Read(MB):%f Write(MB):%f", &read, &write); err == nil {
+			ch <- prometheus.MustNewConstMetric(readBytesDesc, prometheus.CounterValue, read*1024*1024, c.name)
+			ch <- prometheus.MustNewConstMetric(writeBytesDesc, prometheus.CounterValue, write*1024*1024, c.name)
+		}
+	}
+	c.collectProperty(ch, openedTablesDesc, "leveldb.openedtables")
+	c.collectProperty(ch, cachedBlocksDesc, "leveldb.cachedblock")
+	c.collectProperty(ch, aliveItersDesc, "leveldb.aliveiters")
+}
+
+// collectProperty exports the integer property as a gauge.
+func (c *statsCollector) collectProperty(ch chan<- prometheus.Metric, desc *prometheus.Desc, name string) {
+	prop, err := c.ldb.db.GetProperty(name)
+	if err != nil {
+		return
+	}
+	v, err := strconv.ParseFloat(prop, 64)
+	if err != nil {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, v, c.name)
+}
@@ -0,0 +1,39 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package lvldb
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatsCollector(t *testing.T) {
+	db, err := NewMem()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	c := db.StatsCollector("test")
+
+	descs := make(chan *prometheus.Desc, 10)
+	c.Describe(descs)
+	close(descs)
+	known := make(map[*prometheus.Desc]bool)
+	for d := range descs {
+		known[d] = true
+	}
+
+	metrics := make(chan prometheus.Metric, 10)
+	c.Collect(metrics)
+	close(metrics)
+	n := 0
+	for m := range metrics {
+		assert.True(t, known[m.Desc()], m.Desc().String())
+		n++
+	}
+	assert.NotZero(t, n)
+}
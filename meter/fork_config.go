@@ -13,15 +13,37 @@ import (
 // ForkConfig config for a fork.
 type ForkConfig struct {
 	FixTransferLog uint32
+	Istanbul       uint32 // EVM istanbul rules
+	Berlin         uint32 // EVM berlin rules
+
+	ValidatorRewardHistory uint32 // per-epoch validator reward distribution kept in state
+	StakingBucketOps       uint32 // staking bucket top-up, split and merge
+	StakingParams          uint32 // staking economics governed by params, validated when set
+	StakingCompound        uint32 // staking rewards compounding
+	CandidateProfile       uint32 // candidate metadata and deferred commission changes
+	AuctionBids            uint32 // individual auction bids and bid cancellation
+	AuctionSummaryArchive  uint32 // auction summaries archived individually
+	AccountLockVesting     uint32 // vesting tranches of account lock profiles
 }
 
 func (fc ForkConfig) String() string {
-	return fmt.Sprintf("FTRL: #%v", fc.FixTransferLog)
+	return fmt.Sprintf("FTRL: #%v, IST: #%v, BER: #%v, VRH: #%v, SBO: #%v, SP: #%v, SC: #%v, CP: #%v, AB: #%v, ASA: #%v, ALV: #%v", fc.FixTransferLog, fc.Istanbul, fc.Berlin, fc.ValidatorRewardHistory, fc.StakingBucketOps, fc.StakingParams, fc.StakingCompound, fc.CandidateProfile, fc.AuctionBids, fc.AuctionSummaryArchive, fc.AccountLockVesting)
 }
 
 // NoFork a special config without any forks.
 var NoFork = ForkConfig{
 	FixTransferLog: math.MaxUint32,
+	Istanbul:       math.MaxUint32,
+	Berlin:         math.MaxUint32,
+
+	ValidatorRewardHistory: math.MaxUint32,
+	StakingBucketOps:       math.MaxUint32,
+	StakingParams:          math.MaxUint32,
+	StakingCompound:        math.MaxUint32,
+	CandidateProfile:       math.MaxUint32,
+	AuctionBids:            math.MaxUint32,
+	AuctionSummaryArchive:  math.MaxUint32,
+	AccountLockVesting:     math.MaxUint32,
 }
 
 // for well-known networks
@@ -29,10 +51,32 @@ var forkConfigs = map[Bytes32]ForkConfig{
 	// mainnet
 	MustParseBytes32("0x00000000851caf3cfdb6e899cf5958bfb1ac3413d346d43539627e6be7ec1b4a"): {
 		FixTransferLog: 1072000,
+		Istanbul:       math.MaxUint32,
+		Berlin:         math.MaxUint32,
+
+		ValidatorRewardHistory: math.MaxUint32,
+		StakingBucketOps:       math.MaxUint32,
+		StakingParams:          math.MaxUint32,
+		StakingCompound:        math.MaxUint32,
+		CandidateProfile:       math.MaxUint32,
+		AuctionBids:            math.MaxUint32,
+		AuctionSummaryArchive:  math.MaxUint32,
+		AccountLockVesting:     math.MaxUint32,
 	},
 	// testnet
 	MustParseBytes32("0x000000000b2bce3c70bc649a02749e8687721b09ed2e15997f466536b20bb127"): {
 		FixTransferLog: 1080000,
+		Istanbul:       math.MaxUint32,
+		Berlin:         math.MaxUint32,
+
+		ValidatorRewardHistory: math.MaxUint32,
+		StakingBucketOps:       math.MaxUint32,
+		StakingParams:          math.MaxUint32,
+		StakingCompound:        math.MaxUint32,
+		CandidateProfile:       math.MaxUint32,
+		AuctionBids:            math.MaxUint32,
+		AuctionSummaryArchive:  math.MaxUint32,
+		AccountLockVesting:     math.MaxUint32,
 	},
 }
 
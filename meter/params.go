@@ -71,10 +71,26 @@ var (
 	KeyAuctionReservedPrice   = BytesToBytes32([]byte("auction-reserved-price"))
 	KeyMinRequiredByDelegate  = BytesToBytes32([]byte("minimium-require-by-delegate"))
 	KeyAuctionInitRelease     = BytesToBytes32([]byte("auction-initial-release"))
+	KeyAuctionCancelWindow    = BytesToBytes32([]byte("auction-cancel-window")) // seconds, default of auction applies while unset
 	KeyBorrowInterestRate     = BytesToBytes32([]byte("borrower-interest-rate"))
 	KeyConsensusCommitteeSize = BytesToBytes32([]byte("consensus-committee-size"))
 	KeyConsensusDelegateSize  = BytesToBytes32([]byte("consensus-delegate-size"))
 
+	// staking lock options and economics, hard-coded defaults of staking apply while unset
+	KeyStakingOneDayLockRate    = BytesToBytes32([]byte("staking-one-day-lock-rate"))
+	KeyStakingOneDayLockTime    = BytesToBytes32([]byte("staking-one-day-lock-time"))
+	KeyStakingOneWeekLockRate   = BytesToBytes32([]byte("staking-one-week-lock-rate"))
+	KeyStakingOneWeekLockTime   = BytesToBytes32([]byte("staking-one-week-lock-time"))
+	KeyStakingTwoWeekLockRate   = BytesToBytes32([]byte("staking-two-week-lock-rate"))
+	KeyStakingTwoWeekLockTime   = BytesToBytes32([]byte("staking-two-week-lock-time"))
+	KeyStakingThreeWeekLockRate = BytesToBytes32([]byte("staking-three-week-lock-rate"))
+	KeyStakingThreeWeekLockTime = BytesToBytes32([]byte("staking-three-week-lock-time"))
+	KeyStakingFourWeekLockRate  = BytesToBytes32([]byte("staking-four-week-lock-rate"))
+	KeyStakingFourWeekLockTime  = BytesToBytes32([]byte("staking-four-week-lock-time"))
+	KeyStakingForeverLockRate   = BytesToBytes32([]byte("staking-forever-lock-rate"))
+	KeyStakingUnboundGrace      = BytesToBytes32([]byte("staking-unbound-grace"))
+	KeyStakingShareScale        = BytesToBytes32([]byte("staking-share-scale"))
+
 	//  mtr-erc20, 0x00000000000000006e61746976652d6d74722d65726332302d61646472657373
 	KeyNativeMtrERC20Address = BytesToBytes32([]byte("native-mtr-erc20-address"))
 	// mtrg-erc20, 0x000000000000006e61746976652d6d7472672d65726332302d61646472657373
@@ -0,0 +1,75 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package metric
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// The collectors created here are registered to the default prometheus registry
+// on creation, so they are expected to be package level variables.
+
+// NewGauge creates and registers a gauge.
+func NewGauge(name, help string) prometheus.Gauge {
+	g := prometheus.NewGauge(prometheus.GaugeOpts{Name: name, Help: help})
+	prometheus.MustRegister(g)
+	return g
+}
+
+// NewGaugeVec creates and registers a gauge partitioned by labels.
+func NewGaugeVec(name, help string, labels ...string) *prometheus.GaugeVec {
+	g := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: name, Help: help}, labels)
+	prometheus.MustRegister(g)
+	return g
+}
+
+// NewCounter creates and registers a counter.
+func NewCounter(name, help string) prometheus.Counter {
+	c := prometheus.NewCounter(prometheus.CounterOpts{Name: name, Help: help})
+	prometheus.MustRegister(c)
+	return c
+}
+
+// NewCounterVec creates and registers a counter partitioned by labels.
+func NewCounterVec(name, help string, labels ...string) *prometheus.CounterVec {
+	c := prometheus.NewCounterVec(prometheus.CounterOpts{Name: name, Help: help}, labels)
+	prometheus.MustRegister(c)
+	return c
+}
+
+// NewHistogram creates and registers a histogram of durations in seconds.
+func NewHistogram(name, help string) prometheus.Histogram {
+	h := prometheus.NewHistogram(prometheus.HistogramOpts{Name: name, Help: help, Buckets: prometheus.DefBuckets})
+	prometheus.MustRegister(h)
+	return h
+}
+
+// NewHistogramVec creates and registers a histogram of durations in seconds
+// partitioned by labels.
+func NewHistogramVec(name, help string, labels ...string) *prometheus.HistogramVec {
+	h := prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: name, Help: help, Buckets: prometheus.DefBuckets}, labels)
+	prometheus.MustRegister(h)
+	return h
+}
+
+// Register registers the collector, it panics if failed.
+func Register(c prometheus.Collector) {
+	prometheus.MustRegister(c)
+}
+
+// ObserveSince observes the duration since start in seconds.
+func ObserveSince(o prometheus.Observer, start time.Time) {
+	o.Observe(time.Since(start).Seconds())
+}
+
+// Handler returns the http handler exposing the registered metrics.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
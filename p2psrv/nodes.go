@@ -29,7 +29,7 @@ func (ns *Nodes) DecodeRLP(s *rlp.Stream) error {
 			if err != rlp.EOL {
 				return err
 			}
-			return nil
+			return s.ListEnd()
 		}
 		*ns = append(*ns, discover.NewNode(n.ID, n.IP, n.UDP, n.TCP))
 	}
@@ -56,4 +56,15 @@ type Options struct {
 
 	// If NoDial is true, the server will not dial any peers.
 	NoDial bool
+
+	// StaticNodes are always kept connected. They are redialed with backoff
+	// once disconnected.
+	StaticNodes Nodes
+
+	// TrustedNodes are allowed to connect even above the peer limit.
+	TrustedNodes Nodes
+
+	// If WhitelistOnly is true, only static and trusted nodes are dialed or accepted,
+	// and discovery is disabled.
+	WhitelistOnly bool
 }
@@ -19,10 +19,17 @@ import (
 	"github.com/ethereum/go-ethereum/p2p/discv5"
 	"github.com/ethereum/go-ethereum/p2p/nat"
 	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
 )
 
 var log = log15.New("pkg", "p2psrv")
 
+// flags passed to p2p.Server.SetupConn, mirror of unexported p2p.connFlag.
+const (
+	dynDialedConn    = 1 << 0
+	staticDialedConn = 1 << 1
+)
+
 // Server p2p server wraps ethereum's p2p.Server, and handles discovery v5 stuff.
 type Server struct {
 	opts            Options
@@ -33,6 +40,8 @@ type Server struct {
 	knownNodes      *cache.PrioCache
 	discoveredNodes *cache.RandCache
 	dialingNodes    *nodeMap
+	staticNodes     *staticNodes
+	trustedNodes    *nodeMap
 }
 
 // New create a p2p server.
@@ -43,27 +52,38 @@ func New(opts *Options) *Server {
 		knownNodes.Set(node.ID, node, 0)
 		discoveredNodes.Set(node.ID, node)
 	}
+	staticNodes := newStaticNodes()
+	for _, node := range opts.StaticNodes {
+		staticNodes.Add(node)
+	}
+	trustedNodes := newNodeMap()
+	for _, node := range opts.TrustedNodes {
+		trustedNodes.Add(node)
+	}
 
 	return &Server{
 		opts: *opts,
 		srv: &p2p.Server{
 			Config: p2p.Config{
-				Name:        opts.Name,
-				PrivateKey:  opts.PrivateKey,
-				MaxPeers:    opts.MaxPeers,
-				NoDiscovery: true,
-				DiscoveryV5: false, // disable discovery inside p2p.Server instance
-				ListenAddr:  opts.ListenAddr,
-				NetRestrict: opts.NetRestrict,
-				NAT:         opts.NAT,
-				NoDial:      opts.NoDial,
-				DialRatio:   int(math.Sqrt(float64(opts.MaxPeers))),
+				Name:         opts.Name,
+				PrivateKey:   opts.PrivateKey,
+				MaxPeers:     opts.MaxPeers,
+				NoDiscovery:  true,
+				DiscoveryV5:  false, // disable discovery inside p2p.Server instance
+				ListenAddr:   opts.ListenAddr,
+				NetRestrict:  opts.NetRestrict,
+				NAT:          opts.NAT,
+				NoDial:       opts.NoDial,
+				DialRatio:    int(math.Sqrt(float64(opts.MaxPeers))),
+				TrustedNodes: opts.TrustedNodes,
 			},
 		},
 		done:            make(chan struct{}),
 		knownNodes:      knownNodes,
 		discoveredNodes: discoveredNodes,
 		dialingNodes:    newNodeMap(),
+		staticNodes:     staticNodes,
+		trustedNodes:    trustedNodes,
 	}
 }
 
@@ -85,6 +105,11 @@ func (s *Server) Start(protocols []*Protocol) error {
 			}
 			log := log.New("peer", peer, "dir", dir)
 
+			if s.opts.WhitelistOnly && !s.isWhitelisted(peer.ID()) {
+				log.Debug("reject peer not in whitelist")
+				return errors.New("not in whitelist")
+			}
+
 			log.Debug("peer connected")
 			startTime := mclock.Now()
 			defer func() {
@@ -104,7 +129,7 @@ func (s *Server) Start(protocols []*Protocol) error {
 		return err
 	}
 	// fmt.Println("server Node:", s.NodeInfo())
-	if !s.opts.NoDiscovery {
+	if !s.opts.NoDiscovery && !s.opts.WhitelistOnly {
 		if err := s.listenDiscV5(); err != nil {
 			return err
 		}
@@ -123,7 +148,10 @@ func (s *Server) Start(protocols []*Protocol) error {
 	}
 	log.Info("start up", "self", s.Self())
 
-	s.goes.Go(s.dialLoop)
+	if !s.opts.WhitelistOnly {
+		s.goes.Go(s.dialLoop)
+	}
+	s.goes.Go(s.staticDialLoop)
 	return nil
 }
 
@@ -149,16 +177,27 @@ func (s *Server) KnownNodes() Nodes {
 
 // AddStatic connects to the given node and maintains the connection until the
 // server is shut down. If the connection fails for any reason, the server will
-// attempt to reconnect the peer.
+// attempt to reconnect the peer with backoff.
 func (s *Server) AddStatic(node *discover.Node) {
-	s.srv.AddPeer(node)
+	s.staticNodes.Add(node)
 }
 
-// RemoveStatic disconnects from the given node
+// RemoveStatic stops maintaining the connection to the given node, and disconnects from it.
 func (s *Server) RemoveStatic(node *discover.Node) {
+	s.staticNodes.Remove(node.ID)
 	s.srv.RemovePeer(node)
 }
 
+// StaticNodes returns nodes kept connected.
+func (s *Server) StaticNodes() Nodes {
+	return s.staticNodes.Nodes()
+}
+
+// isWhitelisted returns whether the node is a static or trusted node.
+func (s *Server) isWhitelisted(id discover.NodeID) bool {
+	return s.staticNodes.Contains(id) || s.trustedNodes.Contains(id)
+}
+
 // NodeInfo gathers and returns a collection of metadata known about the host.
 func (s *Server) NodeInfo() *p2p.NodeInfo {
 	return s.srv.NodeInfo()
@@ -293,7 +332,7 @@ func (s *Server) dialLoop() {
 			s.dialingNodes.Add(node)
 			// don't use goes.Go, since the dial process can't be interrupted
 			go func() {
-				if err := s.tryDial(node); err != nil {
+				if err := s.tryDial(node, false); err != nil {
 					s.dialingNodes.Remove(node.ID)
 					log.Debug("failed to dial node", "err", err)
 				}
@@ -318,12 +357,16 @@ func (s *Server) dialLoop() {
 	}
 }
 
-func (s *Server) tryDial(node *discover.Node) error {
+func (s *Server) tryDial(node *discover.Node, static bool) error {
 	conn, err := s.srv.Dialer.Dial(node)
 	if err != nil {
 		return err
 	}
-	return s.srv.SetupConn(conn, 1, node)
+	// static dialed conns are not limited by MaxPeers
+	if static {
+		return s.srv.SetupConn(conn, staticDialedConn, node)
+	}
+	return s.srv.SetupConn(conn, dynDialedConn, node)
 }
 
 func (s *Server) GetDiscoveredNodes() []*discover.Node {
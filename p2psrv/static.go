@@ -0,0 +1,153 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package p2psrv
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/p2p/discover"
+)
+
+const (
+	staticDialInterval   = time.Second
+	staticDialMinBackoff = 2 * time.Second
+	staticDialMaxBackoff = 5 * time.Minute
+)
+
+// staticNode is a node kept connected.
+type staticNode struct {
+	node     *discover.Node
+	dialing  bool
+	failures uint
+	nextDial time.Time
+}
+
+// backoff returns the delay before next dial after consecutive failures.
+func (sn *staticNode) backoff() time.Duration {
+	d := staticDialMinBackoff
+	for i := uint(1); i < sn.failures; i++ {
+		d *= 2
+		if d >= staticDialMaxBackoff {
+			return staticDialMaxBackoff
+		}
+	}
+	return d
+}
+
+// thread-safe static node set.
+type staticNodes struct {
+	m    map[discover.NodeID]*staticNode
+	lock sync.Mutex
+}
+
+func newStaticNodes() *staticNodes {
+	return &staticNodes{
+		m: make(map[discover.NodeID]*staticNode),
+	}
+}
+
+func (sns *staticNodes) Add(node *discover.Node) {
+	sns.lock.Lock()
+	defer sns.lock.Unlock()
+	if _, ok := sns.m[node.ID]; !ok {
+		sns.m[node.ID] = &staticNode{node: node}
+	}
+}
+
+func (sns *staticNodes) Remove(id discover.NodeID) bool {
+	sns.lock.Lock()
+	defer sns.lock.Unlock()
+	if _, ok := sns.m[id]; ok {
+		delete(sns.m, id)
+		return true
+	}
+	return false
+}
+
+func (sns *staticNodes) Contains(id discover.NodeID) bool {
+	sns.lock.Lock()
+	defer sns.lock.Unlock()
+	return sns.m[id] != nil
+}
+
+func (sns *staticNodes) Nodes() Nodes {
+	sns.lock.Lock()
+	defer sns.lock.Unlock()
+	nodes := make(Nodes, 0, len(sns.m))
+	for _, sn := range sns.m {
+		nodes = append(nodes, sn.node)
+	}
+	return nodes
+}
+
+// toDial returns nodes which are neither connected nor being dialed, and whose backoff expired.
+// Returned nodes are marked as dialing.
+func (sns *staticNodes) toDial(connected map[discover.NodeID]bool, now time.Time) []*discover.Node {
+	sns.lock.Lock()
+	defer sns.lock.Unlock()
+	var nodes []*discover.Node
+	for id, sn := range sns.m {
+		if connected[id] {
+			sn.failures = 0
+			continue
+		}
+		if sn.dialing || now.Before(sn.nextDial) {
+			continue
+		}
+		sn.dialing = true
+		nodes = append(nodes, sn.node)
+	}
+	return nodes
+}
+
+// dialed records the dial result of the node.
+func (sns *staticNodes) dialed(id discover.NodeID, err error, now time.Time) {
+	sns.lock.Lock()
+	defer sns.lock.Unlock()
+	sn, ok := sns.m[id]
+	if !ok {
+		return
+	}
+	sn.dialing = false
+	if err != nil {
+		sn.failures++
+	} else {
+		// also back off a little on success, in case the connection is dropped immediately
+		sn.failures = 1
+	}
+	sn.nextDial = now.Add(sn.backoff())
+}
+
+func (s *Server) staticDialLoop() {
+	ticker := time.NewTicker(staticDialInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			connected := make(map[discover.NodeID]bool)
+			for _, peer := range s.srv.Peers() {
+				connected[peer.ID()] = true
+			}
+			for _, node := range s.staticNodes.toDial(connected, time.Now()) {
+				node := node
+				log := log.New("node", node)
+				log.Debug("try to dial static node")
+				// don't use goes.Go, since the dial process can't be interrupted
+				go func() {
+					err := s.tryDial(node, true)
+					if err != nil {
+						log.Debug("failed to dial static node", "err", err)
+					}
+					s.staticNodes.dialed(node.ID, err, time.Now())
+				}()
+			}
+		case <-s.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package p2psrv
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/p2p/discover"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStaticNodes(t *testing.T) {
+	n1 := discover.NewNode(discover.NodeID{1}, net.ParseIP("127.0.0.1"), 11235, 11235)
+	n2 := discover.NewNode(discover.NodeID{2}, net.ParseIP("127.0.0.1"), 11236, 11236)
+
+	sns := newStaticNodes()
+	sns.Add(n1)
+	sns.Add(n2)
+	assert.True(t, sns.Contains(n1.ID))
+	assert.Equal(t, 2, len(sns.Nodes()))
+
+	now := time.Now()
+	connected := map[discover.NodeID]bool{n2.ID: true}
+	assert.Equal(t, []*discover.Node{n1}, sns.toDial(connected, now))
+	assert.Empty(t, sns.toDial(connected, now), "should be dialing")
+
+	// back off exponentially on failures
+	sns.dialed(n1.ID, errors.New("failed"), now)
+	assert.Empty(t, sns.toDial(connected, now.Add(staticDialMinBackoff-time.Millisecond)))
+	assert.Equal(t, 1, len(sns.toDial(connected, now.Add(staticDialMinBackoff))))
+	sns.dialed(n1.ID, errors.New("failed"), now)
+	assert.Empty(t, sns.toDial(connected, now.Add(staticDialMinBackoff)))
+	assert.Equal(t, 1, len(sns.toDial(connected, now.Add(2*staticDialMinBackoff))))
+
+	for i := 0; i < 20; i++ {
+		sns.dialed(n1.ID, errors.New("failed"), now)
+	}
+	assert.Equal(t, staticDialMaxBackoff, sns.m[n1.ID].backoff())
+
+	assert.True(t, sns.Remove(n1.ID))
+	assert.False(t, sns.Remove(n1.ID))
+	assert.False(t, sns.Contains(n1.ID))
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/dfinlab/meter/block"
 	"github.com/dfinlab/meter/meter"
 	"github.com/dfinlab/meter/runtime"
+	"github.com/dfinlab/meter/signer"
 	"github.com/dfinlab/meter/state"
 	"github.com/dfinlab/meter/tx"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -127,7 +128,23 @@ func (f *Flow) Pack(privateKey *ecdsa.PrivateKey, blockType uint32, lastKBlock u
 	if f.packer.nodeMaster != meter.Address(crypto.PubkeyToAddress(privateKey.PublicKey)) {
 		return nil, nil, nil, errors.New("private key mismatch")
 	}
+	return f.pack(blockType, lastKBlock, func(header *block.Header) ([]byte, error) {
+		return crypto.Sign(header.SigningHash().Bytes(), privateKey)
+	})
+}
+
+// PackWithSigner build the new block proposed at round of epoch, and sign it with
+// the signer, which may hold the key in another process.
+func (f *Flow) PackWithSigner(s signer.Signer, epoch uint64, round uint32, blockType uint32, lastKBlock uint32) (*block.Block, *state.Stage, tx.Receipts, error) {
+	if f.packer.nodeMaster != s.Address() {
+		return nil, nil, nil, errors.New("signer mismatch")
+	}
+	return f.pack(blockType, lastKBlock, func(header *block.Header) ([]byte, error) {
+		return s.SignBlock(epoch, round, header)
+	})
+}
 
+func (f *Flow) pack(blockType uint32, lastKBlock uint32, sign func(header *block.Header) ([]byte, error)) (*block.Block, *state.Stage, tx.Receipts, error) {
 	if err := f.runtime.Seeker().Err(); err != nil {
 		return nil, nil, nil, err
 	}
@@ -155,7 +172,7 @@ func (f *Flow) Pack(privateKey *ecdsa.PrivateKey, blockType uint32, lastKBlock u
 	}
 	newBlock := builder.Build()
 
-	sig, err := crypto.Sign(newBlock.Header().SigningHash().Bytes(), privateKey)
+	sig, err := sign(newBlock.Header())
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -21,9 +21,9 @@ import (
 	"github.com/dfinlab/meter/chain"
 	"github.com/dfinlab/meter/co"
 	"github.com/dfinlab/meter/meter"
+	"github.com/dfinlab/meter/metric"
 	"github.com/dfinlab/meter/state"
 	"github.com/ethereum/go-ethereum/event"
-	"github.com/prometheus/client_golang/prometheus"
 )
 
 const (
@@ -37,10 +37,7 @@ const (
 var (
 	GlobPowPoolInst *PowPool
 
-	powBlockRecvedGauge = prometheus.NewGauge(prometheus.GaugeOpts{
-		Name: "pow_block_recved",
-		Help: "Accumulated counter for received pow blocks since last k-block",
-	})
+	powBlockRecvedGauge = metric.NewGauge("pow_block_recved", "Accumulated counter for received pow blocks since last k-block")
 )
 
 // Options options for tx pool.
@@ -106,7 +103,6 @@ func New(options Options, chain *chain.Chain, stateCreator *state.Creator) *PowP
 	}
 	pool.goes.Go(pool.housekeeping)
 	SetGlobPowPoolInst(pool)
-	prometheus.MustRegister(powBlockRecvedGauge)
 
 	return pool
 }
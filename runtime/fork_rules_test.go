@@ -0,0 +1,190 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package runtime
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/dfinlab/meter/lvldb"
+	"github.com/dfinlab/meter/meter"
+	"github.com/dfinlab/meter/runtime/statedb"
+	"github.com/dfinlab/meter/state"
+	"github.com/dfinlab/meter/tx"
+	"github.com/dfinlab/meter/xenv"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// the forks of the tests, the blocks before are executed with the constantinople rules.
+const (
+	testIstanbul = 10
+	testBerlin   = 20
+)
+
+var (
+	testContract = meter.BytesToAddress([]byte("contract"))
+	testOrigin   = meter.BytesToAddress([]byte("origin"))
+)
+
+// execAt executes the code at the given block number, returns the gas used and the output.
+func execAt(t *testing.T, num uint32, code []byte) (uint64, *Output) {
+	kv, _ := lvldb.NewMem()
+	st, _ := state.New(meter.Bytes32{}, kv)
+	st.SetCode(testContract, code)
+	st.SetEnergy(testContract, big.NewInt(1234))
+
+	forkConfig := meter.ForkConfig{Istanbul: testIstanbul, Berlin: testBerlin}
+	rt := New(nil, st, &xenv.BlockContext{Number: num})
+	rt.forkConfig = forkConfig
+	rt.chainConfig = newChainConfig(nil, forkConfig)
+	rt.chainConfig.ChainID = big.NewInt(82)
+
+	out := rt.ExecuteClause(
+		tx.NewClause(&testContract),
+		0,
+		math.MaxUint64,
+		&xenv.TransactionContext{Origin: testOrigin})
+	if err := st.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return math.MaxUint64 - out.LeftOverGas, out
+}
+
+// returnTop returns the code which runs op and returns the word it pushed.
+func returnTop(op byte) []byte {
+	return []byte{
+		op,
+		0x60, 0x00, 0x52, // PUSH1 0, MSTORE
+		0x60, 0x20, 0x60, 0x00, 0xf3, // PUSH1 32, PUSH1 0, RETURN
+	}
+}
+
+func TestForkChainID(t *testing.T) {
+	code := returnTop(0x46) // CHAINID
+
+	_, out := execAt(t, testIstanbul-1, code)
+	assert.NotNil(t, out.VMErr, "invalid opcode before istanbul")
+
+	for _, num := range []uint32{testIstanbul, testBerlin} {
+		_, out = execAt(t, num, code)
+		assert.Nil(t, out.VMErr)
+		assert.Equal(t, common.BigToHash(big.NewInt(82)).Bytes(), out.Data)
+	}
+}
+
+func TestForkSelfBalance(t *testing.T) {
+	code := returnTop(0x47) // SELFBALANCE
+
+	_, out := execAt(t, testIstanbul-1, code)
+	assert.NotNil(t, out.VMErr, "invalid opcode before istanbul")
+
+	for _, num := range []uint32{testIstanbul, testBerlin} {
+		_, out = execAt(t, num, code)
+		assert.Nil(t, out.VMErr)
+		assert.Equal(t, common.BigToHash(big.NewInt(1234)).Bytes(), out.Data)
+	}
+}
+
+func TestForkSloadGas(t *testing.T) {
+	// PUSH1 0, SLOAD, POP, PUSH1 0, SLOAD, STOP
+	code := []byte{0x60, 0x00, 0x54, 0x50, 0x60, 0x00, 0x54, 0x00}
+
+	tests := []struct {
+		num uint32
+		gas uint64
+	}{
+		{0, 3 + 200 + 2 + 3 + 200},
+		{testIstanbul - 1, 3 + 200 + 2 + 3 + 200},
+		{testIstanbul, 3 + 800 + 2 + 3 + 800},
+		{testBerlin - 1, 3 + 800 + 2 + 3 + 800},
+		{testBerlin, 3 + 2100 + 2 + 3 + 100}, // cold, then warm
+	}
+	for _, test := range tests {
+		gas, out := execAt(t, test.num, code)
+		assert.Nil(t, out.VMErr)
+		assert.Equal(t, test.gas, gas, "block %v", test.num)
+	}
+}
+
+func TestForkSstoreGas(t *testing.T) {
+	// PUSH1 1, PUSH1 0, SSTORE, PUSH1 1, PUSH1 0, SSTORE, STOP
+	code := []byte{0x60, 0x01, 0x60, 0x00, 0x55, 0x60, 0x01, 0x60, 0x00, 0x55, 0x00}
+
+	tests := []struct {
+		num uint32
+		gas uint64
+	}{
+		{testIstanbul - 1, 12 + 20000 + 5000}, // set, then reset
+		{testIstanbul, 12 + 20000 + 800},      // set, then noop of EIP-2200
+		{testBerlin - 1, 12 + 20000 + 800},
+		{testBerlin, 12 + 2100 + 20000 + 100}, // cold set, then warm noop
+	}
+	for _, test := range tests {
+		gas, out := execAt(t, test.num, code)
+		assert.Nil(t, out.VMErr)
+		assert.Equal(t, test.gas, gas, "block %v", test.num)
+	}
+}
+
+func TestForkSstoreOriginalAcrossClauses(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+	st, _ := state.New(meter.Bytes32{}, kv)
+
+	forkConfig := meter.ForkConfig{Istanbul: testIstanbul, Berlin: testBerlin}
+	rt := New(nil, st, &xenv.BlockContext{Number: testIstanbul})
+	rt.forkConfig = forkConfig
+	rt.chainConfig = newChainConfig(nil, forkConfig)
+
+	// the clauses of a tx share the original values
+	originals := statedb.NewOriginals()
+	store := func(index uint32, v byte) (uint64, *Output) {
+		// PUSH1 v, PUSH1 0, SSTORE, STOP
+		st.SetCode(testContract, []byte{0x60, v, 0x60, 0x00, 0x55, 0x00})
+		exec, _ := rt.prepareClause(tx.NewClause(&testContract), index, math.MaxUint64, &xenv.TransactionContext{Origin: testOrigin}, originals)
+		out, _ := exec()
+		return math.MaxUint64 - out.LeftOverGas, out
+	}
+
+	gas, out := store(0, 1)
+	assert.Nil(t, out.VMErr)
+	assert.Equal(t, uint64(6+20000), gas, "set")
+
+	// the slot is still dirty in the second clause, reset to its original value
+	gas, out = store(1, 0)
+	assert.Nil(t, out.VMErr)
+	assert.Equal(t, uint64(6+800), gas, "reset")
+	assert.Equal(t, uint64(20000-800), out.RefundGas)
+}
+
+func TestForkAccessList(t *testing.T) {
+	// PUSH20 addr, BALANCE, STOP
+	balanceOf := func(addr meter.Address) []byte {
+		code := append([]byte{0x73}, addr.Bytes()...)
+		return append(code, 0x31, 0x00)
+	}
+	cold := meter.BytesToAddress([]byte("cold"))
+
+	tests := []struct {
+		num  uint32
+		addr meter.Address
+		gas  uint64
+	}{
+		{testIstanbul - 1, cold, 3 + 400},
+		{testIstanbul - 1, testOrigin, 3 + 400},
+		{testIstanbul, cold, 3 + 700},
+		{testIstanbul, testOrigin, 3 + 700},
+		{testBerlin, cold, 3 + 2600},
+		{testBerlin, testOrigin, 3 + 100},   // warmed up before execution
+		{testBerlin, testContract, 3 + 100}, // the destination as well
+	}
+	for _, test := range tests {
+		gas, out := execAt(t, test.num, balanceOf(test.addr))
+		assert.Nil(t, out.VMErr)
+		assert.Equal(t, test.gas, gas, "block %v, %v", test.num, test.addr)
+	}
+}
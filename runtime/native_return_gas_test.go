@@ -14,23 +14,38 @@ import (
 )
 
 func TestNativeCallReturnGas(t *testing.T) {
+	// gas = enter1 + prepare2 + enter2 + leave2 + leave1
+	// here returns prepare2
+	assert.Equal(t, uint64(1562), measureNativeCallGas(t, meter.NoFork))
+	// after berlin, the address is warm, CALL and EXTCODESIZE cost 100 each
+	assert.Equal(t, nativeCallReturnGasWarm, measureNativeCallGas(t, meter.ForkConfig{}))
+}
+
+func measureNativeCallGas(t *testing.T, forkConfig meter.ForkConfig) uint64 {
 	kv, _ := lvldb.NewMem()
 	state, _ := state.New(meter.Bytes32{}, kv)
 	state.SetCode(builtin.Measure.Address, builtin.Measure.RuntimeBytecodes())
 
+	newRuntime := func() *Runtime {
+		rt := New(nil, state, &xenv.BlockContext{})
+		rt.forkConfig = forkConfig
+		rt.chainConfig = newChainConfig(nil, forkConfig)
+		return rt
+	}
+
 	inner, _ := builtin.Measure.ABI.MethodByName("inner")
 	innerData, _ := inner.EncodeInput()
 	outer, _ := builtin.Measure.ABI.MethodByName("outer")
 	outerData, _ := outer.EncodeInput()
 
-	innerOutput := New(nil, state, &xenv.BlockContext{}).ExecuteClause(
+	innerOutput := newRuntime().ExecuteClause(
 		tx.NewClause(&builtin.Measure.Address).WithData(innerData),
 		0,
 		math.MaxUint64,
 		&xenv.TransactionContext{})
 	assert.Nil(t, innerOutput.VMErr)
 
-	outerOutput := New(nil, state, &xenv.BlockContext{}).ExecuteClause(
+	outerOutput := newRuntime().ExecuteClause(
 		tx.NewClause(&builtin.Measure.Address).WithData(outerData),
 		0,
 		math.MaxUint64,
@@ -40,7 +55,5 @@ func TestNativeCallReturnGas(t *testing.T) {
 	innerGasUsed := math.MaxUint64 - innerOutput.LeftOverGas
 	outerGasUsed := math.MaxUint64 - outerOutput.LeftOverGas
 
-	// gas = enter1 + prepare2 + enter2 + leave2 + leave1
-	// here returns prepare2
-	assert.Equal(t, uint64(1562), outerGasUsed-innerGasUsed*2)
+	return outerGasUsed - innerGasUsed*2
 }
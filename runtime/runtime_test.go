@@ -22,6 +22,7 @@ import (
 	"github.com/dfinlab/meter/tx"
 	"github.com/dfinlab/meter/xenv"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -156,6 +157,46 @@ func TestCall(t *testing.T) {
 	assert.True(t, interrupted)
 }
 
+func TestPrepareNextInterrupt(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+
+	g := genesis.NewDevnet()
+	b0, _, err := g.Build(state.NewCreator(kv))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ch, _ := chain.New(kv, b0, false)
+
+	state, _ := state.New(b0.Header().StateRoot(), kv)
+
+	rt := runtime.New(ch.NewSeeker(b0.Header().ID()), state, &xenv.BlockContext{Number: 1, Time: b0.Header().Timestamp()})
+
+	// the constructor of NeverStop, see TestCall
+	data, _ := hex.DecodeString("6080604052348015600f57600080fd5b505b600115601b576011565b60358060286000396000f3006080604052600080fd00a165627a7a7230582026c386600e61384b3a93bf45760f3207b5cac072cec31c9cea1bc7099bda49b00029")
+	trx := new(tx.Builder).
+		ChainTag(b0.Header().ID()[31]).
+		Gas(1000 * 1000 * 1000).
+		Clause(tx.NewClause(nil).WithData(data)).
+		Build()
+	sig, _ := crypto.Sign(trx.SigningHash().Bytes(), genesis.DevAccounts()[0].PrivateKey)
+	trx = trx.WithSignature(sig)
+
+	txExec, err := rt.PrepareTransaction(trx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exec, interrupt := txExec.PrepareNext()
+
+	go func() {
+		interrupt()
+	}()
+
+	_, out, err := exec()
+	assert.Nil(t, out)
+	assert.NotNil(t, err)
+}
+
 func TestExecuteTransaction(t *testing.T) {
 
 	// kv, _ := lvldb.NewMem()
@@ -25,6 +25,19 @@ var codeSizeCache, _ = lru.New(32 * 1024)
 type StateDB struct {
 	state *state.State
 	repo  *stackedmap.StackedMap
+	// originals holds storage values as they were when first touched,
+	// it is not journaled since the first observation never changes.
+	originals Originals
+}
+
+// Originals holds storage values as they were before a transaction modified
+// them (EIP-2200 original values). It is shared by the StateDBs of all clauses
+// of a transaction.
+type Originals map[storageKey]common.Hash
+
+// NewOriginals creates an empty set of original storage values.
+func NewOriginals() Originals {
+	return make(Originals)
 }
 
 type (
@@ -34,16 +47,31 @@ type (
 	eventKey       struct{}
 	transferKey    struct{}
 	stateRevKey    struct{}
+	accessAddrKey  common.Address
+	accessSlotKey  storageKey
 )
 
+type storageKey struct {
+	addr common.Address
+	key  common.Hash
+}
+
 // New create a statedb object.
 func New(state *state.State) *StateDB {
+	return NewWithOriginals(state, NewOriginals())
+}
+
+// NewWithOriginals create a statedb object which records original storage
+// values into originals, so that they are kept across clauses.
+func NewWithOriginals(state *state.State, originals Originals) *StateDB {
 	getter := func(k interface{}) (interface{}, bool) {
 		switch k.(type) {
 		case suicideFlagKey:
 			return false, true
 		case refundKey:
 			return uint64(0), true
+		case accessAddrKey, accessSlotKey:
+			return false, true
 		}
 		panic(fmt.Sprintf("unknown type of key %+v", k))
 	}
@@ -52,6 +80,7 @@ func New(state *state.State) *StateDB {
 	return &StateDB{
 		state,
 		repo,
+		originals,
 	}
 }
 
@@ -288,8 +317,21 @@ func (s *StateDB) GetState(addr common.Address, key common.Hash) common.Hash {
 	return common.Hash(s.state.GetStorage(meter.Address(addr), meter.Bytes32(key)))
 }
 
+// GetCommittedState returns the value of the storage slot before any
+// modification made in the transaction (EIP-2200 original value).
+func (s *StateDB) GetCommittedState(addr common.Address, key common.Hash) common.Hash {
+	if v, ok := s.originals[storageKey{addr, key}]; ok {
+		return v
+	}
+	return s.GetState(addr, key)
+}
+
 // SetState stub.
 func (s *StateDB) SetState(addr common.Address, key, value common.Hash) {
+	sk := storageKey{addr, key}
+	if _, ok := s.originals[sk]; !ok {
+		s.originals[sk] = s.GetState(addr, key)
+	}
 	s.state.SetStorage(meter.Address(addr), meter.Bytes32(key), meter.Bytes32(value))
 }
 
@@ -310,6 +352,39 @@ func (s *StateDB) AddRefund(gas uint64) {
 	s.repo.Put(refundKey{}, total)
 }
 
+// SubRefund removes gas from the refund counter.
+func (s *StateDB) SubRefund(gas uint64) {
+	v, _ := s.repo.Get(refundKey{})
+	if gas > v.(uint64) {
+		panic(fmt.Sprintf("refund counter below zero (gas: %d > refund: %d)", gas, v.(uint64)))
+	}
+	s.repo.Put(refundKey{}, v.(uint64)-gas)
+}
+
+// AddressInAccessList returns true if the address is warm (EIP-2929).
+func (s *StateDB) AddressInAccessList(addr common.Address) bool {
+	v, _ := s.repo.Get(accessAddrKey(addr))
+	return v.(bool)
+}
+
+// SlotInAccessList returns whether the address and the slot are warm (EIP-2929).
+func (s *StateDB) SlotInAccessList(addr common.Address, slot common.Hash) (addressOk bool, slotOk bool) {
+	v, _ := s.repo.Get(accessSlotKey(storageKey{addr, slot}))
+	return s.AddressInAccessList(addr), v.(bool)
+}
+
+// AddAddressToAccessList marks the address as warm.
+// The change is journaled and reverted together with the snapshot.
+func (s *StateDB) AddAddressToAccessList(addr common.Address) {
+	s.repo.Put(accessAddrKey(addr), true)
+}
+
+// AddSlotToAccessList marks both the address and the slot as warm.
+func (s *StateDB) AddSlotToAccessList(addr common.Address, slot common.Hash) {
+	s.repo.Put(accessAddrKey(addr), true)
+	s.repo.Put(accessSlotKey(storageKey{addr, slot}), true)
+}
+
 // AddPreimage stub.
 func (s *StateDB) AddPreimage(hash common.Hash, preimage []byte) {
 	s.repo.Put(preimageKey(hash), preimage)
@@ -245,3 +245,43 @@ func (test *snapshotTest) checkEqual(state, checkstate *statedb.StateDB) error {
 	}
 	return nil
 }
+
+func TestAccessListAndCommittedState(t *testing.T) {
+	db, _ := lvldb.NewMem()
+	state, _ := State.NewCreator(db).NewState(meter.Bytes32{})
+	stateDB := statedb.New(state)
+
+	addr := common.BytesToAddress([]byte("acct"))
+	slot := common.BytesToHash([]byte("slot"))
+	v1 := common.BytesToHash([]byte{1})
+	v2 := common.BytesToHash([]byte{2})
+
+	stateDB.SetState(addr, slot, v1)
+	if got := stateDB.GetCommittedState(addr, slot); got != (common.Hash{}) {
+		t.Errorf("committed state = %x, want empty", got)
+	}
+
+	rev := stateDB.Snapshot()
+	stateDB.AddSlotToAccessList(addr, slot)
+	stateDB.SetState(addr, slot, v2)
+	if addrOk, slotOk := stateDB.SlotInAccessList(addr, slot); !addrOk || !slotOk {
+		t.Errorf("slot should be warm")
+	}
+	stateDB.RevertToSnapshot(rev)
+
+	if stateDB.AddressInAccessList(addr) {
+		t.Errorf("access list change should be reverted")
+	}
+	if got := stateDB.GetState(addr, slot); got != v1 {
+		t.Errorf("state = %x, want %x", got, v1)
+	}
+	if got := stateDB.GetCommittedState(addr, slot); got != (common.Hash{}) {
+		t.Errorf("committed state = %x, want empty", got)
+	}
+
+	stateDB.AddRefund(10)
+	stateDB.SubRefund(4)
+	if got := stateDB.GetRefund(); got != 6 {
+		t.Errorf("refund = %d, want 6", got)
+	}
+}
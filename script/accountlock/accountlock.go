@@ -6,8 +6,6 @@
 package accountlock
 
 import (
-	"errors"
-
 	"github.com/dfinlab/meter/chain"
 	"github.com/dfinlab/meter/meter"
 	"github.com/dfinlab/meter/state"
@@ -24,6 +22,7 @@ var (
 type AccountLock struct {
 	chain        *chain.Chain
 	stateCreator *state.Creator
+	forkConfig   meter.ForkConfig
 	logger       log15.Logger
 }
 
@@ -41,6 +40,9 @@ func NewAccountLock(ch *chain.Chain, sc *state.Creator) *AccountLock {
 		stateCreator: sc,
 		logger:       log15.New("pkg", "AccountLock"),
 	}
+	if ch != nil {
+		AccountLock.forkConfig = meter.GetForkConfig(ch.GenesisBlock().Header().ID())
+	}
 	SetAccountLockGlobInst(AccountLock)
 	return AccountLock
 }
@@ -51,17 +53,18 @@ func (a *AccountLock) Start() error {
 	return nil
 }
 
-func (a *AccountLock) PrepareAccountLockHandler() (AccountLockHandler func(data []byte, to *meter.Address, txCtx *xenv.TransactionContext, gas uint64, state *state.State) (ret []byte, leftOverGas uint64, err error)) {
+func (a *AccountLock) PrepareAccountLockHandler() (AccountLockHandler func(data []byte, to *meter.Address, txCtx *xenv.TransactionContext, blockCtx *xenv.BlockContext, gas uint64, state *state.State) (ret []byte, leftOverGas uint64, op uint32, err error)) {
 
-	AccountLockHandler = func(data []byte, to *meter.Address, txCtx *xenv.TransactionContext, gas uint64, state *state.State) (ret []byte, leftOverGas uint64, err error) {
+	AccountLockHandler = func(data []byte, to *meter.Address, txCtx *xenv.TransactionContext, blockCtx *xenv.BlockContext, gas uint64, state *state.State) (ret []byte, leftOverGas uint64, op uint32, err error) {
 
 		ab, err := AccountLockDecodeFromBytes(data)
 		if err != nil {
 			log.Error("Decode script message failed", "error", err)
-			return nil, gas, err
+			return nil, gas, op, err
 		}
+		op = ab.Opcode
 
-		env := NewAccountLockEnviroment(a, state, txCtx, to)
+		env := NewAccountLockEnviroment(a, state, txCtx, blockCtx, to)
 		if env == nil {
 			panic("create AccountLock enviroment failed")
 		}
@@ -71,31 +74,40 @@ func (a *AccountLock) PrepareAccountLockHandler() (AccountLockHandler func(data
 		switch ab.Opcode {
 		case OP_ADDLOCK:
 			if env.GetTxCtx().Origin.IsZero() == false {
-				return nil, gas, errors.New("not from kblock")
+				return nil, gas, op, errNotFromKblock
 			}
 			ret, leftOverGas, err = ab.HandleAccountLockAdd(env, gas)
 
 		case OP_REMOVELOCK:
 			if env.GetTxCtx().Origin.IsZero() == false {
-				return nil, gas, errors.New("not form kblock")
+				return nil, gas, op, errNotFromKblock
 			}
 			ret, leftOverGas, err = ab.HandleAccountLockRemove(env, gas)
 
 		case OP_TRANSFER:
 			if env.GetTxCtx().Origin != ab.FromAddr {
-				return nil, gas, errors.New("from address is not the same from transaction")
+				return nil, gas, op, errFromNotOrigin
 			}
 			ret, leftOverGas, err = ab.HandleAccountLockTransfer(env, gas)
 
+		case OP_ADDTRANCHE:
+			if !env.IsForked(env.GetForkConfig().AccountLockVesting) {
+				return nil, gas, op, errUnknownOpcode
+			}
+			if env.GetTxCtx().Origin.IsZero() == false {
+				return nil, gas, op, errNotFromKblock
+			}
+			ret, leftOverGas, err = ab.HandleAccountLockAddTranche(env, gas)
+
 		case OP_GOVERNING:
 			if env.GetToAddr().String() != AccountLockAddr.String() {
-				return nil, gas, errors.New("to address is not the same from module address")
+				return nil, gas, op, errNotModuleAddress
 			}
 			ret, leftOverGas, err = ab.GoverningHandler(env, gas)
 
 		default:
 			log.Error("unknown Opcode", "Opcode", ab.Opcode)
-			return nil, gas, errors.New("unknow AccountLock opcode")
+			return nil, gas, op, errUnknownOpcode
 		}
 		log.Debug("Leaving script handler for operation", "op", ab.GetOpName(ab.Opcode))
 		return
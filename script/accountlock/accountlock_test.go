@@ -39,6 +39,8 @@ func generateAccountLockData(op, version, option, lock, release uint32, from, to
 		opStr = "remove profile"
 	case accountlock.OP_TRANSFER:
 		opStr = "transfer w/ account lock"
+	case accountlock.OP_ADDTRANCHE:
+		opStr = "add vesting tranche"
 	}
 	fmt.Println("\nGenerate data for :", opStr)
 
@@ -16,14 +16,16 @@ type AccountLockEnviroment struct {
 	AccountLock *AccountLock
 	state       *state.State
 	txCtx       *xenv.TransactionContext
+	blockCtx    *xenv.BlockContext
 	toAddr      *meter.Address
 }
 
-func NewAccountLockEnviroment(AccountLock *AccountLock, state *state.State, txCtx *xenv.TransactionContext, to *meter.Address) *AccountLockEnviroment {
+func NewAccountLockEnviroment(AccountLock *AccountLock, state *state.State, txCtx *xenv.TransactionContext, blockCtx *xenv.BlockContext, to *meter.Address) *AccountLockEnviroment {
 	return &AccountLockEnviroment{
 		AccountLock: AccountLock,
 		state:       state,
 		txCtx:       txCtx,
+		blockCtx:    blockCtx,
 		toAddr:      to,
 	}
 }
@@ -32,3 +34,11 @@ func (env *AccountLockEnviroment) GetAccountLock() *AccountLock       { return e
 func (env *AccountLockEnviroment) GetState() *state.State             { return env.state }
 func (env *AccountLockEnviroment) GetTxCtx() *xenv.TransactionContext { return env.txCtx }
 func (env *AccountLockEnviroment) GetToAddr() *meter.Address          { return env.toAddr }
+func (env *AccountLockEnviroment) GetBlockCtx() *xenv.BlockContext    { return env.blockCtx }
+
+func (env *AccountLockEnviroment) GetForkConfig() meter.ForkConfig { return env.AccountLock.forkConfig }
+
+// IsForked tells if the fork at height is active in the block being executed.
+func (env *AccountLockEnviroment) IsForked(height uint32) bool {
+	return env.blockCtx.Number >= height
+}
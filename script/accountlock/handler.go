@@ -6,11 +6,11 @@
 package accountlock
 
 import (
-	"errors"
 	"fmt"
 	"math/big"
 
 	"github.com/dfinlab/meter/meter"
+	"github.com/dfinlab/meter/script/scripterr"
 	"github.com/ethereum/go-ethereum/rlp"
 )
 
@@ -18,6 +18,7 @@ const (
 	OP_ADDLOCK    = uint32(1)
 	OP_REMOVELOCK = uint32(2)
 	OP_TRANSFER   = uint32(3)
+	OP_ADDTRANCHE = uint32(4)
 	OP_GOVERNING  = uint32(100)
 
 	TOKEN_METER     = byte(0)
@@ -28,6 +29,26 @@ var (
 	MinimumTransferAmount = new(big.Int).Mul(big.NewInt(10), big.NewInt(1e18))
 )
 
+var (
+	// Errors of accountlock, codes are stable and must not be reused
+	Errors = scripterr.NewRegistry()
+
+	errProfileExist         = Errors.New(1, "profile is already in state")
+	errProfileNotExist      = Errors.New(2, "profile is no in state")
+	errToProfileExist       = Errors.New(3, "profile of ToAddr is already in state")
+	errFromProfileExist     = Errors.New(4, "profile of FromAddr is already in state")
+	errNotEnoughMeter       = Errors.New(5, "not enough meter balance")
+	errNotEnoughMeterGov    = Errors.New(6, "not enough meter-gov balance")
+	errInvalidTrancheEpoch  = Errors.New(7, "invalid tranche epochs (start <= cliff <= end)")
+	errInvalidTrancheAmount = Errors.New(8, "invalid tranche amount")
+
+	// tx sender
+	errNotFromKblock    = Errors.New(20, "not from kblock")
+	errFromNotOrigin    = Errors.New(21, "from address is not the same from transaction")
+	errNotModuleAddress = Errors.New(22, "to address is not the same from module address")
+	errUnknownOpcode    = Errors.New(23, "unknow AccountLock opcode")
+)
+
 // Candidate indicates the structure of a candidate
 type AccountLockBody struct {
 	Opcode         uint32
@@ -55,6 +76,8 @@ func (a *AccountLockBody) GetOpName(op uint32) string {
 		return "removelock"
 	case OP_TRANSFER:
 		return "transfer"
+	case OP_ADDTRANCHE:
+		return "addtranche"
 	case OP_GOVERNING:
 		return "governing"
 	default:
@@ -94,7 +117,7 @@ func (ab *AccountLockBody) HandleAccountLockAdd(env *AccountLockEnviroment, gas
 	}
 
 	if p := pList.Get(ab.FromAddr); p != nil {
-		err = errors.New("profile is already in state")
+		err = errProfileExist
 		log.Error("profile is already in state", "addr", ab.FromAddr)
 		return
 	}
@@ -124,14 +147,84 @@ func (ab *AccountLockBody) HandleAccountLockRemove(env *AccountLockEnviroment, g
 
 	p := pList.Get(ab.FromAddr)
 	if p == nil {
-		err = errors.New("profile is no in state")
+		err = errProfileNotExist
 		log.Error("profile is not in state", "addr", ab.FromAddr)
 		return
 	}
 
 	pList.Remove(ab.FromAddr)
+	AccountLock.SetProfileList(pList, state)
+
+	// schedules only exist since the vesting fork
+	if env.IsForked(env.GetForkConfig().AccountLockVesting) {
+		sList := AccountLock.GetScheduleList(state)
+		sList.Remove(ab.FromAddr)
+		AccountLock.SetScheduleList(sList, state)
+	}
+	return
+}
+
+// HandleAccountLockAddTranche adds a vesting tranche to the profile of FromAddr,
+// LockEpoch is the start, Option the cliff and ReleaseEpoch the end of the tranche.
+// A profile locked until its ReleaseEpoch is converted to a tranche first.
+func (ab *AccountLockBody) HandleAccountLockAddTranche(env *AccountLockEnviroment, gas uint64) (ret []byte, leftOverGas uint64, err error) {
+	defer func() {
+		if err != nil {
+			ret = []byte(err.Error())
+		}
+	}()
+	AccountLock := env.GetAccountLock()
+	state := env.GetState()
+	pList := AccountLock.GetProfileList(state)
+	sList := AccountLock.GetScheduleList(state)
+
+	if gas < meter.ClauseGas {
+		leftOverGas = 0
+	} else {
+		leftOverGas = gas - meter.ClauseGas
+	}
+
+	tranche, err := NewTranche(ab.LockEpoch, ab.Option, ab.ReleaseEpoch, ab.MeterAmount, ab.MeterGovAmount)
+	if err != nil {
+		log.Error("invalid tranche", "addr", ab.FromAddr, "error", err)
+		return
+	}
+
+	p := pList.Get(ab.FromAddr)
+	s := sList.Get(ab.FromAddr)
+	if p == nil {
+		p = NewProfile(ab.FromAddr, ab.Memo, ab.LockEpoch, ab.ReleaseEpoch, big.NewInt(0), big.NewInt(0))
+		pList.Add(p)
+	} else if s == nil {
+		s = NewSchedule(ab.FromAddr)
+		if p.MeterAmount.Sign() != 0 || p.MeterGovAmount.Sign() != 0 {
+			s.Tranches = append(s.Tranches, &Tranche{
+				StartEpoch:     p.LockEpoch,
+				CliffEpoch:     p.ReleaseEpoch,
+				EndEpoch:       p.ReleaseEpoch,
+				MeterAmount:    new(big.Int).Set(p.MeterAmount),
+				MeterGovAmount: new(big.Int).Set(p.MeterGovAmount),
+			})
+		}
+	}
+	if s == nil {
+		s = NewSchedule(ab.FromAddr)
+	}
+	s.Tranches = append(s.Tranches, tranche)
+	sList.Add(s)
+
+	p.MeterAmount = new(big.Int).Add(p.MeterAmount, ab.MeterAmount)
+	p.MeterGovAmount = new(big.Int).Add(p.MeterGovAmount, ab.MeterGovAmount)
+	if ab.LockEpoch < p.LockEpoch {
+		p.LockEpoch = ab.LockEpoch
+	}
+	if ab.ReleaseEpoch > p.ReleaseEpoch {
+		p.ReleaseEpoch = ab.ReleaseEpoch
+	}
 
+	log.Debug("account lock tranche added", "addr", ab.FromAddr, "tranche", tranche.ToString())
 	AccountLock.SetProfileList(pList, state)
+	AccountLock.SetScheduleList(sList, state)
 	return
 }
 
@@ -153,7 +246,7 @@ func (ab *AccountLockBody) HandleAccountLockTransfer(env *AccountLockEnviroment,
 
 	// can not transfer to address which already has account lock
 	if pTo := pList.Get(ab.ToAddr); pTo != nil {
-		err = errors.New("profile of ToAddr is already in state")
+		err = errToProfileExist
 		log.Error("profile is already in state", "addr", ab.ToAddr)
 		return
 	}
@@ -162,7 +255,7 @@ func (ab *AccountLockBody) HandleAccountLockTransfer(env *AccountLockEnviroment,
 	pFrom := pList.Get(ab.FromAddr)
 	if pFrom != nil {
 		if AccountLock.IsExclusiveAccount(ab.FromAddr, state) == false {
-			err = errors.New("profile of FromAddr is already in state")
+			err = errFromProfileExist
 			log.Error("profile is already in state", "addr", ab.FromAddr)
 			return
 		}
@@ -172,13 +265,13 @@ func (ab *AccountLockBody) HandleAccountLockTransfer(env *AccountLockEnviroment,
 	// check have enough balance
 	if ab.MeterAmount.Sign() != 0 {
 		if state.GetEnergy(ab.FromAddr).Cmp(ab.MeterAmount) < 0 {
-			err = errors.New("not enough meter balance")
+			err = errNotEnoughMeter
 			return
 		}
 	}
 	if ab.MeterGovAmount.Sign() != 0 {
 		if state.GetBalance(ab.FromAddr).Cmp(ab.MeterGovAmount) < 0 {
-			err = errors.New("not enough meter-gov balance")
+			err = errNotEnoughMeterGov
 			return
 		}
 	}
@@ -243,5 +336,14 @@ func (ab *AccountLockBody) GoverningHandler(env *AccountLockEnviroment, gas uint
 
 	log.Debug("account lock governing done...", "epoch", curEpoch)
 	AccountLock.SetProfileList(pList, state)
+
+	// schedules only exist since the vesting fork
+	if env.IsForked(env.GetForkConfig().AccountLockVesting) {
+		sList := AccountLock.GetScheduleList(state)
+		for _, r := range toRemove {
+			sList.Remove(r)
+		}
+		AccountLock.SetScheduleList(sList, state)
+	}
 	return
 }
@@ -163,6 +163,10 @@ func GetLatestProfileList() (*ProfileList, error) {
 	return list, nil
 }
 
+// RestrictByAccountLock tells if addr has locked balance, and returns the locked
+// meter and meterGov. Only the unvested part of the profile is locked, the vested
+// part can be transferred. A profile without schedule, which is the only kind
+// before the vesting fork, is fully locked until its ReleaseEpoch.
 func RestrictByAccountLock(addr meter.Address, state *state.State) (bool, *big.Int, *big.Int) {
 	accountlock := GetAccountLockGlobInst()
 	if accountlock == nil {
@@ -181,11 +185,13 @@ func RestrictByAccountLock(addr meter.Address, state *state.State) (bool, *big.I
 		return false, nil, nil
 	}
 
-	if accountlock.GetCurrentEpoch() >= p.ReleaseEpoch {
+	// only the unvested part is restricted
+	mtr, mtrg := Unvested(p, accountlock.GetScheduleList(state).Get(addr), accountlock.GetCurrentEpoch())
+	if mtr.Sign() == 0 && mtrg.Sign() == 0 {
 		return false, nil, nil
 	}
 
 	log.Debug("the Address is not allowed to do transfer", "address", addr,
-		"meter", p.MeterAmount.String(), "meterGov", p.MeterGovAmount.String())
-	return true, p.MeterAmount, p.MeterGovAmount
+		"meter", mtr.String(), "meterGov", mtrg.String())
+	return true, mtr, mtrg
 }
@@ -19,6 +19,8 @@ var (
 	//0x6163636f756e742d6c6f636b2d61646472657373
 	AccountLockAddr       = meter.BytesToAddress([]byte("account-lock-address"))
 	AccountLockProfileKey = meter.Blake2b([]byte("account-lock-profile-list-key"))
+	// vesting schedules are stored aside from the profiles
+	AccountLockScheduleKey = meter.Blake2b([]byte("account-lock-schedule-list-key"))
 )
 
 // Candidate List
@@ -54,3 +56,36 @@ func (a *AccountLock) SetProfileList(lockList *ProfileList, state *state.State)
 		return rlp.EncodeToBytes(lockList.Profiles)
 	})
 }
+
+// Schedule List
+func (a *AccountLock) GetScheduleList(state *state.State) (result *ScheduleList) {
+	state.DecodeStorage(AccountLockAddr, AccountLockScheduleKey, func(raw []byte) error {
+		schedules := make([]*Schedule, 0)
+
+		if len(strings.TrimSpace(string(raw))) >= 0 {
+			err := rlp.Decode(bytes.NewReader(raw), &schedules)
+			if err != nil {
+				if err.Error() == "EOF" && len(raw) == 0 {
+					// EOF is caused by no value, is not error case, so returns with empty slice
+				} else {
+					log.Warn("Error during decoding schedule list", "err", err)
+					return err
+				}
+			}
+		}
+
+		result = NewScheduleList(schedules)
+		return nil
+	})
+	return
+}
+
+func (a *AccountLock) SetScheduleList(scheduleList *ScheduleList, state *state.State) {
+	state.EncodeStorage(AccountLockAddr, AccountLockScheduleKey, func() ([]byte, error) {
+		// clear the slot once the last schedule is removed
+		if len(scheduleList.Schedules) == 0 {
+			return nil, nil
+		}
+		return rlp.EncodeToBytes(scheduleList.Schedules)
+	})
+}
@@ -0,0 +1,202 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package accountlock
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/dfinlab/meter/meter"
+)
+
+// Tranche vests its amounts linearly per epoch from StartEpoch to EndEpoch.
+// Nothing vests before CliffEpoch, the part accrued by then vests at the cliff.
+type Tranche struct {
+	StartEpoch     uint32
+	CliffEpoch     uint32
+	EndEpoch       uint32
+	MeterAmount    *big.Int
+	MeterGovAmount *big.Int
+}
+
+func NewTranche(start, cliff, end uint32, mtr *big.Int, mtrg *big.Int) (*Tranche, error) {
+	if start > cliff || cliff > end {
+		return nil, errInvalidTrancheEpoch
+	}
+	if mtr.Sign() < 0 || mtrg.Sign() < 0 || (mtr.Sign() == 0 && mtrg.Sign() == 0) {
+		return nil, errInvalidTrancheAmount
+	}
+	return &Tranche{
+		StartEpoch:     start,
+		CliffEpoch:     cliff,
+		EndEpoch:       end,
+		MeterAmount:    new(big.Int).Set(mtr),
+		MeterGovAmount: new(big.Int).Set(mtrg),
+	}, nil
+}
+
+func (t *Tranche) ToString() string {
+	return fmt.Sprintf("Tranche(Start=%v, Cliff=%v, End=%v, MeterAmount=%v, MeterGovAmount=%v)",
+		t.StartEpoch, t.CliffEpoch, t.EndEpoch, t.MeterAmount.String(), t.MeterGovAmount.String())
+}
+
+func (t *Tranche) vested(amount *big.Int, epoch uint32) *big.Int {
+	if epoch < t.CliffEpoch {
+		return big.NewInt(0)
+	}
+	if epoch >= t.EndEpoch {
+		return new(big.Int).Set(amount)
+	}
+	v := new(big.Int).Mul(amount, big.NewInt(int64(epoch-t.StartEpoch)))
+	return v.Div(v, big.NewInt(int64(t.EndEpoch-t.StartEpoch)))
+}
+
+// Vested returns the amounts vested at epoch.
+func (t *Tranche) Vested(epoch uint32) (*big.Int, *big.Int) {
+	return t.vested(t.MeterAmount, epoch), t.vested(t.MeterGovAmount, epoch)
+}
+
+// Schedule is the vesting schedule of an account lock profile. Profiles without
+// schedule release everything at ReleaseEpoch.
+type Schedule struct {
+	Addr     meter.Address
+	Tranches []*Tranche
+}
+
+func NewSchedule(addr meter.Address) *Schedule {
+	return &Schedule{Addr: addr, Tranches: []*Tranche{}}
+}
+
+func (s *Schedule) ToString() string {
+	t := make([]string, 0, len(s.Tranches))
+	for _, tr := range s.Tranches {
+		t = append(t, tr.ToString())
+	}
+	return fmt.Sprintf("Schedule(%v) Tranches=[%v]", s.Addr, strings.Join(t, ", "))
+}
+
+// Vested returns the amounts vested at epoch of all tranches.
+func (s *Schedule) Vested(epoch uint32) (*big.Int, *big.Int) {
+	mtr, mtrg := big.NewInt(0), big.NewInt(0)
+	for _, t := range s.Tranches {
+		m, g := t.Vested(epoch)
+		mtr.Add(mtr, m)
+		mtrg.Add(mtrg, g)
+	}
+	return mtr, mtrg
+}
+
+// Unvested returns the amounts still locked at epoch. The legacy profile without
+// schedule is locked entirely until ReleaseEpoch.
+func Unvested(p *Profile, s *Schedule, epoch uint32) (*big.Int, *big.Int) {
+	if s == nil {
+		if epoch >= p.ReleaseEpoch {
+			return big.NewInt(0), big.NewInt(0)
+		}
+		return new(big.Int).Set(p.MeterAmount), new(big.Int).Set(p.MeterGovAmount)
+	}
+	mtr, mtrg := s.Vested(epoch)
+	mtr.Sub(p.MeterAmount, mtr)
+	mtrg.Sub(p.MeterGovAmount, mtrg)
+	if mtr.Sign() < 0 {
+		mtr.SetUint64(0)
+	}
+	if mtrg.Sign() < 0 {
+		mtrg.SetUint64(0)
+	}
+	return mtr, mtrg
+}
+
+type ScheduleList struct {
+	Schedules []*Schedule
+}
+
+func NewScheduleList(schedules []*Schedule) *ScheduleList {
+	if schedules == nil {
+		schedules = make([]*Schedule, 0)
+	}
+	sort.SliceStable(schedules, func(i, j int) bool {
+		return bytes.Compare(schedules[i].Addr.Bytes(), schedules[j].Addr.Bytes()) <= 0
+	})
+	return &ScheduleList{Schedules: schedules}
+}
+
+func (sl *ScheduleList) indexOf(addr meter.Address) (int, int) {
+	// return values:
+	//     first parameter: if found, the index of the item
+	//     second parameter: if not found, the correct insert index of the item
+	if len(sl.Schedules) <= 0 {
+		return -1, 0
+	}
+	l := 0
+	r := len(sl.Schedules)
+	for l < r {
+		m := (l + r) / 2
+		cmp := bytes.Compare(addr.Bytes(), sl.Schedules[m].Addr.Bytes())
+		if cmp < 0 {
+			r = m
+		} else if cmp > 0 {
+			l = m + 1
+		} else {
+			return m, -1
+		}
+	}
+	return -1, r
+}
+
+func (sl *ScheduleList) Get(addr meter.Address) *Schedule {
+	index, _ := sl.indexOf(addr)
+	if index < 0 {
+		return nil
+	}
+	return sl.Schedules[index]
+}
+
+func (sl *ScheduleList) Add(s *Schedule) {
+	index, insertIndex := sl.indexOf(s.Addr)
+	if index >= 0 {
+		sl.Schedules[index] = s
+		return
+	}
+	newList := make([]*Schedule, insertIndex)
+	copy(newList, sl.Schedules[:insertIndex])
+	newList = append(newList, s)
+	newList = append(newList, sl.Schedules[insertIndex:]...)
+	sl.Schedules = newList
+}
+
+func (sl *ScheduleList) Remove(addr meter.Address) {
+	index, _ := sl.indexOf(addr)
+	if index >= 0 {
+		sl.Schedules = append(sl.Schedules[:index], sl.Schedules[index+1:]...)
+	}
+}
+
+func (sl *ScheduleList) Count() int {
+	return len(sl.Schedules)
+}
+
+// api routine interface
+func GetLatestScheduleList() (*ScheduleList, uint32, error) {
+	accountlock := GetAccountLockGlobInst()
+	if accountlock == nil {
+		log.Warn("accountlock is not initialized...")
+		err := errors.New("accountlock is not initialized...")
+		return NewScheduleList(nil), 0, err
+	}
+
+	best := accountlock.chain.BestBlock()
+	state, err := accountlock.stateCreator.NewState(best.Header().StateRoot())
+	if err != nil {
+		return NewScheduleList(nil), 0, err
+	}
+
+	return accountlock.GetScheduleList(state), uint32(best.GetBlockEpoch()), nil
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package accountlock_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/dfinlab/meter/lvldb"
+	"github.com/dfinlab/meter/meter"
+	"github.com/dfinlab/meter/script/accountlock"
+	"github.com/dfinlab/meter/state"
+	"github.com/dfinlab/meter/xenv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrancheVested(t *testing.T) {
+	_, err := accountlock.NewTranche(10, 5, 20, big.NewInt(100), big.NewInt(0))
+	assert.NotNil(t, err, "cliff before start")
+
+	tr, err := accountlock.NewTranche(10, 15, 20, big.NewInt(100), big.NewInt(1000))
+	assert.Nil(t, err)
+	for _, c := range []struct {
+		epoch     uint32
+		mtr, mtrg int64
+	}{{0, 0, 0}, {14, 0, 0}, {15, 50, 500}, {17, 70, 700}, {20, 100, 1000}, {100, 100, 1000}} {
+		mtr, mtrg := tr.Vested(c.epoch)
+		assert.Equal(t, c.mtr, mtr.Int64(), "epoch %v", c.epoch)
+		assert.Equal(t, c.mtrg, mtrg.Int64(), "epoch %v", c.epoch)
+	}
+
+	p := accountlock.NewProfile(meter.Address{}, nil, 0, 20, big.NewInt(100), big.NewInt(1000))
+	mtr, mtrg := accountlock.Unvested(p, nil, 19)
+	assert.Equal(t, int64(1000), mtrg.Int64(), "legacy lock")
+	mtr, mtrg = accountlock.Unvested(p, nil, 20)
+	assert.Equal(t, 0, mtr.Sign()+mtrg.Sign())
+}
+
+func TestAddTranche(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+	st, _ := state.New(meter.Bytes32{}, kv)
+	a := accountlock.NewAccountLock(nil, nil)
+	env := accountlock.NewAccountLockEnviroment(a, st, &xenv.TransactionContext{}, &xenv.BlockContext{}, nil)
+	addr := meter.MustParseAddress(FROM_ADDRESS)
+
+	add := &accountlock.AccountLockBody{Opcode: accountlock.OP_ADDLOCK, FromAddr: addr, LockEpoch: 0, ReleaseEpoch: 10,
+		MeterAmount: big.NewInt(0), MeterGovAmount: big.NewInt(100)}
+	_, _, err := add.HandleAccountLockAdd(env, meter.ClauseGas)
+	assert.Nil(t, err)
+
+	tranche := &accountlock.AccountLockBody{Opcode: accountlock.OP_ADDTRANCHE, FromAddr: addr, LockEpoch: 10, Option: 20, ReleaseEpoch: 30,
+		MeterAmount: big.NewInt(0), MeterGovAmount: big.NewInt(200)}
+	_, _, err = tranche.HandleAccountLockAddTranche(env, meter.ClauseGas)
+	assert.Nil(t, err)
+
+	p := a.GetProfileList(st).Get(addr)
+	assert.Equal(t, int64(300), p.MeterGovAmount.Int64())
+	assert.Equal(t, uint32(30), p.ReleaseEpoch)
+
+	s := a.GetScheduleList(st).Get(addr)
+	assert.Equal(t, 2, len(s.Tranches), "legacy lock converted")
+	for _, c := range []struct {
+		epoch uint32
+		mtrg  int64
+	}{{9, 300}, {10, 200}, {19, 200}, {20, 100}, {25, 50}, {30, 0}} {
+		_, mtrg := accountlock.Unvested(p, s, c.epoch)
+		assert.Equal(t, c.mtrg, mtrg.Int64(), "epoch %v", c.epoch)
+	}
+
+	remove := &accountlock.AccountLockBody{Opcode: accountlock.OP_REMOVELOCK, FromAddr: addr}
+	_, _, err = remove.HandleAccountLockRemove(env, meter.ClauseGas)
+	assert.Nil(t, err)
+	assert.Nil(t, a.GetScheduleList(st).Get(addr))
+}
@@ -6,8 +6,6 @@
 package auction
 
 import (
-	"errors"
-
 	"github.com/dfinlab/meter/chain"
 	"github.com/dfinlab/meter/meter"
 	"github.com/dfinlab/meter/state"
@@ -29,6 +27,7 @@ var (
 type Auction struct {
 	chain        *chain.Chain
 	stateCreator *state.Creator
+	forkConfig   meter.ForkConfig
 	logger       log15.Logger
 }
 
@@ -46,6 +45,9 @@ func NewAuction(ch *chain.Chain, sc *state.Creator) *Auction {
 		stateCreator: sc,
 		logger:       log15.New("pkg", "auction"),
 	}
+	if ch != nil {
+		auction.forkConfig = meter.GetForkConfig(ch.GenesisBlock().Header().ID())
+	}
 	SetAuctionGlobInst(auction)
 	return auction
 }
@@ -55,17 +57,18 @@ func (a *Auction) Start() error {
 	return nil
 }
 
-func (a *Auction) PrepareAuctionHandler() (AuctionHandler func(data []byte, to *meter.Address, txCtx *xenv.TransactionContext, gas uint64, state *state.State) (ret []byte, leftOverGas uint64, err error)) {
+func (a *Auction) PrepareAuctionHandler() (AuctionHandler func(data []byte, to *meter.Address, txCtx *xenv.TransactionContext, blockCtx *xenv.BlockContext, gas uint64, state *state.State) (ret []byte, leftOverGas uint64, op uint32, err error)) {
 
-	AuctionHandler = func(data []byte, to *meter.Address, txCtx *xenv.TransactionContext, gas uint64, state *state.State) (ret []byte, leftOverGas uint64, err error) {
+	AuctionHandler = func(data []byte, to *meter.Address, txCtx *xenv.TransactionContext, blockCtx *xenv.BlockContext, gas uint64, state *state.State) (ret []byte, leftOverGas uint64, op uint32, err error) {
 
 		ab, err := AuctionDecodeFromBytes(data)
 		if err != nil {
 			log.Error("Decode script message failed", "error", err)
-			return nil, gas, err
+			return nil, gas, op, err
 		}
+		op = ab.Opcode
 
-		env := NewAuctionEnviroment(a, state, txCtx, to)
+		env := NewAuctionEnviroment(a, state, txCtx, blockCtx, to)
 		if env == nil {
 			panic("create auction enviroment failed")
 		}
@@ -75,25 +78,34 @@ func (a *Auction) PrepareAuctionHandler() (AuctionHandler func(data []byte, to *
 		switch ab.Opcode {
 		case OP_START:
 			if env.GetTxCtx().Origin.IsZero() == false {
-				return nil, gas, errors.New("not from kblock")
+				return nil, gas, op, errNotFromKblock
 			}
 			ret, leftOverGas, err = ab.StartAuctionCB(env, gas)
 
 		case OP_STOP:
 			if env.GetTxCtx().Origin.IsZero() == false {
-				return nil, gas, errors.New("not form kblock")
+				return nil, gas, op, errNotFromKblock
 			}
 			ret, leftOverGas, err = ab.CloseAuctionCB(env, gas)
 
 		case OP_BID:
 			if env.GetTxCtx().Origin != ab.Bidder {
-				return nil, gas, errors.New("bidder address is not the same from transaction")
+				return nil, gas, op, errBidderNotOrigin
 			}
 			ret, leftOverGas, err = ab.HandleAuctionTx(env, gas)
 
+		case OP_CANCEL:
+			if !env.IsForked(env.GetForkConfig().AuctionBids) {
+				return nil, gas, op, errUnknownOpcode
+			}
+			if env.GetTxCtx().Origin != ab.Bidder {
+				return nil, gas, op, errBidderNotOrigin
+			}
+			ret, leftOverGas, err = ab.CancelAuctionTx(env, gas)
+
 		default:
 			log.Error("unknown Opcode", "Opcode", ab.Opcode)
-			return nil, gas, errors.New("unknow auction opcode")
+			return nil, gas, op, errUnknownOpcode
 		}
 		log.Debug("Leaving script handler for operation", "op", ab.GetOpName(ab.Opcode))
 		return
@@ -7,6 +7,7 @@ package auction
 
 import (
 	"bytes"
+	"encoding/binary"
 	"errors"
 	"math/big"
 	"strings"
@@ -26,6 +27,12 @@ var (
 	AuctionAccountAddr = meter.BytesToAddress([]byte("auction-account-address"))
 	SummaryListKey     = meter.Blake2b([]byte("summary-list-key"))
 	AuctionCBKey       = meter.Blake2b([]byte("auction-active-cb-key"))
+	BidListKeyPrefix   = []byte("auction-bid-list-key")
+
+	// summaries are archived individually since the summary list is retired
+	SummaryKeyPrefix      = []byte("auction-summary-key")
+	SummaryIndexKeyPrefix = []byte("auction-summary-index-key")
+	SummaryCountKey       = meter.Blake2b([]byte("auction-summary-count-key"))
 )
 
 // Candidate List
@@ -91,6 +98,110 @@ func (a *Auction) SetSummaryList(summaryList *AuctionSummaryList, state *state.S
 	})
 }
 
+// summary archive
+func summaryKey(index uint64) meter.Bytes32 {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], index)
+	return meter.Blake2b(SummaryKeyPrefix, b[:])
+}
+
+func summaryIndexKey(id meter.Bytes32) meter.Bytes32 {
+	return meter.Blake2b(SummaryIndexKeyPrefix, id.Bytes())
+}
+
+func (a *Auction) getArchivedCount(state *state.State) (count uint64) {
+	state.DecodeStorage(AuctionAccountAddr, SummaryCountKey, func(raw []byte) error {
+		if len(raw) == 0 {
+			return nil
+		}
+		return rlp.DecodeBytes(raw, &count)
+	})
+	return
+}
+
+func (a *Auction) setArchivedCount(count uint64, state *state.State) {
+	state.EncodeStorage(AuctionAccountAddr, SummaryCountKey, func() ([]byte, error) {
+		return rlp.EncodeToBytes(count)
+	})
+}
+
+// getArchivedSummary returns nil if nothing archived at index.
+func (a *Auction) getArchivedSummary(index uint64, state *state.State) (result *AuctionSummary) {
+	state.DecodeStorage(AuctionAccountAddr, summaryKey(index), func(raw []byte) error {
+		if len(raw) == 0 {
+			return nil
+		}
+		summary := &AuctionSummary{}
+		if err := rlp.DecodeBytes(raw, summary); err != nil {
+			log.Warn("Error during decoding auction summary", "index", index, "err", err)
+			return err
+		}
+		result = summary
+		return nil
+	})
+	return
+}
+
+func (a *Auction) setArchivedSummary(index uint64, summary *AuctionSummary, state *state.State) {
+	state.EncodeStorage(AuctionAccountAddr, summaryKey(index), func() ([]byte, error) {
+		return rlp.EncodeToBytes(summary)
+	})
+	// index is stored plus one, zero means not archived
+	state.EncodeStorage(AuctionAccountAddr, summaryIndexKey(summary.AuctionID), func() ([]byte, error) {
+		return rlp.EncodeToBytes(index + 1)
+	})
+}
+
+func (a *Auction) getArchivedIndex(id meter.Bytes32, state *state.State) (index uint64, ok bool) {
+	state.DecodeStorage(AuctionAccountAddr, summaryIndexKey(id), func(raw []byte) error {
+		if len(raw) == 0 {
+			return nil
+		}
+		var v uint64
+		if err := rlp.DecodeBytes(raw, &v); err != nil {
+			return err
+		}
+		if v > 0 {
+			index, ok = v-1, true
+		}
+		return nil
+	})
+	return
+}
+
+// bid list of address
+func bidListKey(addr meter.Address) meter.Bytes32 {
+	return meter.Blake2b(BidListKeyPrefix, addr.Bytes())
+}
+
+func (a *Auction) GetBidList(addr meter.Address, state *state.State) (result *AuctionBidList) {
+	state.DecodeStorage(AuctionAccountAddr, bidListKey(addr), func(raw []byte) error {
+		bids := make([]*AuctionBid, 0)
+
+		if len(strings.TrimSpace(string(raw))) >= 0 {
+			err := rlp.Decode(bytes.NewReader(raw), &bids)
+			if err != nil {
+				if err.Error() == "EOF" && len(raw) == 0 {
+					// EOF is caused by no value, is not error case, so returns with empty slice
+				} else {
+					log.Warn("Error during decoding auction bid list", "err", err)
+					return err
+				}
+			}
+		}
+
+		result = NewAuctionBidList(bids)
+		return nil
+	})
+	return
+}
+
+func (a *Auction) SetBidList(addr meter.Address, bidList *AuctionBidList, state *state.State) {
+	state.EncodeStorage(AuctionAccountAddr, bidListKey(addr), func() ([]byte, error) {
+		return rlp.EncodeToBytes(bidList.Bids)
+	})
+}
+
 //==================== account openation===========================
 // from addr == > AuctionAccountAddr
 func (a *Auction) TransferMTRToAuction(addr meter.Address, amount *big.Int, state *state.State) error {
@@ -108,6 +219,22 @@ func (a *Auction) TransferMTRToAuction(addr meter.Address, amount *big.Int, stat
 	return nil
 }
 
+// from AuctionAccountAddr ==> addr, the inverse of TransferMTRToAuction
+func (a *Auction) TransferMTRToBidder(addr meter.Address, amount *big.Int, state *state.State) error {
+	if amount.Sign() == 0 {
+		return nil
+	}
+
+	meterBalance := state.GetEnergy(AuctionAccountAddr)
+	if meterBalance.Cmp(amount) < 0 {
+		return errors.New("not enough meter")
+	}
+
+	state.AddEnergy(addr, amount)
+	state.SubEnergy(AuctionAccountAddr, amount)
+	return nil
+}
+
 func (a *Auction) SendMTRGToBidder(addr meter.Address, amount *big.Int, stateDB *statedb.StateDB) {
 	if amount.Sign() == 0 {
 		return
@@ -0,0 +1,125 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package auction
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/dfinlab/meter/builtin"
+	"github.com/dfinlab/meter/meter"
+	"github.com/dfinlab/meter/state"
+)
+
+const (
+	// bids kept in the history of each address, the oldest are dropped
+	AUCTION_MAX_BIDS_PER_ADDRESS = 512
+	// bids can be cancelled within this window after the auction starts, so
+	// cancellations stop well before CloseAuctionCB. Governed by
+	// meter.KeyAuctionCancelWindow
+	AUCTION_CANCEL_WINDOW = uint64(60 * 60 * 12) // 12 hours
+)
+
+// AuctionBid is a single bid. AuctionTx in the control block sums all bids of
+// an address, the individual ones are kept in the history of the address.
+type AuctionBid struct {
+	AuctionID meter.Bytes32
+	Addr      meter.Address
+	Amount    *big.Int
+	Nonce     uint64
+	Timestamp uint64
+	Cancelled bool
+}
+
+func (b *AuctionBid) ToString() string {
+	return fmt.Sprintf("AuctionBid(auctionID=%v, addr=%v, amount=%v, nonce=%v, timestamp=%v, cancelled=%v)",
+		b.AuctionID.AbbrevString(), b.Addr, b.Amount.String(), b.Nonce, fmt.Sprintln(time.Unix(int64(b.Timestamp), 0)), b.Cancelled)
+}
+
+// AuctionBidList is the bid history of an address, in the order of bidding.
+type AuctionBidList struct {
+	Bids []*AuctionBid
+}
+
+func NewAuctionBidList(bids []*AuctionBid) *AuctionBidList {
+	if bids == nil {
+		bids = make([]*AuctionBid, 0)
+	}
+	return &AuctionBidList{Bids: bids}
+}
+
+// Add appends the bid, the oldest one is dropped if the history is full.
+func (l *AuctionBidList) Add(bid *AuctionBid) {
+	if len(l.Bids) >= AUCTION_MAX_BIDS_PER_ADDRESS {
+		l.Bids = l.Bids[len(l.Bids)-AUCTION_MAX_BIDS_PER_ADDRESS+1:]
+	}
+	l.Bids = append(l.Bids, bid)
+}
+
+// GetActive returns the latest bid with nonce in the auction that is not cancelled.
+func (l *AuctionBidList) GetActive(auctionID meter.Bytes32, nonce uint64) *AuctionBid {
+	for i := len(l.Bids) - 1; i >= 0; i-- {
+		b := l.Bids[i]
+		if b.AuctionID == auctionID && b.Nonce == nonce && b.Cancelled == false {
+			return b
+		}
+	}
+	return nil
+}
+
+func (l *AuctionBidList) Count() int {
+	return len(l.Bids)
+}
+
+func (l *AuctionBidList) ToString() string {
+	if l == nil || len(l.Bids) == 0 {
+		return "AuctionBidList (size:0)"
+	}
+	s := []string{fmt.Sprintf("AuctionBidList (size:%v) {", len(l.Bids))}
+	for i, b := range l.Bids {
+		s = append(s, fmt.Sprintf("  %d.%v", i, b.ToString()))
+	}
+	s = append(s, "}")
+	return strings.Join(s, "\n")
+}
+
+func (l *AuctionBidList) ToList() []AuctionBid {
+	result := make([]AuctionBid, 0)
+	for _, v := range l.Bids {
+		result = append(result, *v)
+	}
+	return result
+}
+
+// GetCancelWindow returns the governed cancel window, the default if unset.
+func GetCancelWindow(state *state.State) uint64 {
+	window := builtin.Params.Native(state).Get(meter.KeyAuctionCancelWindow)
+	if window.Sign() <= 0 || window.IsUint64() == false {
+		return AUCTION_CANCEL_WINDOW
+	}
+	return window.Uint64()
+}
+
+// api routine interface
+func GetAuctionBidList(addr meter.Address) (*AuctionBidList, error) {
+	auction := GetAuctionGlobInst()
+	if auction == nil {
+		log.Error("auction is not initialized...")
+		err := errors.New("aution is not initialized...")
+		return NewAuctionBidList(nil), err
+	}
+
+	best := auction.chain.BestBlock()
+	state, err := auction.stateCreator.NewState(best.Header().StateRoot())
+	if err != nil {
+		return NewAuctionBidList(nil), err
+	}
+
+	return auction.GetBidList(addr, state), nil
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package auction_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/dfinlab/meter/lvldb"
+	"github.com/dfinlab/meter/meter"
+	"github.com/dfinlab/meter/script/auction"
+	"github.com/dfinlab/meter/state"
+	"github.com/dfinlab/meter/xenv"
+	"github.com/stretchr/testify/assert"
+)
+
+func mtr(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), big.NewInt(1e18))
+}
+
+func TestAuctionBidList(t *testing.T) {
+	id := meter.BytesToBytes32([]byte("auction"))
+	l := auction.NewAuctionBidList(nil)
+	for i := 0; i < auction.AUCTION_MAX_BIDS_PER_ADDRESS+2; i++ {
+		l.Add(&auction.AuctionBid{AuctionID: id, Amount: mtr(10), Nonce: uint64(i)})
+	}
+	assert.Equal(t, auction.AUCTION_MAX_BIDS_PER_ADDRESS, l.Count())
+	assert.Equal(t, uint64(2), l.Bids[0].Nonce, "oldest dropped")
+
+	assert.Nil(t, l.GetActive(id, 1))
+	b := l.GetActive(id, 5)
+	assert.NotNil(t, b)
+	b.Cancelled = true
+	assert.Nil(t, l.GetActive(id, 5))
+	assert.Nil(t, l.GetActive(meter.Bytes32{}, 6), "other auction")
+}
+
+func TestAuctionBidCancel(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+	st, _ := state.New(meter.Bytes32{}, kv)
+	a := auction.NewAuction(nil, nil)
+	bidder, _ := meter.ParseAddress(HOLDER_ADDRESS)
+	st.SetEnergy(bidder, mtr(100))
+
+	env := auction.NewAuctionEnviroment(a, st, &xenv.TransactionContext{}, &xenv.BlockContext{}, nil)
+	start := &auction.AuctionBody{Opcode: auction.OP_START, Amount: mtr(1000), ReserveAmount: mtr(100), Timestamp: 1000}
+	_, _, err := start.StartAuctionCB(env, meter.ClauseGas)
+	assert.Nil(t, err)
+	id := a.GetAuctionCB(st).AuctionID
+
+	blockCtx := &xenv.BlockContext{Time: 2000}
+	env = auction.NewAuctionEnviroment(a, st, &xenv.TransactionContext{Origin: bidder}, blockCtx, nil)
+	for i, amount := range []int64{10, 20} {
+		bid := &auction.AuctionBody{Opcode: auction.OP_BID, Bidder: bidder, Amount: mtr(amount), Nonce: uint64(i + 1), Timestamp: 2000}
+		_, _, err = bid.HandleAuctionTx(env, meter.ClauseGas)
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, 2, a.GetBidList(bidder, st).Count(), "each bid recorded")
+	assert.Equal(t, mtr(30).String(), a.GetAuctionCB(st).Get(bidder).Amount.String())
+
+	assert.Equal(t, uint64(2000), a.GetBidList(bidder, st).Bids[0].Timestamp, "block time")
+
+	// window is checked against block time, from the auction start
+	cancel := &auction.AuctionBody{Opcode: auction.OP_CANCEL, AuctionID: id, Bidder: bidder, Nonce: 1, Timestamp: 2100}
+	blockCtx.Time = 1000 + auction.AUCTION_CANCEL_WINDOW + 1
+	_, _, err = cancel.CancelAuctionTx(env, meter.ClauseGas)
+	assert.NotNil(t, err, "window passed")
+
+	blockCtx.Time = 1000 + auction.AUCTION_CANCEL_WINDOW
+	_, _, err = cancel.CancelAuctionTx(env, meter.ClauseGas)
+	assert.Nil(t, err)
+	_, _, err = cancel.CancelAuctionTx(env, meter.ClauseGas)
+	assert.NotNil(t, err, "already cancelled")
+
+	cb := a.GetAuctionCB(st)
+	assert.Equal(t, mtr(20).String(), cb.Get(bidder).Amount.String())
+	assert.Equal(t, uint32(1), cb.Get(bidder).Count)
+	assert.Equal(t, mtr(20).String(), cb.RcvdMTR.String())
+	assert.Equal(t, mtr(80).String(), st.GetEnergy(bidder).String(), "refunded")
+	assert.Equal(t, mtr(20).String(), st.GetEnergy(auction.AuctionAccountAddr).String())
+	assert.True(t, a.GetBidList(bidder, st).Bids[0].Cancelled)
+}
@@ -13,18 +13,20 @@ import (
 
 //
 type AuctionEnviroment struct {
-	auction *Auction
-	state   *state.State
-	txCtx   *xenv.TransactionContext
-	toAddr  *meter.Address
+	auction  *Auction
+	state    *state.State
+	txCtx    *xenv.TransactionContext
+	blockCtx *xenv.BlockContext
+	toAddr   *meter.Address
 }
 
-func NewAuctionEnviroment(auction *Auction, state *state.State, txCtx *xenv.TransactionContext, to *meter.Address) *AuctionEnviroment {
+func NewAuctionEnviroment(auction *Auction, state *state.State, txCtx *xenv.TransactionContext, blockCtx *xenv.BlockContext, to *meter.Address) *AuctionEnviroment {
 	return &AuctionEnviroment{
-		auction: auction,
-		state:   state,
-		txCtx:   txCtx,
-		toAddr:  to,
+		auction:  auction,
+		state:    state,
+		txCtx:    txCtx,
+		blockCtx: blockCtx,
+		toAddr:   to,
 	}
 }
 
@@ -32,3 +34,11 @@ func (env *AuctionEnviroment) GetAuction() *Auction               { return env.a
 func (env *AuctionEnviroment) GetState() *state.State             { return env.state }
 func (env *AuctionEnviroment) GetTxCtx() *xenv.TransactionContext { return env.txCtx }
 func (env *AuctionEnviroment) GetToAddr() *meter.Address          { return env.toAddr }
+func (env *AuctionEnviroment) GetBlockCtx() *xenv.BlockContext    { return env.blockCtx }
+
+func (env *AuctionEnviroment) GetForkConfig() meter.ForkConfig { return env.auction.forkConfig }
+
+// IsForked tells if the fork at height is active in the block being executed.
+func (env *AuctionEnviroment) IsForked(height uint32) bool {
+	return env.blockCtx.Number >= height
+}
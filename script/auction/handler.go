@@ -6,19 +6,20 @@
 package auction
 
 import (
-	"errors"
 	"fmt"
 	"math/big"
 
 	"github.com/dfinlab/meter/builtin"
 	"github.com/dfinlab/meter/meter"
+	"github.com/dfinlab/meter/script/scripterr"
 	"github.com/ethereum/go-ethereum/rlp"
 )
 
 const (
-	OP_START = uint32(1)
-	OP_STOP  = uint32(2)
-	OP_BID   = uint32(3)
+	OP_START  = uint32(1)
+	OP_STOP   = uint32(2)
+	OP_BID    = uint32(3)
+	OP_CANCEL = uint32(4)
 )
 
 func GetOpName(op uint32) string {
@@ -29,6 +30,8 @@ func GetOpName(op uint32) string {
 		return "Bid"
 	case OP_STOP:
 		return "Stop"
+	case OP_CANCEL:
+		return "Cancel"
 	default:
 		return "Unknown"
 	}
@@ -70,17 +73,30 @@ func (ab *AuctionBody) GetOpName(op uint32) string {
 		return "Stop"
 	case OP_BID:
 		return "Bid"
+	case OP_CANCEL:
+		return "Cancel"
 	default:
 		return "Unknown"
 	}
 }
 
 var (
-	errNotStart             = errors.New("Auction not start")
-	errNotStop              = errors.New("An auction is active, stop first")
-	errNotEnoughMTR         = errors.New("not enough MTR balance")
-	errLessThanBidThreshold = errors.New("amount less than bid threshold (" + big.NewInt(0).Div(MinimumBidAmount, big.NewInt(1e18)).String() + " MTR)")
-	errInvalidNonce         = errors.New("invalid nonce (nonce in auction body and clause are the same)")
+	// Errors of auction, codes are stable and must not be reused
+	Errors = scripterr.NewRegistry()
+
+	errNotStart             = Errors.New(1, "Auction not start")
+	errNotStop              = Errors.New(2, "An auction is active, stop first")
+	errNotEnoughMTR         = Errors.New(3, "not enough MTR balance")
+	errLessThanBidThreshold = Errors.New(4, "amount less than bid threshold ("+big.NewInt(0).Div(MinimumBidAmount, big.NewInt(1e18)).String()+" MTR)")
+	errInvalidNonce         = Errors.New(5, "invalid nonce (nonce in auction body and clause are the same)")
+	errAuctionIDMismatch    = Errors.New(6, "auction id mismatch")
+	errBidNotFound          = Errors.New(7, "bid not found")
+	errCancelWindowPassed   = Errors.New(8, "bid cancel window passed")
+
+	// tx sender
+	errNotFromKblock   = Errors.New(20, "not from kblock")
+	errBidderNotOrigin = Errors.New(21, "bidder address is not the same from transaction")
+	errUnknownOpcode   = Errors.New(22, "unknow auction opcode")
 )
 
 func AuctionEncodeBytes(sb *AuctionBody) []byte {
@@ -182,18 +198,21 @@ func (ab *AuctionBody) CloseAuctionCB(senv *AuctionEnviroment, gas uint64) (ret
 		DistMTRG:     dist,
 	}
 
-	// limit the summary list to AUCTION_MAX_SUMMARIES
-	var summaries []*AuctionSummary
-	sumLen := len(summaryList.Summaries)
-	if sumLen >= AUCTION_MAX_SUMMARIES {
-		summaries = append(summaryList.Summaries[sumLen-AUCTION_MAX_SUMMARIES+1:], summary)
+	auctionCB = &AuctionCB{}
+	if senv.IsForked(senv.GetForkConfig().AuctionSummaryArchive) {
+		Auction.ArchiveSummary(summary, state)
 	} else {
-		summaries = append(summaryList.Summaries, summary)
+		// limit the summary list to AUCTION_MAX_SUMMARIES
+		var summaries []*AuctionSummary
+		sumLen := len(summaryList.Summaries)
+		if sumLen >= AUCTION_MAX_SUMMARIES {
+			summaries = append(summaryList.Summaries[sumLen-AUCTION_MAX_SUMMARIES+1:], summary)
+		} else {
+			summaries = append(summaryList.Summaries, summary)
+		}
+		summaryList = NewAuctionSummaryList(summaries)
+		Auction.SetSummaryList(summaryList, state)
 	}
-
-	summaryList = NewAuctionSummaryList(summaries)
-	auctionCB = &AuctionCB{}
-	Auction.SetSummaryList(summaryList, state)
 	Auction.SetAuctionCB(auctionCB, state)
 	return
 }
@@ -269,6 +288,83 @@ func (ab *AuctionBody) HandleAuctionTx(senv *AuctionEnviroment, gas uint64) (ret
 		return
 	}
 
+	// keep the individual bid in the history of bidder
+	if senv.IsForked(senv.GetForkConfig().AuctionBids) {
+		bidList := Auction.GetBidList(ab.Bidder, state)
+		bidList.Add(&AuctionBid{
+			AuctionID: auctionCB.AuctionID,
+			Addr:      ab.Bidder,
+			Amount:    new(big.Int).Set(ab.Amount),
+			Nonce:     ab.Nonce,
+			Timestamp: senv.GetBlockCtx().Time,
+		})
+		Auction.SetBidList(ab.Bidder, bidList, state)
+	}
+
+	Auction.SetAuctionCB(auctionCB, state)
+	return
+}
+
+// CancelAuctionTx withdraws the bid with Nonce in the active auction and refunds
+// the MTR. Bids are cancelled only within the cancel window after the auction
+// starts, checked against the block time.
+func (ab *AuctionBody) CancelAuctionTx(senv *AuctionEnviroment, gas uint64) (ret []byte, leftOverGas uint64, err error) {
+	defer func() {
+		if err != nil {
+			ret = []byte(err.Error())
+		}
+	}()
+	Auction := senv.GetAuction()
+	state := senv.GetState()
+	auctionCB := Auction.GetAuctionCB(state)
+	bidList := Auction.GetBidList(ab.Bidder, state)
+
+	if gas < meter.ClauseGas {
+		leftOverGas = 0
+	} else {
+		leftOverGas = gas - meter.ClauseGas
+	}
+
+	if auctionCB.IsActive() == false {
+		log.Info("CancelAuctionTx: auction not start")
+		err = errNotStart
+		return
+	}
+	if ab.AuctionID != auctionCB.AuctionID {
+		err = errAuctionIDMismatch
+		return
+	}
+
+	bid := bidList.GetActive(auctionCB.AuctionID, ab.Nonce)
+	tx := auctionCB.Get(ab.Bidder)
+	if bid == nil || tx == nil || tx.Amount.Cmp(bid.Amount) < 0 {
+		log.Info("bid not found", "bidder", ab.Bidder, "nonce", ab.Nonce)
+		err = errBidNotFound
+		return
+	}
+	if now := senv.GetBlockCtx().Time; now > auctionCB.CreateTime+GetCancelWindow(state) {
+		log.Info("bid cancel window passed", "bidder", ab.Bidder, "auctionStart", auctionCB.CreateTime, "cancelTime", now)
+		err = errCancelWindowPassed
+		return
+	}
+
+	err = Auction.TransferMTRToBidder(ab.Bidder, bid.Amount, state)
+	if err != nil {
+		log.Error("refund bid failed", "address", ab.Bidder, "error", err)
+		return
+	}
+
+	tx.Amount = new(big.Int).Sub(tx.Amount, bid.Amount)
+	if tx.Count > 0 {
+		tx.Count--
+	}
+	if tx.Amount.Sign() == 0 {
+		auctionCB.Remove(ab.Bidder)
+	}
+	auctionCB.RcvdMTR = new(big.Int).Sub(auctionCB.RcvdMTR, bid.Amount)
+	bid.Cancelled = true
+
+	Auction.SetBidList(ab.Bidder, bidList, state)
 	Auction.SetAuctionCB(auctionCB, state)
 	return
 }
@@ -46,7 +46,7 @@ func getHistoryPrices() *[N]float64 {
 	history := [N]float64{}
 	reservedPrice := GetAuctionReservedPrice()
 
-	list, err := GetAuctionSummaryList()
+	list, err := GetLastAuctionSummaries(N)
 	if err != nil {
 		panic("get auction summary failed")
 	}
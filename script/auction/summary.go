@@ -13,9 +13,11 @@ import (
 	"strings"
 
 	"github.com/dfinlab/meter/meter"
+	"github.com/dfinlab/meter/state"
 )
 
 const (
+	// cap of the summary list, before the AuctionSummaryArchive fork
 	AUCTION_MAX_SUMMARIES = 512
 )
 
@@ -46,27 +48,164 @@ func (a *AuctionSummary) ToString() string {
 		a.CreateTime, a.RcvdMTR.String(), a.ActualPrice.String(), a.LeftoverMTRG.String())
 }
 
+// ArchiveSummary appends the summary to the archive, since the AuctionSummaryArchive
+// fork. The first one after the fork migrates the retired summary list to the
+// archive, so it's done once.
+func (a *Auction) ArchiveSummary(summary *AuctionSummary, state *state.State) {
+	count := a.getArchivedCount(state)
+	if count == 0 {
+		count = a.migrateSummaryList(state)
+	}
+	a.setArchivedSummary(count, summary, state)
+	a.setArchivedCount(count+1, state)
+}
+
+// migrateSummaryList moves the summaries in the retired list to the archive, in
+// the same order, and clears the list. It returns the number of summaries moved.
+func (a *Auction) migrateSummaryList(state *state.State) uint64 {
+	legacy := a.GetSummaryList(state)
+	if legacy.Count() == 0 {
+		return 0
+	}
+	for i, s := range legacy.Summaries {
+		a.setArchivedSummary(uint64(i), s, state)
+	}
+	a.SetSummaryList(NewAuctionSummaryList(nil), state)
+	log.Info("auction summary list migrated", "count", legacy.Count())
+	return uint64(legacy.Count())
+}
+
+// GetSummaryCount returns the number of summaries, in the summary list if
+// nothing archived yet.
+func (a *Auction) GetSummaryCount(state *state.State) uint64 {
+	if count := a.getArchivedCount(state); count > 0 {
+		return count
+	}
+	return uint64(a.GetSummaryList(state).Count())
+}
+
+// GetSummaryByIndex returns the summary of the index-th auction, nil if out of range.
+func (a *Auction) GetSummaryByIndex(index uint64, state *state.State) *AuctionSummary {
+	if a.getArchivedCount(state) > 0 {
+		return a.getArchivedSummary(index, state)
+	}
+	legacy := a.GetSummaryList(state)
+	if index >= uint64(legacy.Count()) {
+		return nil
+	}
+	return legacy.Summaries[index]
+}
+
+func (a *Auction) GetSummaryByID(id meter.Bytes32, state *state.State) *AuctionSummary {
+	if a.getArchivedCount(state) > 0 {
+		index, ok := a.getArchivedIndex(id, state)
+		if !ok {
+			return nil
+		}
+		return a.getArchivedSummary(index, state)
+	}
+	return a.GetSummaryList(state).Get(id)
+}
+
+// GetSummaries returns the summaries of index in [start, end).
+func (a *Auction) GetSummaries(start, end uint64, state *state.State) *AuctionSummaryList {
+	if count := a.GetSummaryCount(state); end > count {
+		end = count
+	}
+	summaries := make([]*AuctionSummary, 0)
+	for i := start; i < end; i++ {
+		if s := a.GetSummaryByIndex(i, state); s != nil {
+			summaries = append(summaries, s)
+		}
+	}
+	return NewAuctionSummaryList(summaries)
+}
+
+// GetLastSummaries returns the last n summaries, in the order of auctions.
+func (a *Auction) GetLastSummaries(n uint64, state *state.State) *AuctionSummaryList {
+	count := a.GetSummaryCount(state)
+	if n > count {
+		n = count
+	}
+	return a.GetSummaries(count-n, count, state)
+}
+
+// SearchSummaryByEpoch returns the index of the first summary that ends at or
+// after epoch, the count of summaries if none. Auctions are in the order of epochs.
+func (a *Auction) SearchSummaryByEpoch(epoch uint64, state *state.State) uint64 {
+	count := a.GetSummaryCount(state)
+	l, r := uint64(0), count
+	for l < r {
+		m := (l + r) / 2
+		s := a.GetSummaryByIndex(m, state)
+		if s != nil && s.EndEpoch < epoch {
+			l = m + 1
+		} else {
+			r = m
+		}
+	}
+	return l
+}
+
+func (a *Auction) bestState() (*state.State, error) {
+	best := a.chain.BestBlock()
+	return a.stateCreator.NewState(best.Header().StateRoot())
+}
+
 // api routine interface
-func GetAuctionSummaryList() (*AuctionSummaryList, error) {
+func GetAuctionSummaryByID(id meter.Bytes32) (*AuctionSummary, error) {
 	auction := GetAuctionGlobInst()
 	if auction == nil {
 		log.Error("auction is not initialized...")
-		err := errors.New("aution is not initialized...")
-		return NewAuctionSummaryList(nil), err
+		return nil, errors.New("aution is not initialized...")
 	}
+	state, err := auction.bestState()
+	if err != nil {
+		return nil, err
+	}
+	return auction.GetSummaryByID(id, state), nil
+}
 
-	best := auction.chain.BestBlock()
-	state, err := auction.stateCreator.NewState(best.Header().StateRoot())
+// GetAuctionSummaries returns at most limit summaries ending in epoch [fromEpoch, toEpoch],
+// skipping the first offset ones.
+func GetAuctionSummaries(fromEpoch, toEpoch uint64, offset, limit uint64) (*AuctionSummaryList, error) {
+	auction := GetAuctionGlobInst()
+	if auction == nil {
+		log.Error("auction is not initialized...")
+		return NewAuctionSummaryList(nil), errors.New("aution is not initialized...")
+	}
+	state, err := auction.bestState()
 	if err != nil {
 		return NewAuctionSummaryList(nil), err
 	}
 
-	summaryList := auction.GetSummaryList(state)
-	if summaryList == nil {
-		log.Error("no summaryList stored ...")
+	start := auction.SearchSummaryByEpoch(fromEpoch, state)
+	end := auction.GetSummaryCount(state)
+	if toEpoch < ^uint64(0) {
+		end = auction.SearchSummaryByEpoch(toEpoch+1, state)
+	}
+	start += offset
+	if start >= end {
 		return NewAuctionSummaryList(nil), nil
 	}
-	return summaryList, nil
+	if end-start > limit {
+		end = start + limit
+	}
+	return auction.GetSummaries(start, end, state), nil
+}
+
+// GetLastAuctionSummaries returns the last n summaries, in the order of auctions.
+func GetLastAuctionSummaries(n uint64) (*AuctionSummaryList, error) {
+	auction := GetAuctionGlobInst()
+	if auction == nil {
+		log.Error("auction is not initialized...")
+		return NewAuctionSummaryList(nil), errors.New("aution is not initialized...")
+	}
+	state, err := auction.bestState()
+	if err != nil {
+		return NewAuctionSummaryList(nil), err
+	}
+	return auction.GetLastSummaries(n, state), nil
 }
 
 type AuctionSummaryList struct {
@@ -0,0 +1,66 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package auction_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/dfinlab/meter/lvldb"
+	"github.com/dfinlab/meter/meter"
+	"github.com/dfinlab/meter/script/auction"
+	"github.com/dfinlab/meter/state"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSummary(i uint64) *auction.AuctionSummary {
+	return &auction.AuctionSummary{
+		AuctionID:    meter.BytesToBytes32(big.NewInt(int64(i + 1)).Bytes()),
+		StartEpoch:   i * 24,
+		EndEpoch:     i*24 + 24,
+		RlsdMTRG:     mtr(1000),
+		RsvdMTRG:     mtr(100),
+		RsvdPrice:    big.NewInt(5e17),
+		RcvdMTR:      mtr(int64(i)),
+		ActualPrice:  big.NewInt(5e17),
+		LeftoverMTRG: big.NewInt(0),
+		DistMTRG:     []*auction.DistMtrg{{Addr: meter.BytesToAddress([]byte("bidder")), Amount: mtr(1)}},
+	}
+}
+
+func TestAuctionSummaryArchive(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+	st, _ := state.New(meter.Bytes32{}, kv)
+	a := auction.NewAuction(nil, nil)
+
+	// summaries in the retired list
+	a.SetSummaryList(auction.NewAuctionSummaryList([]*auction.AuctionSummary{newSummary(0), newSummary(1)}), st)
+	assert.Equal(t, uint64(2), a.GetSummaryCount(st))
+	assert.Equal(t, newSummary(1).AuctionID, a.GetSummaryByIndex(1, st).AuctionID)
+	assert.NotNil(t, a.GetSummaryByID(newSummary(0).AuctionID, st))
+
+	// archiving moves them over
+	for i := uint64(2); i < 600; i++ {
+		a.ArchiveSummary(newSummary(i), st)
+	}
+	assert.Equal(t, 0, a.GetSummaryList(st).Count())
+	assert.Equal(t, uint64(600), a.GetSummaryCount(st), "no cap")
+
+	s := a.GetSummaryByID(newSummary(0).AuctionID, st)
+	assert.NotNil(t, s)
+	assert.Equal(t, 1, len(s.DistMTRG), "payouts kept")
+	assert.Equal(t, uint64(420*24+24), a.GetSummaryByID(newSummary(420).AuctionID, st).EndEpoch)
+	assert.Nil(t, a.GetSummaryByID(meter.Bytes32{}, st))
+
+	last := a.GetLastSummaries(24, st)
+	assert.Equal(t, 24, last.Count())
+	assert.Equal(t, newSummary(599).AuctionID, last.Summaries[23].AuctionID, "in the order of auctions")
+
+	assert.Equal(t, uint64(10), a.SearchSummaryByEpoch(10*24+24, st))
+	assert.Equal(t, uint64(11), a.SearchSummaryByEpoch(10*24+25, st))
+	assert.Equal(t, uint64(600), a.SearchSummaryByEpoch(1e9, st))
+	assert.Equal(t, 5, a.GetSummaries(10, 15, st).Count())
+}
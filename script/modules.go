@@ -8,6 +8,7 @@ package script
 import (
 	"github.com/dfinlab/meter/script/accountlock"
 	"github.com/dfinlab/meter/script/auction"
+	"github.com/dfinlab/meter/script/scripterr"
 	"github.com/dfinlab/meter/script/staking"
 )
 
@@ -32,10 +33,12 @@ func ModuleStakingInit(se *ScriptEngine) *staking.Staking {
 		modName:    STAKING_MODULE_NAME,
 		modID:      STAKING_MODULE_ID,
 		modHandler: stk.PrepareStakingHandler(),
+		opName:     staking.GetOpName,
 	}
 	if err := se.modReg.Register(STAKING_MODULE_ID, mod); err != nil {
 		panic("register staking module failed")
 	}
+	scripterr.RegisterModule(STAKING_MODULE_ID, STAKING_MODULE_NAME, staking.GetOpName)
 
 	stk.Start()
 	se.logger.Info("ScriptEngine", "started moudle", mod.modName)
@@ -52,10 +55,12 @@ func ModuleAuctionInit(se *ScriptEngine) *auction.Auction {
 		modName:    AUCTION_MODULE_NAME,
 		modID:      AUCTION_MODULE_ID,
 		modHandler: a.PrepareAuctionHandler(),
+		opName:     auction.GetOpName,
 	}
 	if err := se.modReg.Register(AUCTION_MODULE_ID, mod); err != nil {
 		panic("register auction module failed")
 	}
+	scripterr.RegisterModule(AUCTION_MODULE_ID, AUCTION_MODULE_NAME, auction.GetOpName)
 
 	a.Start()
 	se.logger.Info("ScriptEngine", "started moudle", mod.modName)
@@ -72,10 +77,12 @@ func ModuleAccountLockInit(se *ScriptEngine) *accountlock.AccountLock {
 		modName:    ACCOUNTLOCK_MODULE_NAME,
 		modID:      ACCOUNTLOCK_MODULE_ID,
 		modHandler: a.PrepareAccountLockHandler(),
+		opName:     (&accountlock.AccountLockBody{}).GetOpName,
 	}
 	if err := se.modReg.Register(ACCOUNTLOCK_MODULE_ID, mod); err != nil {
 		panic("register accountlock module failed")
 	}
+	scripterr.RegisterModule(ACCOUNTLOCK_MODULE_ID, ACCOUNTLOCK_MODULE_NAME, (&accountlock.AccountLockBody{}).GetOpName)
 
 	a.Start()
 	se.logger.Info("ScriptEngine", "started moudle", mod.modName)
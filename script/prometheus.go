@@ -0,0 +1,23 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package script
+
+import "github.com/dfinlab/meter/metric"
+
+var scriptOpCounter = metric.NewCounterVec("script_ops_total", "Counter of script engine ops by module, op and result", "module", "op", "result")
+
+// countScriptOp counts the op of the module, op is 0 if the payload is not decoded.
+func countScriptOp(mod *Module, op uint32, err error) {
+	name := "unknown"
+	if op != 0 && mod.opName != nil {
+		name = mod.opName(op)
+	}
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+	scriptOpCounter.WithLabelValues(mod.modName, name, result).Inc()
+}
@@ -19,7 +19,8 @@ import (
 type Module struct {
 	modName    string
 	modID      uint32
-	modHandler func(data []byte, to *meter.Address, txCtx *xenv.TransactionContext, gas uint64, state *state.State) (ret []byte, leftOverGas uint64, err error)
+	modHandler func(data []byte, to *meter.Address, txCtx *xenv.TransactionContext, blockCtx *xenv.BlockContext, gas uint64, state *state.State) (ret []byte, leftOverGas uint64, op uint32, err error)
+	opName     func(op uint32) string // name of the op returned by modHandler, for metrics
 }
 
 func (m *Module) ToString() string {
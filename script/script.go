@@ -17,6 +17,7 @@ import (
 	"github.com/dfinlab/meter/meter"
 	"github.com/dfinlab/meter/script/accountlock"
 	"github.com/dfinlab/meter/script/auction"
+	"github.com/dfinlab/meter/script/scripterr"
 	"github.com/dfinlab/meter/script/staking"
 	"github.com/dfinlab/meter/state"
 	"github.com/dfinlab/meter/xenv"
@@ -121,7 +122,7 @@ func (se *ScriptEngine) StartAllModules() {
 	ModuleAccountLockInit(se)
 }
 
-func (se *ScriptEngine) HandleScriptData(data []byte, to *meter.Address, txCtx *xenv.TransactionContext, gas uint64, state *state.State) (ret []byte, leftOverGas uint64, err error) {
+func (se *ScriptEngine) HandleScriptData(data []byte, to *meter.Address, txCtx *xenv.TransactionContext, blockCtx *xenv.BlockContext, gas uint64, state *state.State) (ret []byte, leftOverGas uint64, err error) {
 	se.logger.Info("received script data", "to", to, "gas", gas, "data", hex.EncodeToString(data))
 	if bytes.Compare(data[:len(ScriptPattern)], ScriptPattern[:]) != 0 {
 		err := errors.New(fmt.Sprintf("Pattern mismatch, pattern = %v", hex.EncodeToString(data[:len(ScriptPattern)])))
@@ -145,6 +146,11 @@ func (se *ScriptEngine) HandleScriptData(data []byte, to *meter.Address, txCtx *
 	// se.logger.Info("script header", "header", header.ToString(), "module", mod.ToString())
 
 	//module handler
-	ret, leftOverGas, err = mod.modHandler(script.Payload, to, txCtx, gas, state)
+	ret, leftOverGas, op, err := mod.modHandler(script.Payload, to, txCtx, blockCtx, gas, state)
+	countScriptOp(mod, op, err)
+	if err != nil {
+		// the structured revert data for clients, see scripterr.Decode
+		ret = scripterr.Encode(mod.modID, op, err)
+	}
 	return
 }
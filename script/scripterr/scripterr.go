@@ -0,0 +1,157 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+// Package scripterr gives the errors of script engine modules stable numeric
+// codes, and encodes them as the revert data of failed clauses.
+package scripterr
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// CodeUnknown is the code of errors not registered by the module.
+const CodeUnknown = uint32(0)
+
+var (
+	// Selector is the 4-byte selector of the revert data, which is
+	// ABI-encoded as ScriptError(uint32 module, uint32 op, uint32 code, string message)
+	Selector = crypto.Keccak256([]byte("ScriptError(uint32,uint32,uint32,string)"))[:4]
+
+	modules sync.Map // module ID -> *moduleInfo
+)
+
+// Error is a module error with a code that never changes across releases.
+type Error struct {
+	Code    uint32
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Registry holds the errors of a module, codes can't be reused.
+type Registry struct {
+	errs map[uint32]*Error
+}
+
+func NewRegistry() *Registry {
+	return &Registry{errs: make(map[uint32]*Error)}
+}
+
+// New registers the error of code.
+func (r *Registry) New(code uint32, message string) *Error {
+	if code == CodeUnknown {
+		panic("scripterr: code 0 is reserved")
+	}
+	if e, ok := r.errs[code]; ok {
+		panic(fmt.Sprintf("scripterr: code %v already used by %q", code, e.Message))
+	}
+	e := &Error{Code: code, Message: message}
+	r.errs[code] = e
+	return e
+}
+
+// Get returns the error of code, nil if not registered.
+func (r *Registry) Get(code uint32) *Error {
+	return r.errs[code]
+}
+
+type moduleInfo struct {
+	name   string
+	opName func(op uint32) string
+}
+
+// RegisterModule names the module ID and its ops for decoding.
+func RegisterModule(modID uint32, name string, opName func(op uint32) string) {
+	modules.Store(modID, &moduleInfo{name, opName})
+}
+
+// Revert is the decoded revert data.
+type Revert struct {
+	ModuleID uint32
+	Op       uint32
+	Code     uint32
+	Message  string
+}
+
+// Module returns the name of the module, or the ID if unknown.
+func (r *Revert) Module() string {
+	if info, ok := modules.Load(r.ModuleID); ok {
+		return info.(*moduleInfo).name
+	}
+	return fmt.Sprint(r.ModuleID)
+}
+
+// OpName returns the name of the op, or the op number if unknown.
+func (r *Revert) OpName() string {
+	if info, ok := modules.Load(r.ModuleID); ok && info.(*moduleInfo).opName != nil {
+		return info.(*moduleInfo).opName(r.Op)
+	}
+	return fmt.Sprint(r.Op)
+}
+
+// Encode encodes err of the op as revert data, errors not created by a
+// Registry are of CodeUnknown.
+func Encode(modID uint32, op uint32, err error) []byte {
+	code := CodeUnknown
+	if e, ok := err.(*Error); ok {
+		code = e.Code
+	}
+	msg := []byte(err.Error())
+
+	// selector, 3 words of uint32, offset, length and the padded message
+	data := make([]byte, 4+32*5+(len(msg)+31)/32*32)
+	copy(data, Selector)
+	putWord(data[4:], uint64(modID))
+	putWord(data[4+32:], uint64(op))
+	putWord(data[4+64:], uint64(code))
+	putWord(data[4+96:], 32*4)
+	putWord(data[4+128:], uint64(len(msg)))
+	copy(data[4+160:], msg)
+	return data
+}
+
+// Decode decodes the revert data, nil if data is not encoded by Encode.
+func Decode(data []byte) *Revert {
+	if len(data) < 4+32*5 || string(data[:4]) != string(Selector) {
+		return nil
+	}
+	body := data[4:]
+	modID, ok1 := getUint32(body[0:])
+	op, ok2 := getUint32(body[32:])
+	code, ok3 := getUint32(body[64:])
+	offset, ok4 := getUint32(body[96:])
+	if !(ok1 && ok2 && ok3 && ok4) || uint64(offset)+32 > uint64(len(body)) {
+		return nil
+	}
+	length, ok := getUint32(body[offset:])
+	if !ok || uint64(offset)+32+uint64(length) > uint64(len(body)) {
+		return nil
+	}
+	return &Revert{
+		ModuleID: modID,
+		Op:       op,
+		Code:     code,
+		Message:  string(body[offset+32 : offset+32+length]),
+	}
+}
+
+func putWord(b []byte, v uint64) {
+	binary.BigEndian.PutUint64(b[24:32], v)
+}
+
+func getUint32(b []byte) (uint32, bool) {
+	v := new(big.Int).SetBytes(b[:32])
+	if !v.IsUint64() || v.Uint64() > uint64(^uint32(0)) {
+		return 0, false
+	}
+	return uint32(v.Uint64()), true
+}
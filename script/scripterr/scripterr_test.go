@@ -0,0 +1,58 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package scripterr_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/dfinlab/meter/script/scripterr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	reg := scripterr.NewRegistry()
+	errTest := reg.New(7, "bucket not owned by the holder")
+	scripterr.RegisterModule(9999, "test", func(op uint32) string {
+		return fmt.Sprintf("op%v", op)
+	})
+
+	data := scripterr.Encode(9999, 3, errTest)
+	assert.Equal(t, scripterr.Selector, data[:4])
+	assert.Equal(t, 0, (len(data)-4)%32, "abi words")
+
+	r := scripterr.Decode(data)
+	assert.NotNil(t, r)
+	assert.Equal(t, uint32(9999), r.ModuleID)
+	assert.Equal(t, uint32(7), r.Code)
+	assert.Equal(t, errTest.Message, r.Message)
+	assert.Equal(t, "test", r.Module())
+	assert.Equal(t, "op3", r.OpName())
+	assert.Equal(t, errTest, reg.Get(r.Code))
+
+	// errors not registered are of unknown code
+	r = scripterr.Decode(scripterr.Encode(12345, 1, errors.New("boom")))
+	assert.Equal(t, scripterr.CodeUnknown, r.Code)
+	assert.Equal(t, "boom", r.Message)
+	assert.Equal(t, "12345", r.Module())
+	assert.Equal(t, "1", r.OpName())
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	assert.Nil(t, scripterr.Decode(nil))
+	assert.Nil(t, scripterr.Decode([]byte("bucket not owned by the holder")))
+
+	data := scripterr.Encode(1, 1, errors.New("message"))
+	assert.Nil(t, scripterr.Decode(data[:len(data)-40]), "truncated")
+}
+
+func TestRegistryDuplicate(t *testing.T) {
+	reg := scripterr.NewRegistry()
+	reg.New(1, "first")
+	assert.Panics(t, func() { reg.New(1, "second") })
+	assert.Panics(t, func() { reg.New(scripterr.CodeUnknown, "reserved") })
+}
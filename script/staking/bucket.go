@@ -37,6 +37,9 @@ type Bucket struct {
 }
 
 //bucketID Candidate .. are excluded
+// ID is only taken as BucketID when the bucket is created. BucketID is kept when
+// the value changes later on (top-up, split, merge, compound), so it may differ
+// from ID() after that, always refer to the bucket with BucketID.
 func (b *Bucket) ID() (hash meter.Bytes32) {
 	hw := meter.NewBlake2b()
 	err := rlp.Encode(hw, []interface{}{
@@ -113,6 +116,70 @@ func (b *Bucket) UpdateLockOption(opt uint32, rate uint8) {
 	return
 }
 
+// CalcBonus returns the bonus votes accrued by the bucket since CalcLastTime.
+func (b *Bucket) CalcBonus(ts uint64) *big.Int {
+	if ts < b.CalcLastTime {
+		return big.NewInt(0)
+	}
+	denominator := big.NewInt(int64((3600 * 24 * 365) * 100))
+	bonus := big.NewInt(int64((ts - b.CalcLastTime) * uint64(b.Rate)))
+	bonus = bonus.Mul(bonus, b.Value)
+	bonus = bonus.Div(bonus, denominator)
+	return bonus
+}
+
+// SettleBonus adds the bonus votes accrued up to ts to the bucket, and calculates
+// from ts on. It returns the added bonus.
+func (b *Bucket) SettleBonus(ts uint64) *big.Int {
+	bonus := b.CalcBonus(ts)
+	b.BonusVotes += bonus.Uint64()
+	b.TotalVotes = new(big.Int).Add(b.TotalVotes, bonus)
+	if ts > b.CalcLastTime {
+		b.CalcLastTime = ts
+	}
+	return bonus
+}
+
+// AddValue tops up the bucket at ts, the added value counts in votes immediately.
+// Bonus of the current value is settled first, so the added value earns bonus
+// from ts on.
+func (b *Bucket) AddValue(amount *big.Int, ts uint64) {
+	b.SettleBonus(ts)
+	b.Value = new(big.Int).Add(b.Value, amount)
+	b.TotalVotes = new(big.Int).Add(b.TotalVotes, amount)
+}
+
+// Split moves amount out of the bucket at ts to a new one with the same owner,
+// token, candidate and lock option. Bonus is settled first, then bonus votes are
+// split pro rata of the value.
+func (b *Bucket) Split(amount *big.Int, ts uint64, create uint64, nonce uint64) *Bucket {
+	b.SettleBonus(ts)
+	bonus := new(big.Int).SetUint64(b.BonusVotes)
+	bonus.Mul(bonus, amount)
+	bonus.Div(bonus, b.Value)
+
+	nb := NewBucket(b.Owner, b.Candidate, new(big.Int).Set(amount), b.Token, b.Option, b.Rate, create, nonce)
+	nb.BonusVotes = bonus.Uint64()
+	nb.TotalVotes = new(big.Int).Add(nb.Value, bonus)
+	nb.CalcLastTime = b.CalcLastTime
+
+	b.Value = new(big.Int).Sub(b.Value, amount)
+	b.BonusVotes -= nb.BonusVotes
+	b.TotalVotes = new(big.Int).Sub(b.TotalVotes, nb.TotalVotes)
+	return nb
+}
+
+// Merge moves value and bonus votes of from into the bucket at ts. Both buckets
+// settle their bonus to ts at their own rate first, the caller makes sure they
+// have the same lock option.
+func (b *Bucket) Merge(from *Bucket, ts uint64) {
+	b.SettleBonus(ts)
+	from.SettleBonus(ts)
+	b.Value = new(big.Int).Add(b.Value, from.Value)
+	b.BonusVotes += from.BonusVotes
+	b.TotalVotes = new(big.Int).Add(b.TotalVotes, from.TotalVotes)
+}
+
 type BucketList struct {
 	buckets []*Bucket
 }
@@ -0,0 +1,66 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package staking_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/dfinlab/meter/script/staking"
+	"github.com/stretchr/testify/assert"
+)
+
+func mtrg(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), big.NewInt(1e18))
+}
+
+const year = uint64(3600 * 24 * 365)
+
+// percent returns n percent of v
+func percent(v *big.Int, n int64) *big.Int {
+	return new(big.Int).Div(new(big.Int).Mul(v, big.NewInt(n)), big.NewInt(100))
+}
+
+func TestBucketAddValue(t *testing.T) {
+	b := staking.NewBucket(HolderAddress, CandidateAddress, mtrg(10), staking.TOKEN_METER_GOV, staking.ONE_WEEK_LOCK, staking.ONE_WEEK_LOCK_RATE, Timestamp, Nonce)
+	id := b.BucketID
+
+	// bonus of the old value is settled, the added value earns from now on
+	b.AddValue(mtrg(5), Timestamp+year)
+	bonus := percent(mtrg(10), 5)
+	assert.Equal(t, mtrg(15).String(), b.Value.String())
+	assert.Equal(t, bonus.Uint64(), b.BonusVotes)
+	assert.Equal(t, new(big.Int).Add(mtrg(15), bonus).String(), b.TotalVotes.String())
+	assert.Equal(t, Timestamp+year, b.CalcLastTime)
+	assert.Equal(t, percent(mtrg(15), 5).String(), b.CalcBonus(Timestamp+2*year).String())
+	assert.Equal(t, id, b.BucketID, "id is kept")
+}
+
+func TestBucketSplitMerge(t *testing.T) {
+	b := staking.NewBucket(HolderAddress, CandidateAddress, mtrg(40), staking.TOKEN_METER_GOV, staking.TWO_WEEK_LOCK, staking.TWO_WEEK_LOCK_RATE, Timestamp, Nonce)
+
+	nb := b.Split(mtrg(10), Timestamp+year, Timestamp+year, Nonce+1)
+	bonus := percent(mtrg(40), 6)
+	assert.NotEqual(t, b.BucketID, nb.BucketID)
+	assert.Equal(t, mtrg(30).String(), b.Value.String())
+	assert.Equal(t, mtrg(10).String(), nb.Value.String())
+	assert.Equal(t, percent(bonus, 75).Uint64(), b.BonusVotes)
+	assert.Equal(t, percent(bonus, 25).Uint64(), nb.BonusVotes, "bonus votes pro rata")
+	assert.Equal(t, new(big.Int).Add(mtrg(40), bonus).String(), new(big.Int).Add(b.TotalVotes, nb.TotalVotes).String(), "votes preserved")
+	assert.Equal(t, b.Option, nb.Option)
+	assert.Equal(t, b.Rate, nb.Rate)
+	assert.Equal(t, b.Candidate, nb.Candidate)
+	assert.Equal(t, Timestamp+year, b.CalcLastTime)
+	assert.Equal(t, Timestamp+year, nb.CalcLastTime)
+
+	// both settle to the merge time, no accrued bonus is lost
+	b.Merge(nb, Timestamp+2*year)
+	bonus = new(big.Int).Add(bonus, percent(mtrg(40), 6))
+	assert.Equal(t, mtrg(40).String(), b.Value.String())
+	assert.Equal(t, bonus.Uint64(), b.BonusVotes)
+	assert.Equal(t, new(big.Int).Add(mtrg(40), bonus).String(), b.TotalVotes.String())
+	assert.Equal(t, Timestamp+2*year, b.CalcLastTime)
+}
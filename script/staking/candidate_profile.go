@@ -0,0 +1,241 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package staking
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dfinlab/meter/meter"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// size limits of candidate metadata
+const (
+	MAX_CANDIDATE_DESCRIPTION_LEN = 512
+	MAX_CANDIDATE_WEBSITE_LEN     = 128
+	MAX_CANDIDATE_CONTACT_LEN     = 128
+)
+
+// commission changes
+const (
+	// commission moves by at most this step at each governing, unit shannon, aka 1e09
+	MAX_COMMISSION_CHANGE_PER_EPOCH = uint64(2 * 1e07) // 2%
+	// increases take effect after this notice, so delegators can leave in time
+	COMMISSION_INCREASE_NOTICE = uint64(3600 * 24 * 7) // 1 week
+)
+
+var (
+	errCandidateMetaTooLong = Errors.New(36, "candidate metadata too long")
+	errCandidateMetaInvalid = Errors.New(37, "invalid candidate metadata")
+)
+
+// CandidateMeta is the optional metadata of candidate, carried in ExtraData of
+// candidate and candidate update as RLP.
+type CandidateMeta struct {
+	Description []byte
+	Website     []byte
+	Contact     []byte
+	LogoHash    meter.Bytes32 // hash of the logo image, stored off-chain
+}
+
+// DecodeCandidateMeta decodes and validates the metadata, nil if data is empty.
+func DecodeCandidateMeta(data []byte) (*CandidateMeta, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var meta CandidateMeta
+	if err := rlp.DecodeBytes(data, &meta); err != nil {
+		return nil, errCandidateMetaInvalid
+	}
+	if len(meta.Description) > MAX_CANDIDATE_DESCRIPTION_LEN ||
+		len(meta.Website) > MAX_CANDIDATE_WEBSITE_LEN ||
+		len(meta.Contact) > MAX_CANDIDATE_CONTACT_LEN {
+		return nil, errCandidateMetaTooLong
+	}
+	return &meta, nil
+}
+
+func (m *CandidateMeta) Equal(o *CandidateMeta) bool {
+	return bytes.Equal(m.Description, o.Description) && bytes.Equal(m.Website, o.Website) &&
+		bytes.Equal(m.Contact, o.Contact) && m.LogoHash == o.LogoHash
+}
+
+// CandidateProfile keeps the metadata and the pending commission change of a
+// candidate. It's stored aside from the candidate list, so the stored candidates
+// are decoded as before.
+type CandidateProfile struct {
+	Addr meter.Address
+	Meta CandidateMeta
+
+	Pending           bool   // a commission change is pending
+	PendingCommission uint64 // commission requested
+	PendingTime       uint64 // the change starts not earlier than this time
+}
+
+func NewCandidateProfile(addr meter.Address) *CandidateProfile {
+	return &CandidateProfile{Addr: addr}
+}
+
+func (p *CandidateProfile) ToString() string {
+	return fmt.Sprintf("CandidateProfile(%v) Website=%v, Contact=%v, LogoHash=%v, Pending=%v, PendingCommission=%v, PendingTime=%v",
+		p.Addr, string(p.Meta.Website), string(p.Meta.Contact), p.Meta.LogoHash, p.Pending, p.PendingCommission, p.PendingTime)
+}
+
+// RequestCommission records the commission change requested at ts. Decreases
+// start at the next governing, increases after the notice period.
+func (p *CandidateProfile) RequestCommission(current, commission uint64, ts uint64) {
+	if commission == current {
+		p.Pending, p.PendingCommission, p.PendingTime = false, 0, 0
+		return
+	}
+	p.Pending = true
+	p.PendingCommission = commission
+	p.PendingTime = ts
+	if commission > current {
+		p.PendingTime = ts + COMMISSION_INCREASE_NOTICE
+	}
+}
+
+// NextCommission returns the commission at governing time ts, moved towards the
+// pending one by at most MAX_COMMISSION_CHANGE_PER_EPOCH. The pending change is
+// cleared once reached.
+func (p *CandidateProfile) NextCommission(current uint64, ts uint64) uint64 {
+	if !p.Pending || ts < p.PendingTime {
+		return current
+	}
+	next := p.PendingCommission
+	if next > current && next-current > MAX_COMMISSION_CHANGE_PER_EPOCH {
+		next = current + MAX_COMMISSION_CHANGE_PER_EPOCH
+	} else if next < current && current-next > MAX_COMMISSION_CHANGE_PER_EPOCH {
+		next = current - MAX_COMMISSION_CHANGE_PER_EPOCH
+	}
+	if next == p.PendingCommission {
+		p.Pending, p.PendingCommission, p.PendingTime = false, 0, 0
+	}
+	return next
+}
+
+func (p *CandidateProfile) IsEmpty() bool {
+	return !p.Pending && len(p.Meta.Description) == 0 && len(p.Meta.Website) == 0 &&
+		len(p.Meta.Contact) == 0 && p.Meta.LogoHash.IsZero()
+}
+
+type CandidateProfileList struct {
+	profiles []*CandidateProfile
+}
+
+func NewCandidateProfileList(profiles []*CandidateProfile) *CandidateProfileList {
+	if profiles == nil {
+		profiles = make([]*CandidateProfile, 0)
+	}
+	sort.SliceStable(profiles, func(i, j int) bool {
+		return bytes.Compare(profiles[i].Addr.Bytes(), profiles[j].Addr.Bytes()) <= 0
+	})
+	return &CandidateProfileList{profiles: profiles}
+}
+
+func (l *CandidateProfileList) indexOf(addr meter.Address) (int, int) {
+	// return values:
+	//     first parameter: if found, the index of the item
+	//     second parameter: if not found, the correct insert index of the item
+	if len(l.profiles) <= 0 {
+		return -1, 0
+	}
+	left := 0
+	right := len(l.profiles)
+	for left < right {
+		m := (left + right) / 2
+		cmp := bytes.Compare(addr.Bytes(), l.profiles[m].Addr.Bytes())
+		if cmp < 0 {
+			right = m
+		} else if cmp > 0 {
+			left = m + 1
+		} else {
+			return m, -1
+		}
+	}
+	return -1, right
+}
+
+func (l *CandidateProfileList) Get(addr meter.Address) *CandidateProfile {
+	index, _ := l.indexOf(addr)
+	if index < 0 {
+		return nil
+	}
+	return l.profiles[index]
+}
+
+// GetOrNew returns the profile of addr, a new one is added if not found.
+func (l *CandidateProfileList) GetOrNew(addr meter.Address) *CandidateProfile {
+	if p := l.Get(addr); p != nil {
+		return p
+	}
+	p := NewCandidateProfile(addr)
+	l.Add(p)
+	return p
+}
+
+func (l *CandidateProfileList) Add(p *CandidateProfile) {
+	index, insertIndex := l.indexOf(p.Addr)
+	if index >= 0 {
+		l.profiles[index] = p
+		return
+	}
+	newList := make([]*CandidateProfile, insertIndex)
+	copy(newList, l.profiles[:insertIndex])
+	newList = append(newList, p)
+	newList = append(newList, l.profiles[insertIndex:]...)
+	l.profiles = newList
+}
+
+func (l *CandidateProfileList) Remove(addr meter.Address) {
+	index, _ := l.indexOf(addr)
+	if index >= 0 {
+		l.profiles = append(l.profiles[:index], l.profiles[index+1:]...)
+	}
+}
+
+func (l *CandidateProfileList) ToString() string {
+	if l == nil || len(l.profiles) == 0 {
+		return "CandidateProfileList (size:0)"
+	}
+	s := []string{fmt.Sprintf("CandidateProfileList (size:%v) {", len(l.profiles))}
+	for i, p := range l.profiles {
+		s = append(s, fmt.Sprintf("%d. %v", i, p.ToString()))
+	}
+	s = append(s, "}")
+	return strings.Join(s, "\n")
+}
+
+func (l *CandidateProfileList) ToList() []CandidateProfile {
+	result := make([]CandidateProfile, 0)
+	for _, p := range l.profiles {
+		result = append(result, *p)
+	}
+	return result
+}
+
+// api routine interface
+func GetLatestCandidateProfileList() (*CandidateProfileList, error) {
+	staking := GetStakingGlobInst()
+	if staking == nil {
+		log.Warn("staking is not initialized...")
+		err := errors.New("staking is not initialized...")
+		return NewCandidateProfileList(nil), err
+	}
+
+	best := staking.chain.BestBlock()
+	state, err := staking.stateCreator.NewState(best.Header().StateRoot())
+	if err != nil {
+		return NewCandidateProfileList(nil), err
+	}
+
+	return staking.GetCandidateProfileList(state), nil
+}
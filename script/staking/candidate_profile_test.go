@@ -0,0 +1,86 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package staking_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dfinlab/meter/meter"
+	"github.com/dfinlab/meter/script/staking"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeCandidateMeta(t *testing.T) {
+	meta, err := staking.DecodeCandidateMeta(nil)
+	assert.Nil(t, err)
+	assert.Nil(t, meta)
+
+	data, _ := rlp.EncodeToBytes(&staking.CandidateMeta{
+		Description: []byte("a meter validator"),
+		Website:     []byte("https://meter.io"),
+		LogoHash:    meter.Blake2b([]byte("logo")),
+	})
+	meta, err = staking.DecodeCandidateMeta(data)
+	assert.Nil(t, err)
+	assert.Equal(t, "https://meter.io", string(meta.Website))
+
+	data, _ = rlp.EncodeToBytes(&staking.CandidateMeta{
+		Description: []byte(strings.Repeat("x", staking.MAX_CANDIDATE_DESCRIPTION_LEN+1)),
+	})
+	_, err = staking.DecodeCandidateMeta(data)
+	assert.NotNil(t, err, "too long")
+
+	_, err = staking.DecodeCandidateMeta([]byte("garbage"))
+	assert.NotNil(t, err)
+}
+
+func TestCandidateCommissionChange(t *testing.T) {
+	const pct = uint64(1e07)
+	p := staking.NewCandidateProfile(CandidateAddress)
+
+	// increase waits for the notice, then moves by the max step per epoch
+	p.RequestCommission(10*pct, 15*pct, Timestamp)
+	assert.Equal(t, Timestamp+staking.COMMISSION_INCREASE_NOTICE, p.PendingTime)
+	assert.Equal(t, 10*pct, p.NextCommission(10*pct, Timestamp+3600))
+
+	ts := p.PendingTime
+	assert.Equal(t, 12*pct, p.NextCommission(10*pct, ts))
+	assert.Equal(t, 14*pct, p.NextCommission(12*pct, ts+3600))
+	assert.Equal(t, 15*pct, p.NextCommission(14*pct, ts+7200))
+	assert.False(t, p.Pending, "cleared once reached")
+	assert.True(t, p.IsEmpty())
+
+	// decrease starts at the next governing
+	p.RequestCommission(15*pct, 12*pct, ts)
+	assert.Equal(t, 13*pct, p.NextCommission(15*pct, ts))
+	assert.Equal(t, 12*pct, p.NextCommission(13*pct, ts+3600))
+
+	// requesting the current commission cancels the pending one
+	p.RequestCommission(12*pct, 20*pct, ts)
+	p.RequestCommission(12*pct, 12*pct, ts)
+	assert.False(t, p.Pending)
+	assert.Equal(t, 12*pct, p.NextCommission(12*pct, ts+staking.COMMISSION_INCREASE_NOTICE))
+
+	// zero commission is a change as any other
+	p.RequestCommission(1*pct, 0, ts)
+	assert.True(t, p.Pending)
+	assert.False(t, p.IsEmpty())
+	assert.Equal(t, uint64(0), p.NextCommission(1*pct, ts))
+	assert.False(t, p.Pending)
+}
+
+func TestCandidateProfileList(t *testing.T) {
+	l := staking.NewCandidateProfileList(nil)
+	p := l.GetOrNew(CandidateAddress)
+	p.Meta.Website = []byte("https://meter.io")
+	assert.Equal(t, "https://meter.io", string(l.Get(CandidateAddress).Meta.Website))
+	assert.Equal(t, 1, len(l.ToList()))
+
+	l.Remove(CandidateAddress)
+	assert.Nil(t, l.Get(CandidateAddress))
+}
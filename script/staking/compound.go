@@ -0,0 +1,184 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package staking
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/dfinlab/meter/meter"
+	"github.com/dfinlab/meter/state"
+)
+
+// Compound indicates the holder opts in to reinvest the validator rewards into
+// the bucket at each governing.
+type Compound struct {
+	Holder   meter.Address
+	BucketID meter.Bytes32
+}
+
+func (c *Compound) ToString() string {
+	return fmt.Sprintf("Compound(%v) BucketID=%v", c.Holder, c.BucketID)
+}
+
+type CompoundList struct {
+	compounds []*Compound
+}
+
+func NewCompoundList(compounds []*Compound) *CompoundList {
+	if compounds == nil {
+		compounds = make([]*Compound, 0)
+	}
+	sort.SliceStable(compounds, func(i, j int) bool {
+		return bytes.Compare(compounds[i].Holder.Bytes(), compounds[j].Holder.Bytes()) <= 0
+	})
+	return &CompoundList{compounds: compounds}
+}
+
+func (l *CompoundList) indexOf(addr meter.Address) (int, int) {
+	// return values:
+	//     first parameter: if found, the index of the item
+	//     second parameter: if not found, the correct insert index of the item
+	if len(l.compounds) <= 0 {
+		return -1, 0
+	}
+	left := 0
+	right := len(l.compounds)
+	for left < right {
+		m := (left + right) / 2
+		cmp := bytes.Compare(addr.Bytes(), l.compounds[m].Holder.Bytes())
+		if cmp < 0 {
+			right = m
+		} else if cmp > 0 {
+			left = m + 1
+		} else {
+			return m, -1
+		}
+	}
+	return -1, right
+}
+
+func (l *CompoundList) Get(addr meter.Address) *Compound {
+	index, _ := l.indexOf(addr)
+	if index < 0 {
+		return nil
+	}
+	return l.compounds[index]
+}
+
+func (l *CompoundList) Exist(addr meter.Address) bool {
+	index, _ := l.indexOf(addr)
+	return index >= 0
+}
+
+// Add inserts the compound, or replaces the bucket if the holder already opted in.
+func (l *CompoundList) Add(c *Compound) {
+	index, insertIndex := l.indexOf(c.Holder)
+	if index >= 0 {
+		l.compounds[index] = c
+		return
+	}
+	newList := make([]*Compound, insertIndex)
+	copy(newList, l.compounds[:insertIndex])
+	newList = append(newList, c)
+	newList = append(newList, l.compounds[insertIndex:]...)
+	l.compounds = newList
+}
+
+func (l *CompoundList) Remove(addr meter.Address) {
+	index, _ := l.indexOf(addr)
+	if index >= 0 {
+		l.compounds = append(l.compounds[:index], l.compounds[index+1:]...)
+	}
+}
+
+func (l *CompoundList) Count() int {
+	return len(l.compounds)
+}
+
+func (l *CompoundList) ToString() string {
+	if l == nil || len(l.compounds) == 0 {
+		return "CompoundList (size:0)"
+	}
+	s := []string{fmt.Sprintf("CompoundList (size:%v) {", len(l.compounds))}
+	for i, c := range l.compounds {
+		s = append(s, fmt.Sprintf("%d. %v", i, c.ToString()))
+	}
+	s = append(s, "}")
+	return strings.Join(s, "\n")
+}
+
+func (l *CompoundList) ToList() []Compound {
+	result := make([]Compound, 0)
+	for _, c := range l.compounds {
+		result = append(result, *c)
+	}
+	return result
+}
+
+// compoundTarget returns the bucket the rewards of holder are reinvested into,
+// nil if the holder has not opted in or the bucket is no longer eligible.
+func compoundTarget(c *Compound, bucketList *BucketList) *Bucket {
+	if c == nil {
+		return nil
+	}
+	b := bucketList.Get(c.BucketID)
+	if b == nil || b.Owner != c.Holder || b.Token != TOKEN_METER || b.Unbounded == true {
+		return nil
+	}
+	return b
+}
+
+// CompoundRewards reinvests the distributed rewards of the opted-in holders into
+// their buckets. Rewards are bounded from the holder's balance, and the totals of
+// the stakeholder and candidate follow the new bucket value. Holders whose bucket
+// is gone or no longer eligible are dropped from the list, their rewards stay in
+// the balance. Bucket bonus is settled to ts before the value is added. It
+// returns the total amount reinvested.
+func (s *Staking) CompoundRewards(rewards []*RewardInfo, compoundList *CompoundList, bucketList *BucketList,
+	candidateList *CandidateList, stakeholderList *StakeholderList, ts uint64, state *state.State) *big.Int {
+	sum := big.NewInt(0)
+	for _, r := range rewards {
+		c := compoundList.Get(r.Address)
+		if c == nil || r.Amount.Sign() <= 0 {
+			continue
+		}
+		b := compoundTarget(c, bucketList)
+		if b == nil {
+			log.Info("compound bucket is not eligible, opted out", "holder", c.Holder, "bucketID", c.BucketID)
+			compoundList.Remove(c.Holder)
+			continue
+		}
+		if err := s.BoundAccountMeter(r.Address, r.Amount, state); err != nil {
+			log.Warn("compound rewards failed", "holder", r.Address, "amount", r.Amount, "err", err)
+			continue
+		}
+
+		stakeholder := stakeholderList.Get(b.Owner)
+		cand := candidateList.Get(b.Candidate)
+		if stakeholder != nil {
+			stakeholder.RemoveBucket(b)
+		}
+		if cand != nil {
+			cand.RemoveBucket(b)
+		}
+
+		b.AddValue(r.Amount, ts)
+
+		if stakeholder != nil {
+			stakeholder.AddBucket(b)
+		}
+		if cand != nil {
+			cand.AddBucket(b)
+		}
+		sum = sum.Add(sum, r.Amount)
+		log.Debug("rewards compounded", "holder", r.Address, "amount", r.Amount, "bucket", b.BucketID)
+	}
+	return sum
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package staking_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/dfinlab/meter/lvldb"
+	"github.com/dfinlab/meter/meter"
+	"github.com/dfinlab/meter/script/staking"
+	"github.com/dfinlab/meter/state"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompoundList(t *testing.T) {
+	l := staking.NewCompoundList(nil)
+	a := meter.BytesToAddress([]byte("a"))
+	b := meter.BytesToAddress([]byte("b"))
+
+	l.Add(&staking.Compound{Holder: b, BucketID: meter.BytesToBytes32([]byte("1"))})
+	l.Add(&staking.Compound{Holder: a, BucketID: meter.BytesToBytes32([]byte("2"))})
+	l.Add(&staking.Compound{Holder: b, BucketID: meter.BytesToBytes32([]byte("3"))})
+	assert.Equal(t, 2, l.Count())
+	assert.Equal(t, meter.BytesToBytes32([]byte("3")), l.Get(b).BucketID, "replaced")
+	assert.Equal(t, a, l.ToList()[0].Holder, "sorted by holder")
+
+	l.Remove(a)
+	assert.False(t, l.Exist(a))
+	assert.True(t, l.Exist(b))
+}
+
+func TestSetCompoundList(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+	st, _ := state.New(meter.Bytes32{}, kv)
+	s := &staking.Staking{}
+
+	// empty list leaves no storage
+	s.SetCompoundList(staking.NewCompoundList(nil), st)
+	assert.Empty(t, st.GetRawStorage(staking.StakingModuleAddr, staking.CompoundListKey))
+
+	l := s.GetCompoundList(st)
+	l.Add(&staking.Compound{Holder: HolderAddress, BucketID: StakingID})
+	s.SetCompoundList(l, st)
+	assert.NotEmpty(t, st.GetRawStorage(staking.StakingModuleAddr, staking.CompoundListKey))
+	assert.Equal(t, 1, s.GetCompoundList(st).Count())
+
+	// cleared when the last holder opts out
+	l.Remove(HolderAddress)
+	s.SetCompoundList(l, st)
+	assert.Empty(t, st.GetRawStorage(staking.StakingModuleAddr, staking.CompoundListKey))
+	assert.Equal(t, 0, s.GetCompoundList(st).Count())
+}
+
+func TestCompoundRewards(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+	st, _ := state.New(meter.Bytes32{}, kv)
+	s := &staking.Staking{}
+
+	bucketList := s.GetBucketList(st)
+	candidateList := s.GetCandidateList(st)
+	stakeholderList := s.GetStakeHolderList(st)
+	compoundList := s.GetCompoundList(st)
+
+	other := meter.BytesToAddress([]byte("other"))
+	b := staking.NewBucket(HolderAddress, CandidateAddress, mtrg(10), staking.TOKEN_METER, staking.ONE_WEEK_LOCK, staking.ONE_WEEK_LOCK_RATE, Timestamp, Nonce)
+	gone := staking.NewBucket(other, CandidateAddress, mtrg(10), staking.TOKEN_METER, staking.ONE_WEEK_LOCK, staking.ONE_WEEK_LOCK_RATE, Timestamp, Nonce+1)
+	bucketList.Add(b)
+
+	cand := staking.NewCandidate(CandidateAddress, []byte("cand"), []byte("pubkey"), []byte("1.2.3.4"), CandidatePort, 0, Timestamp)
+	cand.AddBucket(b)
+	candidateList.Add(cand)
+	holder := staking.NewStakeholder(HolderAddress)
+	holder.AddBucket(b)
+	stakeholderList.Add(holder)
+
+	compoundList.Add(&staking.Compound{Holder: HolderAddress, BucketID: b.BucketID})
+	compoundList.Add(&staking.Compound{Holder: other, BucketID: gone.BucketID})
+
+	reward := mtrg(2)
+	st.SetEnergy(HolderAddress, reward)
+	st.SetEnergy(other, reward)
+	rewards := []*staking.RewardInfo{
+		{Address: HolderAddress, Amount: reward},
+		{Address: other, Amount: reward},
+		{Address: CandidateAddress, Amount: reward},
+	}
+
+	sum := s.CompoundRewards(rewards, compoundList, bucketList, candidateList, stakeholderList, Timestamp, st)
+	assert.Equal(t, reward.String(), sum.String())
+
+	assert.Equal(t, mtrg(12).String(), bucketList.Get(b.BucketID).Value.String())
+	assert.Equal(t, mtrg(12).String(), candidateList.Get(CandidateAddress).TotalVotes.String(), "voting power follows")
+	assert.Equal(t, mtrg(12).String(), stakeholderList.Get(HolderAddress).TotalStake.String())
+	assert.Equal(t, big.NewInt(0).String(), st.GetEnergy(HolderAddress).String())
+	assert.Equal(t, reward.String(), st.GetBoundedEnergy(HolderAddress).String())
+
+	// bucket gone, opted out and rewards stay in balance
+	assert.False(t, compoundList.Exist(other))
+	assert.Equal(t, reward.String(), st.GetEnergy(other).String())
+}
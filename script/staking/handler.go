@@ -17,6 +17,7 @@ import (
 
 	"github.com/dfinlab/meter/builtin"
 	"github.com/dfinlab/meter/meter"
+	"github.com/dfinlab/meter/script/scripterr"
 	crypto "github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/rlp"
 )
@@ -29,6 +30,10 @@ const (
 	OP_DELEGATE       = uint32(5)
 	OP_UNDELEGATE     = uint32(6)
 	OP_CANDIDATE_UPDT = uint32(7)
+	OP_BUCKET_ADD     = uint32(8)
+	OP_BUCKET_SPLIT   = uint32(9)
+	OP_BUCKET_MERGE   = uint32(10)
+	OP_COMPOUND       = uint32(11)
 
 	OP_DELEGATE_STATISTICS  = uint32(101)
 	OP_DELEGATE_EXITJAIL    = uint32(102)
@@ -38,31 +43,52 @@ const (
 )
 
 var (
+	// Errors of staking, codes are stable and must not be reused
+	Errors = scripterr.NewRegistry()
+
 	// sanity check
-	errInvalidPubkey    = errors.New("invalid public key")
-	errInvalidIpAddress = errors.New("invalid ip address")
-	errInvalidPort      = errors.New("invalid port number")
-	errInvalidToken     = errors.New("invalid token")
+	errInvalidPubkey    = Errors.New(1, "invalid public key")
+	errInvalidIpAddress = Errors.New(2, "invalid ip address")
+	errInvalidPort      = Errors.New(3, "invalid port number")
+	errInvalidToken     = Errors.New(4, "invalid token")
 
 	// buckets
-	errBucketNotFound      = errors.New("bucket not found")
-	errBucketInfoMismatch  = errors.New("bucket info mismatch (holderAddr, amount, token)")
-	errBucketInUse         = errors.New("bucket in used (address is not zero)")
-	errUpdateForeverBucket = errors.New("can't update forever bucket")
+	errBucketNotFound      = Errors.New(10, "bucket not found")
+	errBucketInfoMismatch  = Errors.New(11, "bucket info mismatch (holderAddr, amount, token)")
+	errBucketInUse         = Errors.New(12, "bucket in used (address is not zero)")
+	errUpdateForeverBucket = Errors.New(13, "can't update forever bucket")
+	errBucketUnbounded     = Errors.New(14, "bucket is unbounded")
+	errBucketIDExist       = Errors.New(15, "bucket id already exists")
+	errBucketMergeMismatch = Errors.New(16, "buckets to merge mismatch (holderAddr, token, candidate, option)")
+	errBucketMergeSelf     = Errors.New(17, "can't merge bucket to itself")
+	errInvalidAmount       = Errors.New(18, "invalid amount")
+	errCompoundToken       = Errors.New(19, "rewards can only compound into MTR bucket")
 
 	// amount
-	errLessThanMinimalBalance  = errors.New("amount less than minimal balance (" + new(big.Int).Div(MIN_REQUIRED_BY_DELEGATE, big.NewInt(1e18)).String() + " MTRG)")
-	errLessThanMinBoundBalance = errors.New("amount less than minimal balance (" + new(big.Int).Div(MIN_BOUND_BALANCE, big.NewInt(1e18)).String() + " MTRG)")
-	errNotEnoughMTR            = errors.New("not enough MTR")
-	errNotEnoughMTRG           = errors.New("not enough MTRG")
+	errLessThanMinimalBalance  = Errors.New(20, "amount less than minimal balance ("+new(big.Int).Div(MIN_REQUIRED_BY_DELEGATE, big.NewInt(1e18)).String()+" MTRG)")
+	errLessThanMinBoundBalance = Errors.New(21, "amount less than minimal balance ("+new(big.Int).Div(MIN_BOUND_BALANCE, big.NewInt(1e18)).String()+" MTRG)")
+	errNotEnoughMTR            = Errors.New(22, "not enough MTR")
+	errNotEnoughMTRG           = Errors.New(23, "not enough MTRG")
+	errNotEnoughMeterBalance   = Errors.New(24, "not enough meter balance")
+	errNotEnoughMeterGov       = Errors.New(25, "not enough meter-gov balance")
+	errNotEnoughBail           = Errors.New(26, "not enough balance for bail")
+	errNotEnoughBoundedMeter   = Errors.New(27, "not enough bounded meter balance")
+	errNotEnoughBoundedMTRG    = Errors.New(28, "not enough bounded meter-gov balance")
 
 	// candidate
-	errCandidateNotListed          = errors.New("candidate address is not listed")
-	errCandidateInJail             = errors.New("candidate address is in jail")
-	errCandidateListed             = errors.New("candidate info already listed")
-	errUpdateTooFrequent           = errors.New("update too frequent")
-	errCandidateListedWithDiffInfo = errors.New("candidate address already listed with different infomation (pubkey, ip, port)")
-	errCandidateNotChanged         = errors.New("candidate not changed")
+	errCandidateNotListed          = Errors.New(30, "candidate address is not listed")
+	errCandidateInJail             = Errors.New(31, "candidate address is in jail")
+	errCandidateListed             = Errors.New(32, "candidate info already listed")
+	errUpdateTooFrequent           = Errors.New(33, "update too frequent")
+	errCandidateListedWithDiffInfo = Errors.New(34, "candidate address already listed with different infomation (pubkey, ip, port)")
+	errCandidateNotChanged         = Errors.New(35, "candidate not changed")
+
+	// tx sender
+	errHolderNotOrigin    = Errors.New(40, "holder address is not the same from transaction")
+	errCandidateNotOrigin = Errors.New(41, "candidate address is not the same from transaction")
+	errNotModuleAddress   = Errors.New(42, "to address is not the same from module address")
+	errNotExecutor        = Errors.New(43, "only executor can exec this API")
+	errUnknownOpcode      = Errors.New(44, "unknow staking opcode")
 )
 
 func GetOpName(op uint32) string {
@@ -81,6 +107,14 @@ func GetOpName(op uint32) string {
 		return "Undelegate"
 	case OP_CANDIDATE_UPDT:
 		return "CandidateUpdate"
+	case OP_BUCKET_ADD:
+		return "BucketAdd"
+	case OP_BUCKET_SPLIT:
+		return "BucketSplit"
+	case OP_BUCKET_MERGE:
+		return "BucketMerge"
+	case OP_COMPOUND:
+		return "Compound"
 	case OP_DELEGATE_STATISTICS:
 		return "DelegateStatistics"
 	case OP_DELEGATE_EXITJAIL:
@@ -197,11 +231,11 @@ func (sb *StakingBody) BoundHandler(senv *StakingEnviroment, gas uint64) (ret []
 	switch sb.Token {
 	case TOKEN_METER:
 		if state.GetEnergy(sb.HolderAddr).Cmp(sb.Amount) < 0 {
-			err = errors.New("not enough meter balance")
+			err = errNotEnoughMeterBalance
 		}
 	case TOKEN_METER_GOV:
 		if state.GetBalance(sb.HolderAddr).Cmp(sb.Amount) < 0 {
-			err = errors.New("not enough meter-gov balance")
+			err = errNotEnoughMeterGov
 		}
 	default:
 		err = errInvalidToken
@@ -212,7 +246,7 @@ func (sb *StakingBody) BoundHandler(senv *StakingEnviroment, gas uint64) (ret []
 	}
 
 	// sanity checked, now do the action
-	opt, rate, locktime := GetBoundLockOption(sb.Option)
+	opt, rate, locktime := senv.GetStakingParams().LockOption(sb.Option)
 	log.Info("get bound option", "option", opt, "rate", rate, "locktime", locktime)
 
 	var candAddr meter.Address
@@ -290,7 +324,7 @@ func (sb *StakingBody) UnBoundHandler(senv *StakingEnviroment, gas uint64) (ret
 
 	// sanity check done, take actions
 	b.Unbounded = true
-	b.MatureTime = sb.Timestamp + GetBoundLocktime(b.Option) // lock time
+	b.MatureTime = sb.Timestamp + senv.GetStakingParams().Locktime(b.Option) // lock time
 
 	staking.SetCandidateList(candidateList, state)
 	staking.SetBucketList(bucketList, state)
@@ -375,8 +409,17 @@ func (sb *StakingBody) CandidateHandler(senv *StakingEnviroment, gas uint64) (re
 		return
 	}
 
+	// metadata is carried in ExtraData since the CandidateProfile fork
+	profiled := senv.IsForked(senv.GetForkConfig().CandidateProfile)
+	var meta *CandidateMeta
+	if profiled {
+		if meta, err = DecodeCandidateMeta(sb.ExtraData); err != nil {
+			return
+		}
+	}
+
 	// now staking the amount, force to forever lock
-	opt, rate, locktime := GetBoundLockOption(FOREVER_LOCK)
+	opt, rate, locktime := senv.GetStakingParams().LockOption(FOREVER_LOCK)
 	commission := GetCommissionRate(sb.Option)
 	log.Info("get bound option", "option", opt, "rate", rate, "locktime", locktime, "commission", commission)
 
@@ -407,6 +450,12 @@ func (sb *StakingBody) CandidateHandler(senv *StakingEnviroment, gas uint64) (re
 		err = errInvalidToken
 	}
 
+	if meta != nil {
+		profileList := staking.GetCandidateProfileList(state)
+		profileList.GetOrNew(sb.CandAddr).Meta = *meta
+		staking.SetCandidateProfileList(profileList, state)
+	}
+
 	staking.SetCandidateList(candidateList, state)
 	staking.SetBucketList(bucketList, state)
 	staking.SetStakeHolderList(stakeholderList, state)
@@ -460,12 +509,18 @@ func (sb *StakingBody) UnCandidateHandler(senv *StakingEnviroment, gas uint64) (
 		b.Candidate = meter.Address{}
 		// candidate locked bucket back to normal(longest lock)
 		if b.IsForeverLock() == true {
-			opt, rate, _ := GetBoundLockOption(FOUR_WEEK_LOCK)
+			opt, rate, _ := senv.GetStakingParams().LockOption(FOUR_WEEK_LOCK)
 			b.UpdateLockOption(opt, rate)
 		}
 	}
 	candidateList.Remove(record.Addr)
 
+	profileList := staking.GetCandidateProfileList(state)
+	if profileList.Get(record.Addr) != nil {
+		profileList.Remove(record.Addr)
+		staking.SetCandidateProfileList(profileList, state)
+	}
+
 	staking.SetCandidateList(candidateList, state)
 	staking.SetBucketList(bucketList, state)
 	staking.SetStakeHolderList(stakeholderList, state)
@@ -473,6 +528,267 @@ func (sb *StakingBody) UnCandidateHandler(senv *StakingEnviroment, gas uint64) (
 
 }
 
+// BucketAddHandler tops up the bucket StakingID with Amount.
+func (sb *StakingBody) BucketAddHandler(senv *StakingEnviroment, gas uint64) (ret []byte, leftOverGas uint64, err error) {
+	defer func() {
+		if err != nil {
+			ret = []byte(err.Error())
+		}
+	}()
+	staking := senv.GetStaking()
+	state := senv.GetState()
+	candidateList := staking.GetCandidateList(state)
+	bucketList := staking.GetBucketList(state)
+	stakeholderList := staking.GetStakeHolderList(state)
+
+	if gas < meter.ClauseGas {
+		leftOverGas = 0
+	} else {
+		leftOverGas = gas - meter.ClauseGas
+	}
+
+	if sb.Amount == nil || sb.Amount.Sign() <= 0 {
+		return nil, leftOverGas, errInvalidAmount
+	}
+	b := bucketList.Get(sb.StakingID)
+	if b == nil {
+		return nil, leftOverGas, errBucketNotFound
+	}
+	if (b.Owner != sb.HolderAddr) || (b.Token != sb.Token) {
+		return nil, leftOverGas, errBucketInfoMismatch
+	}
+	if b.Unbounded == true {
+		return nil, leftOverGas, errBucketUnbounded
+	}
+
+	switch sb.Token {
+	case TOKEN_METER:
+		err = staking.BoundAccountMeter(sb.HolderAddr, sb.Amount, state)
+	case TOKEN_METER_GOV:
+		err = staking.BoundAccountMeterGov(sb.HolderAddr, sb.Amount, state)
+	default:
+		err = errInvalidToken
+	}
+	if err != nil {
+		log.Error("errors", "error", err)
+		return
+	}
+
+	// sanity check done, take actions
+	// the bucket is removed and added back, so totals follow the new value
+	stakeholder := stakeholderList.Get(b.Owner)
+	cand := candidateList.Get(b.Candidate)
+	if stakeholder != nil {
+		stakeholder.RemoveBucket(b)
+	}
+	if cand != nil {
+		cand.RemoveBucket(b)
+	}
+
+	b.AddValue(sb.Amount, senv.GetBlockCtx().Time)
+
+	if stakeholder != nil {
+		stakeholder.AddBucket(b)
+	}
+	if cand != nil {
+		cand.AddBucket(b)
+	}
+
+	staking.SetCandidateList(candidateList, state)
+	staking.SetBucketList(bucketList, state)
+	staking.SetStakeHolderList(stakeholderList, state)
+	return
+}
+
+// BucketSplitHandler moves Amount out of the bucket StakingID to a new bucket.
+func (sb *StakingBody) BucketSplitHandler(senv *StakingEnviroment, gas uint64) (ret []byte, leftOverGas uint64, err error) {
+	defer func() {
+		if err != nil {
+			ret = []byte(err.Error())
+		}
+	}()
+	staking := senv.GetStaking()
+	state := senv.GetState()
+	candidateList := staking.GetCandidateList(state)
+	bucketList := staking.GetBucketList(state)
+	stakeholderList := staking.GetStakeHolderList(state)
+
+	if gas < meter.ClauseGas {
+		leftOverGas = 0
+	} else {
+		leftOverGas = gas - meter.ClauseGas
+	}
+
+	if sb.Amount == nil || sb.Amount.Sign() <= 0 {
+		return nil, leftOverGas, errInvalidAmount
+	}
+	b := bucketList.Get(sb.StakingID)
+	if b == nil {
+		return nil, leftOverGas, errBucketNotFound
+	}
+	if (b.Owner != sb.HolderAddr) || (b.Token != sb.Token) {
+		return nil, leftOverGas, errBucketInfoMismatch
+	}
+	if b.IsForeverLock() == true {
+		return nil, leftOverGas, errUpdateForeverBucket
+	}
+	if b.Unbounded == true {
+		return nil, leftOverGas, errBucketUnbounded
+	}
+	// both buckets should meet the bound minimal requirement
+	if sb.Amount.Cmp(MIN_BOUND_BALANCE) < 0 || new(big.Int).Sub(b.Value, sb.Amount).Cmp(MIN_BOUND_BALANCE) < 0 {
+		return nil, leftOverGas, errLessThanMinBoundBalance
+	}
+
+	// sanity check done, take actions
+	stakeholder := stakeholderList.Get(b.Owner)
+	cand := candidateList.Get(b.Candidate)
+	if stakeholder != nil {
+		stakeholder.RemoveBucket(b)
+	}
+	if cand != nil {
+		cand.RemoveBucket(b)
+	}
+
+	nb := b.Split(sb.Amount, senv.GetBlockCtx().Time, sb.Timestamp, sb.Nonce)
+	if bucketList.Exist(nb.BucketID) {
+		// lists are not saved on error, nothing to revert
+		return nil, leftOverGas, errBucketIDExist
+	}
+	bucketList.Add(nb)
+
+	if stakeholder != nil {
+		stakeholder.AddBucket(b)
+		stakeholder.AddBucket(nb)
+	}
+	if cand != nil {
+		cand.AddBucket(b)
+		cand.AddBucket(nb)
+	}
+
+	staking.SetCandidateList(candidateList, state)
+	staking.SetBucketList(bucketList, state)
+	staking.SetStakeHolderList(stakeholderList, state)
+	return
+}
+
+// BucketMergeHandler merges the bucket given by ExtraData into the bucket StakingID,
+// the merged one is removed.
+func (sb *StakingBody) BucketMergeHandler(senv *StakingEnviroment, gas uint64) (ret []byte, leftOverGas uint64, err error) {
+	defer func() {
+		if err != nil {
+			ret = []byte(err.Error())
+		}
+	}()
+	staking := senv.GetStaking()
+	state := senv.GetState()
+	candidateList := staking.GetCandidateList(state)
+	bucketList := staking.GetBucketList(state)
+	stakeholderList := staking.GetStakeHolderList(state)
+
+	if gas < meter.ClauseGas {
+		leftOverGas = 0
+	} else {
+		leftOverGas = gas - meter.ClauseGas
+	}
+
+	if len(sb.ExtraData) != len(meter.Bytes32{}) {
+		return nil, leftOverGas, errBucketNotFound
+	}
+	fromID := meter.BytesToBytes32(sb.ExtraData)
+	if fromID == sb.StakingID {
+		return nil, leftOverGas, errBucketMergeSelf
+	}
+	b := bucketList.Get(sb.StakingID)
+	from := bucketList.Get(fromID)
+	if b == nil || from == nil {
+		return nil, leftOverGas, errBucketNotFound
+	}
+	if (b.Owner != sb.HolderAddr) || (b.Token != sb.Token) {
+		return nil, leftOverGas, errBucketInfoMismatch
+	}
+	if (from.Owner != b.Owner) || (from.Token != b.Token) || (from.Candidate != b.Candidate) || (from.Option != b.Option) {
+		return nil, leftOverGas, errBucketMergeMismatch
+	}
+	if b.IsForeverLock() == true || from.IsForeverLock() == true {
+		return nil, leftOverGas, errUpdateForeverBucket
+	}
+	if b.Unbounded == true || from.Unbounded == true {
+		return nil, leftOverGas, errBucketUnbounded
+	}
+
+	// sanity check done, take actions
+	stakeholder := stakeholderList.Get(b.Owner)
+	cand := candidateList.Get(b.Candidate)
+	if stakeholder != nil {
+		stakeholder.RemoveBucket(b)
+		stakeholder.RemoveBucket(from)
+	}
+	if cand != nil {
+		cand.RemoveBucket(b)
+		cand.RemoveBucket(from)
+	}
+
+	b.Merge(from, senv.GetBlockCtx().Time)
+	bucketList.Remove(from.BucketID)
+
+	if stakeholder != nil {
+		stakeholder.AddBucket(b)
+	}
+	if cand != nil {
+		cand.AddBucket(b)
+	}
+
+	staking.SetCandidateList(candidateList, state)
+	staking.SetBucketList(bucketList, state)
+	staking.SetStakeHolderList(stakeholderList, state)
+	return
+}
+
+// CompoundHandler opts the holder in to reinvest validator rewards into the MTR
+// bucket StakingID at each governing, a zero StakingID opts out.
+func (sb *StakingBody) CompoundHandler(senv *StakingEnviroment, gas uint64) (ret []byte, leftOverGas uint64, err error) {
+	defer func() {
+		if err != nil {
+			ret = []byte(err.Error())
+		}
+	}()
+	staking := senv.GetStaking()
+	state := senv.GetState()
+	bucketList := staking.GetBucketList(state)
+	compoundList := staking.GetCompoundList(state)
+
+	if gas < meter.ClauseGas {
+		leftOverGas = 0
+	} else {
+		leftOverGas = gas - meter.ClauseGas
+	}
+
+	if sb.StakingID.IsZero() {
+		compoundList.Remove(sb.HolderAddr)
+		staking.SetCompoundList(compoundList, state)
+		return
+	}
+
+	b := bucketList.Get(sb.StakingID)
+	if b == nil {
+		return nil, leftOverGas, errBucketNotFound
+	}
+	if b.Owner != sb.HolderAddr {
+		return nil, leftOverGas, errBucketInfoMismatch
+	}
+	if b.Token != TOKEN_METER {
+		return nil, leftOverGas, errCompoundToken
+	}
+	if b.Unbounded == true {
+		return nil, leftOverGas, errBucketUnbounded
+	}
+
+	compoundList.Add(&Compound{Holder: sb.HolderAddr, BucketID: sb.StakingID})
+	staking.SetCompoundList(compoundList, state)
+	return
+}
+
 func (sb *StakingBody) DelegateHandler(senv *StakingEnviroment, gas uint64) (ret []byte, leftOverGas uint64, err error) {
 	defer func() {
 		if err != nil {
@@ -583,6 +899,8 @@ func (sb *StakingBody) GoverningHandler(senv *StakingEnviroment, gas uint64) (re
 	delegateList := staking.GetDelegateList(state)
 	inJailList := staking.GetInJailList(state)
 	rewardList := staking.GetValidatorRewardList(state)
+	compoundList := staking.GetCompoundList(state)
+	params := senv.GetStakingParams()
 
 	if gas < meter.ClauseGas {
 		leftOverGas = 0
@@ -601,7 +919,8 @@ func (sb *StakingBody) GoverningHandler(senv *StakingEnviroment, gas uint64) (re
 	// only need to take action when distribute amount is non-zero
 	if sb.Amount.Sign() != 0 {
 		epoch := sb.Version //epoch is stored in sb.Version tempraroly
-		sum, info, err := staking.DistValidatorRewards(sb.Amount, validators, delegateList, state)
+		rewardHistory := senv.IsForked(senv.GetForkConfig().ValidatorRewardHistory)
+		sum, info, err := staking.DistValidatorRewards(sb.Amount, validators, delegateList, params.ShareScale, state, !rewardHistory)
 		if err != nil {
 			log.Error("Distribute validator rewards failed" + err.Error())
 		} else {
@@ -613,6 +932,16 @@ func (sb *StakingBody) GoverningHandler(senv *StakingEnviroment, gas uint64) (re
 			}
 			log.Info("validator rewards", "reward", reward.ToString())
 			log.Debug("validator rewards", "distribute", info)
+			if rewardHistory {
+				staking.SetEpochRewards(&EpochRewards{Reward: reward, Rewards: info}, state)
+			}
+
+			// reinvest rewards of the opted-in holders, before votes and shares are
+			// calculated below, so they take effect in the same pass
+			if compoundList.Count() > 0 {
+				compounded := staking.CompoundRewards(info, compoundList, bucketList, candidateList, stakeholderList, sb.Timestamp, state)
+				log.Info("validator rewards compounded", "amount", compounded)
+			}
 
 			var rewards []*ValidatorReward
 			rLen := len(rewardList.rewards)
@@ -634,7 +963,7 @@ func (sb *StakingBody) GoverningHandler(senv *StakingEnviroment, gas uint64) (re
 		// handle unbound first
 		if bkt.Unbounded == true {
 			// matured
-			if ts >= bkt.MatureTime+720 {
+			if ts >= bkt.MatureTime+params.UnboundGrace {
 				stakeholder := stakeholderList.Get(bkt.Owner)
 				if stakeholder != nil {
 					stakeholder.RemoveBucket(bkt)
@@ -691,6 +1020,22 @@ func (sb *StakingBody) GoverningHandler(senv *StakingEnviroment, gas uint64) (re
 		log.Debug("after handling", "bucket", bkt.ToString())
 	}
 
+	// apply pending commission changes, limited per epoch
+	var profileList *CandidateProfileList
+	if senv.IsForked(senv.GetForkConfig().CandidateProfile) {
+		profileList = staking.GetCandidateProfileList(state)
+		for _, p := range profileList.profiles {
+			c := candidateList.Get(p.Addr)
+			if c == nil || !p.Pending {
+				continue
+			}
+			if next := p.NextCommission(c.Commission, ts); next != c.Commission {
+				log.Info("candidate commission changed", "name", string(c.Name), "addr", c.Addr, "from", c.Commission, "to", next)
+				c.Commission = next
+			}
+		}
+	}
+
 	// handle delegateList
 	delegates := []*Delegate{}
 	for _, c := range candidateList.candidates {
@@ -721,9 +1066,8 @@ func (sb *StakingBody) GoverningHandler(senv *StakingEnviroment, gas uint64) (re
 				log.Info("get bucket from ID failed", "bucketID", bucketID)
 				continue
 			}
-			// amplify 1e09 because unit is shannon (1e09),  votes of bucket / votes of candidate * 1e09
-			shares := big.NewInt(1e09)
-			shares = shares.Mul(b.TotalVotes, shares)
+			// amplify by share scale, 1e09 by default as unit is shannon,  votes of bucket / votes of candidate * scale
+			shares := new(big.Int).Mul(b.TotalVotes, params.ShareScale)
 			shares = shares.Div(shares, c.TotalVotes)
 			delegate.DistList = append(delegate.DistList, NewDistributor(b.Owner, shares.Uint64()))
 		}
@@ -754,6 +1098,12 @@ func (sb *StakingBody) GoverningHandler(senv *StakingEnviroment, gas uint64) (re
 	staking.SetStakeHolderList(stakeholderList, state)
 	staking.SetDelegateList(delegateList, state)
 	staking.SetValidatorRewardList(rewardList, state)
+	if senv.IsForked(senv.GetForkConfig().StakingCompound) {
+		staking.SetCompoundList(compoundList, state)
+	}
+	if profileList != nil {
+		staking.SetCandidateProfileList(profileList, state)
+	}
 
 	log.Info("After Governing, new delegate list calculated", "members", delegateList.Members())
 	// fmt.Println(delegateList.ToString())
@@ -844,6 +1194,17 @@ func (sb *StakingBody) CandidateUpdateHandler(senv *StakingEnviroment, gas uint6
 		return
 	}
 
+	// metadata and deferred commission changes since the CandidateProfile fork
+	profiled := senv.IsForked(senv.GetForkConfig().CandidateProfile)
+	var meta *CandidateMeta
+	if profiled {
+		if meta, err = DecodeCandidateMeta(sb.ExtraData); err != nil {
+			return
+		}
+	}
+	profileList := staking.GetCandidateProfileList(state)
+	profile := profileList.GetOrNew(sb.CandAddr)
+
 	var changed bool
 	var pubUpdated, commissionUpdated, nameUpdated bool
 
@@ -854,7 +1215,8 @@ func (sb *StakingBody) CandidateUpdateHandler(senv *StakingEnviroment, gas uint6
 		nameUpdated = true
 	}
 	commission := GetCommissionRate(sb.Option)
-	if record.Commission != commission {
+	if (!profile.Pending && record.Commission != commission) ||
+		(profile.Pending && profile.PendingCommission != commission) {
 		commissionUpdated = true
 	}
 
@@ -871,7 +1233,12 @@ func (sb *StakingBody) CandidateUpdateHandler(senv *StakingEnviroment, gas uint6
 		changed = true
 	}
 	if commissionUpdated {
-		record.Commission = commission
+		if profiled {
+			// applied by governing, see CandidateProfile.NextCommission
+			profile.RequestCommission(record.Commission, commission, sb.Timestamp)
+		} else {
+			record.Commission = commission
+		}
 		changed = true
 	}
 	if nameUpdated {
@@ -888,6 +1255,12 @@ func (sb *StakingBody) CandidateUpdateHandler(senv *StakingEnviroment, gas uint6
 		record.Port = sb.CandPort
 		changed = true
 	}
+	if meta != nil {
+		if meta.Equal(&profile.Meta) == false {
+			profile.Meta = *meta
+			changed = true
+		}
+	}
 
 	if changed == false {
 		log.Warn("no candidate info changed")
@@ -895,6 +1268,12 @@ func (sb *StakingBody) CandidateUpdateHandler(senv *StakingEnviroment, gas uint6
 		return
 	}
 
+	if profiled {
+		if profile.IsEmpty() {
+			profileList.Remove(sb.CandAddr)
+		}
+		staking.SetCandidateProfileList(profileList, state)
+	}
 	staking.SetCandidateList(candidateList, state)
 	return
 }
@@ -1010,7 +1389,7 @@ func (sb *StakingBody) DelegateExitJailHandler(senv *StakingEnviroment, gas uint
 
 	if state.GetBalance(jailed.Addr).Cmp(jailed.BailAmount) < 0 {
 		log.Error("not enough balance for bail")
-		err = errors.New("not enough balance for bail")
+		err = errNotEnoughBail
 		return
 	}
 
@@ -6,6 +6,14 @@
 package staking
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/dfinlab/meter/builtin"
+	"github.com/dfinlab/meter/meter"
+	"github.com/dfinlab/meter/state"
 	"github.com/dfinlab/meter/types"
 )
 
@@ -35,6 +43,20 @@ const (
 	FOREVER_LOCK      = uint32(1000)
 	FOREVER_LOCK_RATE = FOUR_WEEK_LOCK_RATE
 	FOREVER_LOCK_TIME = uint64(0)
+
+	// matured unbound buckets are returned after this grace
+	UNBOUND_GRACE_TIME = uint64(720)
+	// votes of bucket / votes of candidate are scaled to shannon (1e09)
+	SHARE_SCALE = int64(1e09)
+)
+
+// limits of governed staking params
+const (
+	MAX_LOCK_RATE          = uint8(100)
+	MAX_LOCK_TIME          = uint64(60 * 60 * 24 * 365)
+	MAX_UNBOUND_GRACE_TIME = uint64(60 * 60 * 24 * 7)
+	MIN_SHARE_SCALE        = int64(1e06)
+	MAX_SHARE_SCALE        = int64(1e18)
 )
 
 func GetBoundLockOption(chose uint32) (opt uint32, rate uint8, locktime uint64) {
@@ -96,3 +118,178 @@ func GetCommissionRate(opt uint32) uint64 {
 	}
 	return commission
 }
+
+// lock options in the order of lock time, except the forever lock
+var lockOptions = []uint32{ONE_DAY_LOCK, ONE_WEEK_LOCK, TWO_WEEK_LOCK, THREE_WEEK_LOCK, FOUR_WEEK_LOCK}
+
+var lockOptionKeys = map[uint32]struct{ rate, time meter.Bytes32 }{
+	ONE_DAY_LOCK:    {meter.KeyStakingOneDayLockRate, meter.KeyStakingOneDayLockTime},
+	ONE_WEEK_LOCK:   {meter.KeyStakingOneWeekLockRate, meter.KeyStakingOneWeekLockTime},
+	TWO_WEEK_LOCK:   {meter.KeyStakingTwoWeekLockRate, meter.KeyStakingTwoWeekLockTime},
+	THREE_WEEK_LOCK: {meter.KeyStakingThreeWeekLockRate, meter.KeyStakingThreeWeekLockTime},
+	FOUR_WEEK_LOCK:  {meter.KeyStakingFourWeekLockRate, meter.KeyStakingFourWeekLockTime},
+}
+
+// StakingParams are the staking economics. Each one is governed by its key in
+// builtin.Params, the hard-coded default applies until the key is set by executor.
+type StakingParams struct {
+	LockRates    map[uint32]uint8
+	LockTimes    map[uint32]uint64
+	UnboundGrace uint64
+	ShareScale   *big.Int
+}
+
+// DefaultStakingParams returns the hard-coded staking params.
+func DefaultStakingParams() *StakingParams {
+	p := &StakingParams{
+		LockRates:    make(map[uint32]uint8),
+		LockTimes:    make(map[uint32]uint64),
+		UnboundGrace: UNBOUND_GRACE_TIME,
+		ShareScale:   big.NewInt(SHARE_SCALE),
+	}
+	for _, opt := range append(lockOptions, FOREVER_LOCK) {
+		_, p.LockRates[opt], p.LockTimes[opt] = GetBoundLockOption(opt)
+	}
+	return p
+}
+
+// stakingParamSetters apply the governed value of each key to the params, the
+// value is checked in range on its own.
+var stakingParamSetters = make(map[meter.Bytes32]func(p *StakingParams, v *big.Int) error)
+
+func init() {
+	rateSetter := func(opt uint32) func(p *StakingParams, v *big.Int) error {
+		return func(p *StakingParams, v *big.Int) error {
+			if !v.IsUint64() || v.Uint64() > uint64(MAX_LOCK_RATE) {
+				return fmt.Errorf("rate %v exceeds %v", v, MAX_LOCK_RATE)
+			}
+			p.LockRates[opt] = uint8(v.Uint64())
+			return nil
+		}
+	}
+	timeSetter := func(opt uint32) func(p *StakingParams, v *big.Int) error {
+		return func(p *StakingParams, v *big.Int) error {
+			if !v.IsUint64() || v.Uint64() == 0 || v.Uint64() > MAX_LOCK_TIME {
+				return fmt.Errorf("lock time %v out of range", v)
+			}
+			p.LockTimes[opt] = v.Uint64()
+			return nil
+		}
+	}
+	for opt, keys := range lockOptionKeys {
+		stakingParamSetters[keys.rate] = rateSetter(opt)
+		stakingParamSetters[keys.time] = timeSetter(opt)
+	}
+	stakingParamSetters[meter.KeyStakingForeverLockRate] = rateSetter(FOREVER_LOCK)
+	stakingParamSetters[meter.KeyStakingUnboundGrace] = func(p *StakingParams, v *big.Int) error {
+		if !v.IsUint64() || v.Uint64() > MAX_UNBOUND_GRACE_TIME {
+			return fmt.Errorf("unbound grace %v exceeds %v", v, MAX_UNBOUND_GRACE_TIME)
+		}
+		p.UnboundGrace = v.Uint64()
+		return nil
+	}
+	stakingParamSetters[meter.KeyStakingShareScale] = func(p *StakingParams, v *big.Int) error {
+		if v.Cmp(big.NewInt(MIN_SHARE_SCALE)) < 0 || v.Cmp(big.NewInt(MAX_SHARE_SCALE)) > 0 {
+			return fmt.Errorf("share scale %v out of range", v)
+		}
+		p.ShareScale = new(big.Int).Set(v)
+		return nil
+	}
+
+	for key := range stakingParamSetters {
+		builtin.RegisterParamValidator(key, ValidateStakingParam)
+	}
+}
+
+// GetStakingParams reads staking params from state. The hard-coded defaults apply
+// until governed is set. A governed value out of range falls back to its own
+// default, the others are kept.
+func GetStakingParams(state *state.State, governed bool) *StakingParams {
+	p := DefaultStakingParams()
+	if !governed {
+		return p
+	}
+	params := builtin.Params.Native(state)
+	for key, set := range stakingParamSetters {
+		v := params.Get(key)
+		if v.Sign() == 0 {
+			continue
+		}
+		if err := set(p, v); err != nil {
+			log.Warn("invalid staking param, use default", "key", string(bytes.TrimLeft(key[:], "\x00")), "error", err)
+		}
+	}
+	// values are validated together when set, only possible for values set
+	// before the params were governed
+	if err := p.Validate(); err != nil {
+		log.Warn("inconsistent staking params", "error", err)
+	}
+	return p
+}
+
+// ValidateStakingParam checks the value of staking param key before it's set,
+// together with the other governed params in state. Zero value unsets the key.
+func ValidateStakingParam(state *state.State, key meter.Bytes32, value *big.Int) error {
+	set, ok := stakingParamSetters[key]
+	if !ok {
+		return errors.New("not a staking param")
+	}
+	p := DefaultStakingParams()
+	params := builtin.Params.Native(state)
+	for k, s := range stakingParamSetters {
+		if k == key {
+			continue
+		}
+		if v := params.Get(k); v.Sign() != 0 {
+			s(p, v) // out of range ones keep defaults, as in GetStakingParams
+		}
+	}
+	if value.Sign() != 0 {
+		if err := set(p, value); err != nil {
+			return err
+		}
+	}
+	return p.Validate()
+}
+
+// Validate checks params are in range, and longer lock options have no less
+// lock time and rate.
+func (p *StakingParams) Validate() error {
+	for i, opt := range lockOptions {
+		rate, locktime := p.LockRates[opt], p.LockTimes[opt]
+		if rate > MAX_LOCK_RATE {
+			return fmt.Errorf("lock option %v: rate %v exceeds %v", opt, rate, MAX_LOCK_RATE)
+		}
+		if locktime == 0 || locktime > MAX_LOCK_TIME {
+			return fmt.Errorf("lock option %v: lock time %v out of range", opt, locktime)
+		}
+		if i > 0 {
+			prev := lockOptions[i-1]
+			if rate < p.LockRates[prev] || locktime < p.LockTimes[prev] {
+				return fmt.Errorf("lock option %v: less than lock option %v", opt, prev)
+			}
+		}
+	}
+	if p.LockRates[FOREVER_LOCK] > MAX_LOCK_RATE {
+		return fmt.Errorf("forever lock: rate %v exceeds %v", p.LockRates[FOREVER_LOCK], MAX_LOCK_RATE)
+	}
+	if p.UnboundGrace > MAX_UNBOUND_GRACE_TIME {
+		return fmt.Errorf("unbound grace %v exceeds %v", p.UnboundGrace, MAX_UNBOUND_GRACE_TIME)
+	}
+	if p.ShareScale == nil || p.ShareScale.Cmp(big.NewInt(MIN_SHARE_SCALE)) < 0 || p.ShareScale.Cmp(big.NewInt(MAX_SHARE_SCALE)) > 0 {
+		return errors.New("share scale out of range")
+	}
+	return nil
+}
+
+// LockOption is the governed version of GetBoundLockOption.
+func (p *StakingParams) LockOption(chose uint32) (opt uint32, rate uint8, locktime uint64) {
+	opt, _, _ = GetBoundLockOption(chose)
+	return opt, p.LockRates[opt], p.LockTimes[opt]
+}
+
+// Locktime is the governed version of GetBoundLocktime.
+func (p *StakingParams) Locktime(opt uint32) uint64 {
+	opt, _, _ = GetBoundLockOption(opt)
+	return p.LockTimes[opt]
+}
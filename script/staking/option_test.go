@@ -0,0 +1,108 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package staking_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/dfinlab/meter/builtin"
+	"github.com/dfinlab/meter/lvldb"
+	"github.com/dfinlab/meter/meter"
+	"github.com/dfinlab/meter/script/staking"
+	"github.com/dfinlab/meter/state"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultStakingParams(t *testing.T) {
+	p := staking.DefaultStakingParams()
+	assert.Nil(t, p.Validate())
+
+	for _, opt := range []uint32{staking.ONE_DAY_LOCK, staking.ONE_WEEK_LOCK, staking.TWO_WEEK_LOCK, staking.THREE_WEEK_LOCK, staking.FOUR_WEEK_LOCK, staking.FOREVER_LOCK, 12345} {
+		o, r, l := staking.GetBoundLockOption(opt)
+		po, pr, pl := p.LockOption(opt)
+		assert.Equal(t, o, po)
+		assert.Equal(t, r, pr)
+		assert.Equal(t, l, pl)
+		assert.Equal(t, staking.GetBoundLocktime(opt), p.Locktime(opt))
+	}
+	assert.Equal(t, staking.UNBOUND_GRACE_TIME, p.UnboundGrace)
+	assert.Equal(t, big.NewInt(staking.SHARE_SCALE).String(), p.ShareScale.String())
+}
+
+func TestStakingParamsValidate(t *testing.T) {
+	p := staking.DefaultStakingParams()
+	p.LockRates[staking.TWO_WEEK_LOCK] = 101
+	assert.NotNil(t, p.Validate(), "rate out of range")
+
+	p = staking.DefaultStakingParams()
+	p.LockRates[staking.ONE_WEEK_LOCK] = 7
+	assert.NotNil(t, p.Validate(), "longer lock with less rate")
+
+	p = staking.DefaultStakingParams()
+	p.LockTimes[staking.FOUR_WEEK_LOCK] = 60
+	assert.NotNil(t, p.Validate(), "longer lock with less lock time")
+
+	p = staking.DefaultStakingParams()
+	p.UnboundGrace = staking.MAX_UNBOUND_GRACE_TIME + 1
+	assert.NotNil(t, p.Validate())
+
+	p = staking.DefaultStakingParams()
+	p.ShareScale = big.NewInt(1000)
+	assert.NotNil(t, p.Validate())
+}
+
+func TestGetStakingParams(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+	st, _ := state.New(meter.Bytes32{}, kv)
+
+	p := staking.GetStakingParams(st, true)
+	assert.Equal(t, staking.DefaultStakingParams(), p, "defaults while unset")
+
+	params := builtin.Params.Native(st)
+	params.Set(meter.KeyStakingFourWeekLockRate, big.NewInt(10))
+	params.Set(meter.KeyStakingFourWeekLockTime, big.NewInt(60*60*24*30))
+	params.Set(meter.KeyStakingUnboundGrace, big.NewInt(3600))
+
+	assert.Equal(t, staking.DefaultStakingParams(), staking.GetStakingParams(st, false), "not governed yet")
+
+	p = staking.GetStakingParams(st, true)
+	opt, rate, locktime := p.LockOption(staking.FOUR_WEEK_LOCK)
+	assert.Equal(t, staking.FOUR_WEEK_LOCK, opt)
+	assert.Equal(t, uint8(10), rate)
+	assert.Equal(t, uint64(60*60*24*30), locktime)
+	assert.Equal(t, uint64(3600), p.UnboundGrace)
+	_, rate, _ = p.LockOption(staking.ONE_WEEK_LOCK)
+	assert.Equal(t, staking.ONE_WEEK_LOCK_RATE, rate, "unset keys keep defaults")
+
+	// invalid governed value falls back to its own default only
+	params.Set(meter.KeyStakingOneWeekLockRate, big.NewInt(200))
+	p = staking.GetStakingParams(st, true)
+	_, rate, _ = p.LockOption(staking.ONE_WEEK_LOCK)
+	assert.Equal(t, staking.ONE_WEEK_LOCK_RATE, rate)
+	_, rate, _ = p.LockOption(staking.FOUR_WEEK_LOCK)
+	assert.Equal(t, uint8(10), rate)
+	assert.Equal(t, uint64(3600), p.UnboundGrace)
+}
+
+func TestValidateStakingParam(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+	st, _ := state.New(meter.Bytes32{}, kv)
+	params := builtin.Params.Native(st)
+
+	assert.Nil(t, staking.ValidateStakingParam(st, meter.KeyStakingFourWeekLockRate, big.NewInt(10)))
+	assert.NotNil(t, staking.ValidateStakingParam(st, meter.KeyStakingFourWeekLockRate, big.NewInt(101)), "out of range")
+	assert.NotNil(t, staking.ValidateStakingParam(st, meter.KeyStakingOneWeekLockRate, big.NewInt(7)), "longer lock with less rate")
+	assert.NotNil(t, staking.ValidateStakingParam(st, meter.KeyStakingShareScale, big.NewInt(1000)))
+	assert.NotNil(t, staking.ValidateStakingParam(st, meter.KeyValidatorBaseReward, big.NewInt(1)), "not a staking param")
+
+	// checked against the params in state
+	assert.NotNil(t, staking.ValidateStakingParam(st, meter.KeyStakingThreeWeekLockRate, big.NewInt(9)))
+	params.Set(meter.KeyStakingFourWeekLockRate, big.NewInt(10))
+	assert.Nil(t, staking.ValidateStakingParam(st, meter.KeyStakingThreeWeekLockRate, big.NewInt(9)))
+	params.Set(meter.KeyStakingThreeWeekLockRate, big.NewInt(9))
+	assert.NotNil(t, staking.ValidateStakingParam(st, meter.KeyStakingFourWeekLockRate, big.NewInt(0)), "unset breaks the order")
+}
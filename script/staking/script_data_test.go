@@ -347,3 +347,60 @@ func TestDecode(t *testing.T) {
 	fmt.Println(sb.ToString())
 
 }
+
+func TestBucketAdd(t *testing.T) {
+	body := &staking.StakingBody{
+		Opcode:     staking.OP_BUCKET_ADD,
+		Version:    StakingVersion,
+		HolderAddr: HolderAddress,
+		StakingID:  StakingID,
+		Amount:     Amount,
+		Token:      staking.TOKEN_METER_GOV,
+		Timestamp:  Timestamp,
+		Nonce:      Nonce,
+	}
+	genScriptDataForStaking(body)
+}
+
+func TestBucketSplit(t *testing.T) {
+	body := &staking.StakingBody{
+		Opcode:     staking.OP_BUCKET_SPLIT,
+		Version:    StakingVersion,
+		HolderAddr: HolderAddress,
+		StakingID:  StakingID,
+		Amount:     Amount,
+		Token:      staking.TOKEN_METER_GOV,
+		Timestamp:  Timestamp,
+		Nonce:      Nonce,
+	}
+	genScriptDataForStaking(body)
+}
+
+func TestBucketMerge(t *testing.T) {
+	body := &staking.StakingBody{
+		Opcode:     staking.OP_BUCKET_MERGE,
+		Version:    StakingVersion,
+		HolderAddr: HolderAddress,
+		StakingID:  StakingID,
+		Amount:     big.NewInt(0),
+		Token:      staking.TOKEN_METER_GOV,
+		Timestamp:  Timestamp,
+		Nonce:      Nonce,
+		ExtraData:  AuctionID.Bytes(),
+	}
+	genScriptDataForStaking(body)
+}
+
+func TestCompound(t *testing.T) {
+	body := &staking.StakingBody{
+		Opcode:     staking.OP_COMPOUND,
+		Version:    StakingVersion,
+		HolderAddr: HolderAddress,
+		StakingID:  StakingID,
+		Amount:     big.NewInt(0),
+		Token:      staking.TOKEN_METER,
+		Timestamp:  Timestamp,
+		Nonce:      Nonce,
+	}
+	genScriptDataForStaking(body)
+}
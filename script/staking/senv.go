@@ -13,18 +13,20 @@ import (
 
 //
 type StakingEnviroment struct {
-	staking *Staking
-	state   *state.State
-	txCtx   *xenv.TransactionContext
-	toAddr  *meter.Address
+	staking  *Staking
+	state    *state.State
+	txCtx    *xenv.TransactionContext
+	blockCtx *xenv.BlockContext
+	toAddr   *meter.Address
 }
 
-func NewStakingEnviroment(staking *Staking, state *state.State, txCtx *xenv.TransactionContext, to *meter.Address) *StakingEnviroment {
+func NewStakingEnviroment(staking *Staking, state *state.State, txCtx *xenv.TransactionContext, blockCtx *xenv.BlockContext, to *meter.Address) *StakingEnviroment {
 	return &StakingEnviroment{
-		staking: staking,
-		state:   state,
-		txCtx:   txCtx,
-		toAddr:  to,
+		staking:  staking,
+		state:    state,
+		txCtx:    txCtx,
+		blockCtx: blockCtx,
+		toAddr:   to,
 	}
 }
 
@@ -32,3 +34,16 @@ func (senv *StakingEnviroment) GetStaking() *Staking               { return senv
 func (senv *StakingEnviroment) GetState() *state.State             { return senv.state }
 func (senv *StakingEnviroment) GetTxCtx() *xenv.TransactionContext { return senv.txCtx }
 func (senv *StakingEnviroment) GetToAddr() *meter.Address          { return senv.toAddr }
+func (senv *StakingEnviroment) GetBlockCtx() *xenv.BlockContext    { return senv.blockCtx }
+
+func (senv *StakingEnviroment) GetForkConfig() meter.ForkConfig { return senv.staking.forkConfig }
+
+// IsForked tells if the fork at height is active in the block being executed.
+func (senv *StakingEnviroment) IsForked(height uint32) bool {
+	return senv.blockCtx.Number >= height
+}
+
+// GetStakingParams returns the staking params in effect for the block being executed.
+func (senv *StakingEnviroment) GetStakingParams() *StakingParams {
+	return GetStakingParams(senv.state, senv.IsForked(senv.GetForkConfig().StakingParams))
+}
@@ -6,8 +6,6 @@
 package staking
 
 import (
-	"errors"
-
 	"github.com/dfinlab/meter/builtin"
 	"github.com/dfinlab/meter/chain"
 	"github.com/dfinlab/meter/meter"
@@ -34,6 +32,7 @@ var (
 type Staking struct {
 	chain        *chain.Chain
 	stateCreator *state.Creator
+	forkConfig   meter.ForkConfig
 }
 
 func GetStakingGlobInst() *Staking {
@@ -57,26 +56,35 @@ func NewStaking(ch *chain.Chain, sc *state.Creator) *Staking {
 		chain:        ch,
 		stateCreator: sc,
 	}
+	if ch != nil {
+		staking.forkConfig = meter.GetForkConfig(ch.GenesisBlock().Header().ID())
+	}
 	SetStakingGlobInst(staking)
 	return staking
 }
 
+// ForkConfig returns the fork config of the chain.
+func (s *Staking) ForkConfig() meter.ForkConfig {
+	return s.forkConfig
+}
+
 func (s *Staking) Start() error {
 	log.Info("staking module started")
 	return nil
 }
 
-func (s *Staking) PrepareStakingHandler() (StakingHandler func(data []byte, to *meter.Address, txCtx *xenv.TransactionContext, gas uint64, state *state.State) (ret []byte, leftOverGas uint64, err error)) {
+func (s *Staking) PrepareStakingHandler() (StakingHandler func(data []byte, to *meter.Address, txCtx *xenv.TransactionContext, blockCtx *xenv.BlockContext, gas uint64, state *state.State) (ret []byte, leftOverGas uint64, op uint32, err error)) {
 
-	StakingHandler = func(data []byte, to *meter.Address, txCtx *xenv.TransactionContext, gas uint64, state *state.State) (ret []byte, leftOverGas uint64, err error) {
+	StakingHandler = func(data []byte, to *meter.Address, txCtx *xenv.TransactionContext, blockCtx *xenv.BlockContext, gas uint64, state *state.State) (ret []byte, leftOverGas uint64, op uint32, err error) {
 
 		sb, err := StakingDecodeFromBytes(data)
 		if err != nil {
 			log.Error("Decode script message failed", "error", err)
-			return nil, gas, err
+			return nil, gas, op, err
 		}
+		op = sb.Opcode
 
-		senv := NewStakingEnviroment(s, state, txCtx, to)
+		senv := NewStakingEnviroment(s, state, txCtx, blockCtx, to)
 		if senv == nil {
 			panic("create staking enviroment failed")
 		}
@@ -94,62 +102,98 @@ func (s *Staking) PrepareStakingHandler() (StakingHandler func(data []byte, to *
 		switch sb.Opcode {
 		case OP_BOUND:
 			if senv.GetTxCtx().Origin != sb.HolderAddr {
-				return nil, gas, errors.New("holder address is not the same from transaction")
+				return nil, gas, op, errHolderNotOrigin
 			}
 
 			ret, leftOverGas, err = sb.BoundHandler(senv, gas)
 
 		case OP_UNBOUND:
 			if senv.GetTxCtx().Origin != sb.HolderAddr {
-				return nil, gas, errors.New("holder address is not the same from transaction")
+				return nil, gas, op, errHolderNotOrigin
 			}
 			ret, leftOverGas, err = sb.UnBoundHandler(senv, gas)
 
+		case OP_BUCKET_ADD:
+			if !senv.IsForked(senv.GetForkConfig().StakingBucketOps) {
+				return nil, gas, op, errUnknownOpcode
+			}
+			if senv.GetTxCtx().Origin != sb.HolderAddr {
+				return nil, gas, op, errHolderNotOrigin
+			}
+			ret, leftOverGas, err = sb.BucketAddHandler(senv, gas)
+
+		case OP_BUCKET_SPLIT:
+			if !senv.IsForked(senv.GetForkConfig().StakingBucketOps) {
+				return nil, gas, op, errUnknownOpcode
+			}
+			if senv.GetTxCtx().Origin != sb.HolderAddr {
+				return nil, gas, op, errHolderNotOrigin
+			}
+			ret, leftOverGas, err = sb.BucketSplitHandler(senv, gas)
+
+		case OP_BUCKET_MERGE:
+			if !senv.IsForked(senv.GetForkConfig().StakingBucketOps) {
+				return nil, gas, op, errUnknownOpcode
+			}
+			if senv.GetTxCtx().Origin != sb.HolderAddr {
+				return nil, gas, op, errHolderNotOrigin
+			}
+			ret, leftOverGas, err = sb.BucketMergeHandler(senv, gas)
+
+		case OP_COMPOUND:
+			if !senv.IsForked(senv.GetForkConfig().StakingCompound) {
+				return nil, gas, op, errUnknownOpcode
+			}
+			if senv.GetTxCtx().Origin != sb.HolderAddr {
+				return nil, gas, op, errHolderNotOrigin
+			}
+			ret, leftOverGas, err = sb.CompoundHandler(senv, gas)
+
 		case OP_CANDIDATE:
 			if senv.GetTxCtx().Origin != sb.CandAddr {
-				return nil, gas, errors.New("candidate address is not the same from transaction")
+				return nil, gas, op, errCandidateNotOrigin
 			}
 			ret, leftOverGas, err = sb.CandidateHandler(senv, gas)
 
 		case OP_UNCANDIDATE:
 			if senv.GetTxCtx().Origin != sb.CandAddr {
-				return nil, gas, errors.New("candidate address is not the same from transaction")
+				return nil, gas, op, errCandidateNotOrigin
 			}
 			ret, leftOverGas, err = sb.UnCandidateHandler(senv, gas)
 
 		case OP_DELEGATE:
 			if senv.GetTxCtx().Origin != sb.HolderAddr {
-				return nil, gas, errors.New("holder address is not the same from transaction")
+				return nil, gas, op, errHolderNotOrigin
 			}
 			ret, leftOverGas, err = sb.DelegateHandler(senv, gas)
 
 		case OP_UNDELEGATE:
 			if senv.GetTxCtx().Origin != sb.HolderAddr {
-				return nil, gas, errors.New("holder address is not the same from transaction")
+				return nil, gas, op, errHolderNotOrigin
 			}
 			ret, leftOverGas, err = sb.UnDelegateHandler(senv, gas)
 
 		case OP_GOVERNING:
 			if senv.GetToAddr().String() != StakingModuleAddr.String() {
-				return nil, gas, errors.New("to address is not the same from module address")
+				return nil, gas, op, errNotModuleAddress
 			}
 			ret, leftOverGas, err = sb.GoverningHandler(senv, gas)
 
 		case OP_CANDIDATE_UPDT:
 			if senv.GetTxCtx().Origin != sb.CandAddr {
-				return nil, gas, errors.New("candidate address is not the same from transaction")
+				return nil, gas, op, errCandidateNotOrigin
 			}
 			ret, leftOverGas, err = sb.CandidateUpdateHandler(senv, gas)
 
 		case OP_DELEGATE_STATISTICS:
 			if senv.GetToAddr().String() != StakingModuleAddr.String() {
-				return nil, gas, errors.New("to address is not the same from module address")
+				return nil, gas, op, errNotModuleAddress
 			}
 			ret, leftOverGas, err = sb.DelegateStatisticsHandler(senv, gas)
 
 		case OP_DELEGATE_EXITJAIL:
 			if senv.GetTxCtx().Origin != sb.CandAddr {
-				return nil, gas, errors.New("candidate address is not the same from transaction")
+				return nil, gas, op, errCandidateNotOrigin
 			}
 			ret, leftOverGas, err = sb.DelegateExitJailHandler(senv, gas)
 
@@ -157,13 +201,13 @@ func (s *Staking) PrepareStakingHandler() (StakingHandler func(data []byte, to *
 		case OP_FLUSH_ALL_STATISTICS:
 			executor := meter.BytesToAddress(builtin.Params.Native(state).Get(meter.KeyExecutorAddress).Bytes())
 			if senv.GetTxCtx().Origin != executor || sb.HolderAddr != executor {
-				return nil, gas, errors.New("only executor can exec this API")
+				return nil, gas, op, errNotExecutor
 			}
 			ret, leftOverGas, err = sb.DelegateStatisticsFlushHandler(senv, gas)
 
 		default:
 			log.Error("unknown Opcode", "Opcode", sb.Opcode)
-			return nil, gas, errors.New("unknow staking opcode")
+			return nil, gas, op, errUnknownOpcode
 		}
 		log.Debug("Leaving script handler for operation", "op", GetOpName(sb.Opcode))
 		return
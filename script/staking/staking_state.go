@@ -7,6 +7,7 @@ package staking
 
 import (
 	"bytes"
+	"encoding/binary"
 	"errors"
 	"math/big"
 	"strings"
@@ -28,6 +29,9 @@ var (
 	StatisticsEpochKey     = meter.Blake2b([]byte("delegate-statistics-epoch-key"))
 	InJailListKey          = meter.Blake2b([]byte("delegate-injail-list-key"))
 	ValidatorRewardListKey = meter.Blake2b([]byte("validator-reward-list-key"))
+	CompoundListKey        = meter.Blake2b([]byte("compound-list-key"))
+	CandidateProfileKey    = meter.Blake2b([]byte("candidate-profile-list-key"))
+	EpochRewardsKeyPrefix  = []byte("validator-epoch-rewards-key")
 )
 
 // Candidate List
@@ -273,6 +277,72 @@ func (s *Staking) SetInJailList(list *DelegateInJailList, state *state.State) {
 	})
 }
 
+// candidate profile List
+func (s *Staking) GetCandidateProfileList(state *state.State) (result *CandidateProfileList) {
+	state.DecodeStorage(StakingModuleAddr, CandidateProfileKey, func(raw []byte) error {
+		profiles := make([]*CandidateProfile, 0)
+
+		if len(strings.TrimSpace(string(raw))) >= 0 {
+			err := rlp.Decode(bytes.NewReader(raw), &profiles)
+			if err != nil {
+				if err.Error() == "EOF" && len(raw) == 0 {
+					// EOF is caused by no value, is not error case, so returns with empty slice
+				} else {
+					log.Warn("Error during decoding candidate profile list.", "err", err)
+					return err
+				}
+			}
+		}
+
+		result = NewCandidateProfileList(profiles)
+		return nil
+	})
+	return
+}
+
+// SetCandidateProfileList saves the list, the storage is cleared if the list is empty.
+func (s *Staking) SetCandidateProfileList(list *CandidateProfileList, state *state.State) {
+	state.EncodeStorage(StakingModuleAddr, CandidateProfileKey, func() ([]byte, error) {
+		if len(list.profiles) == 0 {
+			return nil, nil
+		}
+		return rlp.EncodeToBytes(list.profiles)
+	})
+}
+
+// compound List
+func (s *Staking) GetCompoundList(state *state.State) (result *CompoundList) {
+	state.DecodeStorage(StakingModuleAddr, CompoundListKey, func(raw []byte) error {
+		compounds := make([]*Compound, 0)
+
+		if len(strings.TrimSpace(string(raw))) >= 0 {
+			err := rlp.Decode(bytes.NewReader(raw), &compounds)
+			if err != nil {
+				if err.Error() == "EOF" && len(raw) == 0 {
+					// EOF is caused by no value, is not error case, so returns with empty slice
+				} else {
+					log.Warn("Error during decoding compound list.", "err", err)
+					return err
+				}
+			}
+		}
+
+		result = NewCompoundList(compounds)
+		return nil
+	})
+	return
+}
+
+// SetCompoundList saves the list, the storage is cleared if the list is empty.
+func (s *Staking) SetCompoundList(list *CompoundList, state *state.State) {
+	state.EncodeStorage(StakingModuleAddr, CompoundListKey, func() ([]byte, error) {
+		if len(list.compounds) == 0 {
+			return nil, nil
+		}
+		return rlp.EncodeToBytes(list.compounds)
+	})
+}
+
 // validator reward list
 func (s *Staking) GetValidatorRewardList(state *state.State) (result *ValidatorRewardList) {
 	state.DecodeStorage(StakingModuleAddr, ValidatorRewardListKey, func(raw []byte) error {
@@ -308,6 +378,36 @@ func (s *Staking) SetValidatorRewardList(list *ValidatorRewardList, state *state
 	})
 }
 
+// epoch rewards, one storage slot per epoch
+func epochRewardsKey(epoch uint32) meter.Bytes32 {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], epoch)
+	return meter.Blake2b(EpochRewardsKeyPrefix, b[:])
+}
+
+// GetEpochRewards returns nil if nothing distributed in the epoch.
+func (s *Staking) GetEpochRewards(epoch uint32, state *state.State) (result *EpochRewards) {
+	state.DecodeStorage(StakingModuleAddr, epochRewardsKey(epoch), func(raw []byte) error {
+		if len(raw) == 0 {
+			return nil
+		}
+		rewards := &EpochRewards{}
+		if err := rlp.DecodeBytes(raw, rewards); err != nil {
+			log.Warn("Error during decoding epoch rewards.", "err", err)
+			return err
+		}
+		result = rewards
+		return nil
+	})
+	return
+}
+
+func (s *Staking) SetEpochRewards(rewards *EpochRewards, state *state.State) {
+	state.EncodeStorage(StakingModuleAddr, epochRewardsKey(rewards.Reward.Epoch), func() ([]byte, error) {
+		return rlp.EncodeToBytes(rewards)
+	})
+}
+
 //==================== bound/unbound account ===========================
 func (s *Staking) BoundAccountMeter(addr meter.Address, amount *big.Int, state *state.State) error {
 	if amount.Sign() == 0 {
@@ -320,7 +420,7 @@ func (s *Staking) BoundAccountMeter(addr meter.Address, amount *big.Int, state *
 	// meterBalance should >= amount
 	if meterBalance.Cmp(amount) == -1 {
 		log.Error("not enough meter balance", "account", addr, "bound amount", amount)
-		return errors.New("not enough meter balance")
+		return errNotEnoughMeterBalance
 	}
 
 	state.SetEnergy(addr, new(big.Int).Sub(meterBalance, amount))
@@ -339,7 +439,7 @@ func (s *Staking) UnboundAccountMeter(addr meter.Address, amount *big.Int, state
 	// meterBoundedBalance should >= amount
 	if meterBoundedBalance.Cmp(amount) < 0 {
 		log.Error("not enough bounded meter balance", "account", addr, "unbound amount", amount)
-		return errors.New("not enough bounded meter balance")
+		return errNotEnoughBoundedMeter
 	}
 
 	state.SetEnergy(addr, new(big.Int).Add(meterBalance, amount))
@@ -360,7 +460,7 @@ func (s *Staking) BoundAccountMeterGov(addr meter.Address, amount *big.Int, stat
 	// meterGov should >= amount
 	if meterGov.Cmp(amount) == -1 {
 		log.Error("not enough meter-gov balance", "account", addr, "bound amount", amount)
-		return errors.New("not enough meter-gov balance")
+		return errNotEnoughMeterGov
 	}
 
 	state.SetBalance(addr, new(big.Int).Sub(meterGov, amount))
@@ -380,7 +480,7 @@ func (s *Staking) UnboundAccountMeterGov(addr meter.Address, amount *big.Int, st
 	// meterGovBounded should >= amount
 	if meterGovBounded.Cmp(amount) < 0 {
 		log.Error("not enough bounded meter-gov balance", "account", addr, "unbound amount", amount)
-		return errors.New("not enough bounded meter-gov balance")
+		return errNotEnoughBoundedMTRG
 	}
 
 	state.SetBalance(addr, new(big.Int).Add(meterGov, amount))
@@ -397,7 +497,7 @@ func (s *Staking) CollectBailMeterGov(addr meter.Address, amount *big.Int, state
 	meterGov := state.GetBalance(addr)
 	if meterGov.Cmp(amount) < 0 {
 		log.Error("not enough bounded meter-gov balance", "account", addr)
-		return errors.New("not enough meter-gov balance")
+		return errNotEnoughMeterGov
 	}
 
 	state.AddBalance(StakingModuleAddr, amount)
@@ -425,8 +525,15 @@ func (s *Staking) TransferValidatorReward(amount *big.Int, addr meter.Address, s
 //2. get the propotion reward for each validator based on the votingpower
 //3. each validator takes commission first
 //4. finally, distributor takes their propotions of rest
-func (s *Staking) DistValidatorRewards(amount *big.Int, validators []*meter.Address, list *DelegateList, state *state.State) (*big.Int, []*RewardInfo, error) {
+// DistValidatorRewards distributes amount to validators and their distributors.
+// Shares of distributors are in unit of scale. legacy is set before the
+// ValidatorRewardHistory fork, to sum the rewards as it was.
+func (s *Staking) DistValidatorRewards(amount *big.Int, validators []*meter.Address, list *DelegateList, scale *big.Int, state *state.State, legacy bool) (*big.Int, []*RewardInfo, error) {
 	rewardMap := RewardInfoMap{}
+	addReward := rewardMap.Add
+	if legacy {
+		addReward = rewardMap.addRef
+	}
 	delegatesMap := make(map[meter.Address]*Delegate)
 	for _, d := range list.delegates {
 		delegatesMap[d.Address] = d
@@ -456,7 +563,7 @@ func (s *Staking) DistValidatorRewards(amount *big.Int, validators []*meter.Addr
 			continue
 		}
 		s.TransferValidatorReward(baseReward, delegate.Address, state)
-		rewardMap.Add(baseReward, delegate.Address)
+		addReward(baseReward, delegate.Address)
 	}
 	if baseRewardsOnly == true {
 		// only cover validator base rewards
@@ -492,7 +599,7 @@ func (s *Staking) DistValidatorRewards(amount *big.Int, validators []*meter.Addr
 		commission = commission.Mul(eachReward, big.NewInt(int64(delegate.Commission)))
 		commission = commission.Div(commission, big.NewInt(1e09))
 		s.TransferValidatorReward(commission, delegate.Address, state)
-		rewardMap.Add(commission, delegate.Address)
+		addReward(commission, delegate.Address)
 
 		actualReward := new(big.Int).Sub(eachReward, commission)
 
@@ -501,12 +608,22 @@ func (s *Staking) DistValidatorRewards(amount *big.Int, validators []*meter.Addr
 			// no distributor, 100% goes to benefiicary
 			s.TransferValidatorReward(actualReward, delegate.Address, state)
 		} else {
-			// as percentage to each distributor， the unit of Shares is shannon， ie， 1e09
+			// as percentage to each distributor， the unit of Shares is the share scale， 1e09 by default.
+			// shares are calculated in last governing, never distribute more than actualReward if
+			// the scale is lowered since then.
+			shareScale := new(big.Int).Set(scale)
+			shareSum := new(big.Int)
+			for _, dist := range delegate.DistList {
+				shareSum.Add(shareSum, new(big.Int).SetUint64(dist.Shares))
+			}
+			if shareSum.Cmp(shareScale) > 0 {
+				shareScale = shareSum
+			}
 			for _, dist := range delegate.DistList {
-				distReward = new(big.Int).Mul(actualReward, big.NewInt(int64(dist.Shares)))
-				distReward = distReward.Div(distReward, big.NewInt(1e09))
+				distReward = new(big.Int).Mul(actualReward, new(big.Int).SetUint64(dist.Shares))
+				distReward = distReward.Div(distReward, shareScale)
 				s.TransferValidatorReward(distReward, dist.Address, state)
-				rewardMap.Add(distReward, dist.Address)
+				addReward(distReward, dist.Address)
 			}
 		}
 	}
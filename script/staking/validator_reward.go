@@ -18,6 +18,9 @@ import (
 
 const (
 	STAKING_MAX_VALIDATOR_REWARDS = 1200
+
+	// max epochs could be scanned by one reward history query
+	STAKING_MAX_REWARD_HISTORY_EPOCHS = 1200
 )
 
 type RewardInfo struct {
@@ -93,10 +96,58 @@ func (v *ValidatorRewardList) ToList() []*ValidatorReward {
 	return result
 }
 
+// EpochRewards is the complete distribution of an epoch, it's kept forever
+// while ValidatorRewardList only keeps the latest epochs.
+type EpochRewards struct {
+	Reward  *ValidatorReward
+	Rewards []*RewardInfo
+}
+
+func (e *EpochRewards) Get(addr meter.Address) *RewardInfo {
+	for _, r := range e.Rewards {
+		if r.Address == addr {
+			return r
+		}
+	}
+	return nil
+}
+
+func (e *EpochRewards) ToString() string {
+	s := []string{fmt.Sprintf("EpochRewards(Epoch %v) (size:%v) {", e.Reward.Epoch, len(e.Rewards))}
+	for i, r := range e.Rewards {
+		s = append(s, fmt.Sprintf("  %d.Address=%v Amount=%v", i, r.Address, r.Amount.String()))
+	}
+	s = append(s, "}")
+	return strings.Join(s, "\n")
+}
+
+// AddressReward is the reward of an address in an epoch
+type AddressReward struct {
+	Epoch  uint32
+	Amount *big.Int
+}
+
 //// RewardInfoMap
 type RewardInfoMap map[meter.Address]*RewardInfo
 
 func (rmap RewardInfoMap) Add(amount *big.Int, addr meter.Address) error {
+	info, ok := rmap[addr]
+	if ok == true {
+		info.Amount = info.Amount.Add(info.Amount, amount)
+	} else {
+		// amount is reused by the caller, never keep the reference
+		rmap[addr] = &RewardInfo{
+			Address: addr,
+			Amount:  new(big.Int).Set(amount),
+		}
+	}
+	return nil
+}
+
+// addRef is Add before the ValidatorRewardHistory fork, it keeps the reference
+// of amount, so the amounts summed later are changed by the caller reusing it.
+// It's kept to replay the history.
+func (rmap RewardInfoMap) addRef(amount *big.Int, addr meter.Address) error {
 	info, ok := rmap[addr]
 	if ok == true {
 		info.Amount = info.Amount.Add(info.Amount, amount)
@@ -143,3 +194,66 @@ func GetLatestValidatorRewardList() (*ValidatorRewardList, error) {
 	// fmt.Println("delegateList from state", list.ToString())
 	return list, nil
 }
+
+func GetEpochRewards(epoch uint32) (*EpochRewards, error) {
+	staking := GetStakingGlobInst()
+	if staking == nil {
+		log.Warn("staking is not initialized...")
+		err := errors.New("staking is not initialized...")
+		return nil, err
+	}
+
+	best := staking.chain.BestBlock()
+	state, err := staking.stateCreator.NewState(best.Header().StateRoot())
+	if err != nil {
+		return nil, err
+	}
+
+	return staking.GetEpochRewards(epoch, state), nil
+}
+
+// GetAddressRewards returns the rewards of addr in epochs [from, to]. If to is
+// zero, it's the latest distributed epoch. At most STAKING_MAX_REWARD_HISTORY_EPOCHS
+// epochs are scanned, next is the epoch to continue from if the range is not
+// completed, zero otherwise.
+func GetAddressRewards(addr meter.Address, from, to uint32) (rewards []*AddressReward, next uint32, err error) {
+	staking := GetStakingGlobInst()
+	if staking == nil {
+		log.Warn("staking is not initialized...")
+		return nil, 0, errors.New("staking is not initialized...")
+	}
+
+	best := staking.chain.BestBlock()
+	state, err := staking.stateCreator.NewState(best.Header().StateRoot())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if to == 0 {
+		list := staking.GetValidatorRewardList(state)
+		if list.Count() == 0 {
+			return []*AddressReward{}, 0, nil
+		}
+		to = list.rewards[list.Count()-1].Epoch
+	}
+	if from > to {
+		return nil, 0, errors.New("from is greater than to")
+	}
+	if to-from >= STAKING_MAX_REWARD_HISTORY_EPOCHS {
+		to = from + STAKING_MAX_REWARD_HISTORY_EPOCHS - 1
+		next = to + 1
+	}
+
+	rewards = make([]*AddressReward, 0)
+	for epoch := from; ; epoch++ {
+		if e := staking.GetEpochRewards(epoch, state); e != nil {
+			if r := e.Get(addr); r != nil {
+				rewards = append(rewards, &AddressReward{Epoch: epoch, Amount: r.Amount})
+			}
+		}
+		if epoch == to {
+			break
+		}
+	}
+	return rewards, next, nil
+}
@@ -52,3 +52,18 @@ func TestRewardMapToList(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestRewardMapAddCopiesAmount(t *testing.T) {
+	a := meter.MustParseAddress("0xf3dd5c55b96889369f714143f213403464a268a6")
+	b := meter.MustParseAddress("0xd1186074257f1a6f231c415cdaf7e1f4ae48d51f")
+
+	rewardMap := staking.RewardInfoMap{}
+	amount := big.NewInt(100)
+	rewardMap.Add(amount, a)
+	rewardMap.Add(amount, b)
+	rewardMap.Add(amount, a)
+
+	if rewardMap[a].Amount.Int64() != 200 || rewardMap[b].Amount.Int64() != 100 || amount.Int64() != 100 {
+		t.Fatalf("unexpected amounts a=%v b=%v amount=%v", rewardMap[a].Amount, rewardMap[b].Amount, amount)
+	}
+}
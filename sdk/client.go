@@ -0,0 +1,225 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package sdk
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dfinlab/meter/api/accounts"
+	"github.com/dfinlab/meter/meter"
+	"github.com/dfinlab/meter/script/staking"
+	"github.com/dfinlab/meter/tx"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
+)
+
+const (
+	// transactions expire after this many blocks if not packed
+	txExpiration = uint32(720)
+
+	// percentage of the estimated gas added, in case state changes before the tx is packed
+	gasMarginPercent = 20
+)
+
+type blockSummary struct {
+	Number uint32        `json:"number"`
+	ID     meter.Bytes32 `json:"id"`
+}
+
+type rawTx struct {
+	Raw string `json:"raw"`
+}
+
+type txResponse struct {
+	ID meter.Bytes32 `json:"id"`
+}
+
+// Client talks to the restful API of a node.
+type Client struct {
+	url    string
+	client *http.Client
+}
+
+// NewClient creates client of the API served at url, e.g. http://localhost:8669.
+func NewClient(url string) *Client {
+	return &Client{
+		url:    strings.TrimSuffix(url, "/"),
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// ChainTag returns the chain tag, the last byte of the genesis ID.
+func (c *Client) ChainTag() (byte, error) {
+	var genesis blockSummary
+	if err := c.get("/blocks/0", &genesis); err != nil {
+		return 0, err
+	}
+	return genesis.ID[31], nil
+}
+
+// GetBucket returns the staking bucket by ID.
+func (c *Client) GetBucket(id meter.Bytes32) (*staking.Bucket, error) {
+	var bucket *staking.Bucket
+	if err := c.get("/staking/buckets/"+id.String(), &bucket); err != nil {
+		return nil, err
+	}
+	if bucket == nil {
+		return nil, errors.Errorf("bucket %v not found", id)
+	}
+	return bucket, nil
+}
+
+// Simulate executes the clauses on the best block as sent by caller, without
+// sending any transaction. It fails if any clause reverts.
+func (c *Client) Simulate(caller meter.Address, clauses ...*tx.Clause) (accounts.BatchCallResults, error) {
+	call := &accounts.BatchCallData{Caller: &caller}
+	for _, cl := range clauses {
+		call.Clauses = append(call.Clauses, accounts.Clause{
+			To:    cl.To(),
+			Value: (*math.HexOrDecimal256)(cl.Value()),
+			Data:  hexutil.Encode(cl.Data()),
+			Token: cl.Token(),
+		})
+	}
+
+	var results accounts.BatchCallResults
+	if err := c.post("/accounts/*", call, &results); err != nil {
+		return nil, err
+	}
+	for i, r := range results {
+		if r.Reverted {
+			if se := r.ScriptError; se != nil {
+				return results, errors.Errorf("clause #%v reverted: %v %v error %v: %v", i, se.Module, se.Op, se.Code, se.Message)
+			}
+			return results, errors.Errorf("clause #%v reverted: %v", i, r.VMError)
+		}
+	}
+	return results, nil
+}
+
+// Build simulates the clauses and builds the transaction with the estimated gas
+// plus a margin.
+func (c *Client) Build(caller meter.Address, clauses ...*tx.Clause) (*tx.Transaction, error) {
+	results, err := c.Simulate(caller, clauses...)
+	if err != nil {
+		return nil, err
+	}
+	gas, err := tx.IntrinsicGas(clauses...)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range results {
+		gas += r.GasUsed
+	}
+	gas += gas * gasMarginPercent / 100
+
+	// the nonce must differ across runs, or identical txs collide on tx ID
+	var nonce [8]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return nil, err
+	}
+
+	chainTag, err := c.ChainTag()
+	if err != nil {
+		return nil, err
+	}
+	var best blockSummary
+	if err := c.get("/blocks/best", &best); err != nil {
+		return nil, err
+	}
+
+	builder := new(tx.Builder)
+	builder.ChainTag(chainTag).
+		BlockRef(tx.NewBlockRefFromID(best.ID)).
+		Expiration(txExpiration).
+		GasPriceCoef(0).
+		Gas(gas).
+		DependsOn(nil).
+		Nonce(binary.BigEndian.Uint64(nonce[:]))
+	for _, cl := range clauses {
+		builder.Clause(cl)
+	}
+	return builder.Build(), nil
+}
+
+// Send sends the signed transaction and returns its ID.
+func (c *Client) Send(trx *tx.Transaction) (meter.Bytes32, error) {
+	data, err := rlp.EncodeToBytes(trx)
+	if err != nil {
+		return meter.Bytes32{}, err
+	}
+	var res txResponse
+	if err := c.post("/transactions", &rawTx{Raw: hexutil.Encode(data)}, &res); err != nil {
+		return meter.Bytes32{}, err
+	}
+	return res.ID, nil
+}
+
+// Transact builds the transaction of the clauses, signs it with key and sends it.
+func (c *Client) Transact(key *ecdsa.PrivateKey, clauses ...*tx.Clause) (meter.Bytes32, error) {
+	trx, err := c.Build(meter.Address(crypto.PubkeyToAddress(key.PublicKey)), clauses...)
+	if err != nil {
+		return meter.Bytes32{}, err
+	}
+	trx, err = Sign(trx, key)
+	if err != nil {
+		return meter.Bytes32{}, err
+	}
+	return c.Send(trx)
+}
+
+// Sign signs the transaction with key.
+func Sign(trx *tx.Transaction, key *ecdsa.PrivateKey) (*tx.Transaction, error) {
+	sig, err := crypto.Sign(trx.SigningHash().Bytes(), key)
+	if err != nil {
+		return nil, err
+	}
+	return trx.WithSignature(sig), nil
+}
+
+func (c *Client) get(path string, v interface{}) error {
+	resp, err := c.client.Get(c.url + path)
+	if err != nil {
+		return errors.WithMessage(err, "meter api")
+	}
+	defer resp.Body.Close()
+	return readResponse(resp, v)
+}
+
+func (c *Client) post(path string, body interface{}, v interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	resp, err := c.client.Post(c.url+path, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return errors.WithMessage(err, "meter api")
+	}
+	defer resp.Body.Close()
+	return readResponse(resp, v)
+}
+
+func readResponse(resp *http.Response, v interface{}) error {
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("meter api: %v %v", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return json.Unmarshal(body, v)
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+// Package sdk builds, signs and sends the script engine transactions of
+// staking and auction, so clients don't have to assemble the encoding by hand.
+package sdk
+
+import (
+	"math/big"
+	"math/rand"
+	"time"
+
+	"github.com/dfinlab/meter/meter"
+	"github.com/dfinlab/meter/script"
+	"github.com/dfinlab/meter/script/auction"
+	"github.com/dfinlab/meter/script/staking"
+	"github.com/dfinlab/meter/tx"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// scriptPrefix is skipped by the runtime before the script pattern
+var scriptPrefix = []byte{0xff, 0xff, 0xff, 0xff}
+
+// EncodeScript encodes the body of module modID as clause data.
+func EncodeScript(modID uint32, body interface{}) ([]byte, error) {
+	payload, err := rlp.EncodeToBytes(body)
+	if err != nil {
+		return nil, err
+	}
+	s := &script.Script{
+		Header: script.ScriptHeader{
+			Version: uint32(0),
+			ModID:   modID,
+		},
+		Payload: payload,
+	}
+	data, err := rlp.EncodeToBytes(s)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]byte, 0, len(scriptPrefix)+len(script.ScriptPattern)+len(data))
+	ret = append(ret, scriptPrefix...)
+	ret = append(ret, script.ScriptPattern[:]...)
+	return append(ret, data...), nil
+}
+
+// StakingClause returns the clause executing the staking body.
+func StakingClause(body *staking.StakingBody) (*tx.Clause, error) {
+	data, err := EncodeScript(script.STAKING_MODULE_ID, body)
+	if err != nil {
+		return nil, err
+	}
+	return tx.NewClause(&staking.StakingModuleAddr).WithValue(big.NewInt(0)).WithToken(tx.TOKEN_METER_GOV).WithData(data), nil
+}
+
+// AuctionClause returns the clause executing the auction body.
+func AuctionClause(body *auction.AuctionBody) (*tx.Clause, error) {
+	data, err := EncodeScript(script.AUCTION_MODULE_ID, body)
+	if err != nil {
+		return nil, err
+	}
+	return tx.NewClause(&auction.AuctionAccountAddr).WithValue(big.NewInt(0)).WithToken(tx.TOKEN_METER_GOV).WithData(data), nil
+}
+
+func newStakingBody(op uint32, holder meter.Address) *staking.StakingBody {
+	return &staking.StakingBody{
+		Opcode:     op,
+		HolderAddr: holder,
+		Amount:     big.NewInt(0),
+		Timestamp:  uint64(time.Now().Unix()),
+		Nonce:      rand.Uint64(),
+	}
+}
+
+// Bound creates a bucket of amount with the lock option, voting for candidate
+// if it's not zero.
+func Bound(holder, candidate meter.Address, amount *big.Int, token byte, option uint32) *staking.StakingBody {
+	sb := newStakingBody(staking.OP_BOUND, holder)
+	sb.CandAddr = candidate
+	sb.Amount = amount
+	sb.Token = token
+	sb.Option = option
+	return sb
+}
+
+// Unbound unbounds the bucket, amount and token have to match the bucket.
+func Unbound(holder meter.Address, bucketID meter.Bytes32, amount *big.Int, token byte) *staking.StakingBody {
+	sb := newStakingBody(staking.OP_UNBOUND, holder)
+	sb.StakingID = bucketID
+	sb.Amount = amount
+	sb.Token = token
+	return sb
+}
+
+// Candidate lists addr as candidate with a forever locked bucket of amount.
+// The commission is in the unit of 1e09, 1e07 is 1%. meta could be nil.
+func Candidate(addr meter.Address, name, pubKey, ip string, port uint16, amount *big.Int, token byte,
+	commission uint32, meta *staking.CandidateMeta) (*staking.StakingBody, error) {
+	sb := newStakingBody(staking.OP_CANDIDATE, addr)
+	sb.CandAddr = addr
+	sb.CandName = []byte(name)
+	sb.CandPubKey = []byte(pubKey)
+	sb.CandIP = []byte(ip)
+	sb.CandPort = port
+	sb.Amount = amount
+	sb.Token = token
+	sb.Option = commission
+	if meta != nil {
+		extra, err := rlp.EncodeToBytes(meta)
+		if err != nil {
+			return nil, err
+		}
+		sb.ExtraData = extra
+	}
+	return sb, nil
+}
+
+// Delegate votes for candidate with the bucket, amount and token have to match
+// the bucket.
+func Delegate(holder, candidate meter.Address, bucketID meter.Bytes32, amount *big.Int, token byte) *staking.StakingBody {
+	sb := newStakingBody(staking.OP_DELEGATE, holder)
+	sb.CandAddr = candidate
+	sb.StakingID = bucketID
+	sb.Amount = amount
+	sb.Token = token
+	return sb
+}
+
+// Undelegate withdraws the vote of the bucket, amount and token have to match
+// the bucket.
+func Undelegate(holder meter.Address, bucketID meter.Bytes32, amount *big.Int, token byte) *staking.StakingBody {
+	sb := newStakingBody(staking.OP_UNDELEGATE, holder)
+	sb.StakingID = bucketID
+	sb.Amount = amount
+	sb.Token = token
+	return sb
+}
+
+// Bid bids amount of MTR in the current auction. The nonce identifies the bid
+// for cancelling.
+func Bid(bidder meter.Address, amount *big.Int) *auction.AuctionBody {
+	return &auction.AuctionBody{
+		Opcode:        auction.OP_BID,
+		Bidder:        bidder,
+		Amount:        amount,
+		ReserveAmount: big.NewInt(0),
+		Token:         auction.TOKEN_METER,
+		Timestamp:     uint64(time.Now().Unix()),
+		Nonce:         rand.Uint64(),
+	}
+}
+
+// CancelBid cancels the bid with nonce in the auction.
+func CancelBid(bidder meter.Address, auctionID meter.Bytes32, nonce uint64) *auction.AuctionBody {
+	return &auction.AuctionBody{
+		Opcode:        auction.OP_CANCEL,
+		AuctionID:     auctionID,
+		Bidder:        bidder,
+		Amount:        big.NewInt(0),
+		ReserveAmount: big.NewInt(0),
+		Token:         auction.TOKEN_METER,
+		Timestamp:     uint64(time.Now().Unix()),
+		Nonce:         nonce,
+	}
+}
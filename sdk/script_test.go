@@ -0,0 +1,74 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package sdk_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/dfinlab/meter/meter"
+	"github.com/dfinlab/meter/script"
+	"github.com/dfinlab/meter/script/auction"
+	"github.com/dfinlab/meter/script/staking"
+	"github.com/dfinlab/meter/sdk"
+	"github.com/dfinlab/meter/tx"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	holder    = meter.BytesToAddress([]byte("holder"))
+	candidate = meter.BytesToAddress([]byte("candidate"))
+)
+
+func TestStakingClause(t *testing.T) {
+	body := sdk.Bound(holder, candidate, big.NewInt(1e18), staking.TOKEN_METER_GOV, staking.ONE_WEEK_LOCK)
+	clause, err := sdk.StakingClause(body)
+	assert.Nil(t, err)
+	assert.Equal(t, staking.StakingModuleAddr, *clause.To())
+	assert.Equal(t, 0, clause.Value().Sign(), "script clause carries no value")
+
+	data := clause.Data()
+	assert.Equal(t, []byte{0xff, 0xff, 0xff, 0xff}, data[:4])
+	assert.Equal(t, script.ScriptPattern[:], data[4:8])
+
+	s, err := script.ScriptDecodeFromBytes(data[8:])
+	assert.Nil(t, err)
+	assert.Equal(t, script.STAKING_MODULE_ID, s.Header.ModID)
+	sb, err := staking.StakingDecodeFromBytes(s.Payload)
+	assert.Nil(t, err)
+	assert.Equal(t, staking.OP_BOUND, sb.Opcode)
+	assert.Equal(t, candidate, sb.CandAddr)
+	assert.Equal(t, body.Nonce, sb.Nonce)
+}
+
+func TestAuctionClause(t *testing.T) {
+	id := meter.BytesToBytes32([]byte("auction"))
+	clause, err := sdk.AuctionClause(sdk.CancelBid(holder, id, 42))
+	assert.Nil(t, err)
+	assert.Equal(t, auction.AuctionAccountAddr, *clause.To())
+
+	s, err := script.ScriptDecodeFromBytes(clause.Data()[8:])
+	assert.Nil(t, err)
+	assert.Equal(t, script.AUCTION_MODULE_ID, s.Header.ModID)
+	ab, err := auction.AuctionDecodeFromBytes(s.Payload)
+	assert.Nil(t, err)
+	assert.Equal(t, auction.OP_CANCEL, ab.Opcode)
+	assert.Equal(t, id, ab.AuctionID)
+	assert.Equal(t, uint64(42), ab.Nonce)
+}
+
+func TestSign(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	clause, _ := sdk.StakingClause(sdk.Undelegate(holder, meter.Bytes32{}, big.NewInt(1e18), staking.TOKEN_METER_GOV))
+	trx := new(tx.Builder).ChainTag(1).Gas(100000).Clause(clause).Build()
+
+	trx, err := sdk.Sign(trx, key)
+	assert.Nil(t, err)
+	signer, err := trx.Signer()
+	assert.Nil(t, err)
+	assert.Equal(t, meter.Address(crypto.PubkeyToAddress(key.PublicKey)), signer)
+}
@@ -0,0 +1,154 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package signer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/dfinlab/meter/meter"
+	"github.com/pkg/errors"
+)
+
+// Kind is the kind of signing guarded against double sign.
+type Kind string
+
+const (
+	KindBlock    Kind = "block"
+	KindProposal Kind = "proposal"
+	KindVote     Kind = "vote"
+)
+
+// ErrDoubleSign is returned when signing would conflict with a previous one.
+var ErrDoubleSign = errors.New("double sign")
+
+// SignState is the last signed epoch, height and round of a kind.
+type SignState struct {
+	Epoch  uint64        `json:"epoch"`
+	Height uint32        `json:"height"`
+	Round  uint32        `json:"round"`
+	Hash   meter.Bytes32 `json:"hash"`
+}
+
+// Guard records the last signed round of each kind, and rejects signing a
+// different content at the same or any earlier round. Rounds increase across
+// heights in an epoch, so a lower height is allowed at a higher round, as the
+// pacemaker re-proposes and re-votes after reverting. A new epoch starts over
+// from round 0. Re-signing exactly the same content is allowed.
+type Guard struct {
+	path   string
+	lock   sync.Mutex
+	states map[Kind]*SignState
+}
+
+// NewGuard creates guard persisted to the file at path, loading the states if
+// the file exists.
+func NewGuard(path string) (*Guard, error) {
+	g := &Guard{
+		path:   path,
+		states: make(map[Kind]*SignState),
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return g, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &g.states); err != nil {
+		return nil, errors.WithMessage(err, "load sign state")
+	}
+	return g, nil
+}
+
+// NewMemGuard creates guard without persistence.
+func NewMemGuard() *Guard {
+	return &Guard{states: make(map[Kind]*SignState)}
+}
+
+// Check checks whether signing hash at height and round of epoch is safe, and
+// records it as the last signed one before return.
+func (g *Guard) Check(kind Kind, epoch uint64, height, round uint32, hash meter.Bytes32) error {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	if last, ok := g.states[kind]; ok {
+		if epoch < last.Epoch || (epoch == last.Epoch && round < last.Round) {
+			return errors.WithMessage(ErrDoubleSign, fmt.Sprintf("%v (%v,%v,%v) is behind last signed (%v,%v,%v)", kind, epoch, height, round, last.Epoch, last.Height, last.Round))
+		}
+		if epoch == last.Epoch && round == last.Round {
+			if height != last.Height || hash != last.Hash {
+				return errors.WithMessage(ErrDoubleSign, fmt.Sprintf("%v (%v,%v,%v) already signed %v at height %v", kind, epoch, height, round, last.Hash, last.Height))
+			}
+			return nil
+		}
+	}
+
+	prev := g.states[kind]
+	g.states[kind] = &SignState{Epoch: epoch, Height: height, Round: round, Hash: hash}
+	if err := g.save(); err != nil {
+		// never sign if state is not persisted
+		if prev != nil {
+			g.states[kind] = prev
+		} else {
+			delete(g.states, kind)
+		}
+		return errors.WithMessage(err, "save sign state")
+	}
+	return nil
+}
+
+// Last returns the last signed state of kind.
+func (g *Guard) Last(kind Kind) (SignState, bool) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	if s, ok := g.states[kind]; ok {
+		return *s, true
+	}
+	return SignState{}, false
+}
+
+// save writes to a temp file and renames it. Both the file and the directory are
+// synced, or the last signed state might be lost in a crash.
+func (g *Guard) save() error {
+	if g.path == "" {
+		return nil
+	}
+	data, err := json.Marshal(g.states)
+	if err != nil {
+		return err
+	}
+	tmp := g.path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, g.path); err != nil {
+		return err
+	}
+	dir, err := os.Open(filepath.Dir(g.path))
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	return dir.Sync()
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package signer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dfinlab/meter/meter"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGuard(t *testing.T) {
+	g := NewMemGuard()
+	a := meter.BytesToBytes32([]byte("a"))
+	b := meter.BytesToBytes32([]byte("b"))
+
+	assert.Nil(t, g.Check(KindVote, 1, 10, 3, a))
+	assert.Nil(t, g.Check(KindVote, 1, 10, 3, a), "re-sign same content")
+	assert.Equal(t, ErrDoubleSign, errors.Cause(g.Check(KindVote, 1, 10, 3, b)), "conflict at same round")
+	assert.Equal(t, ErrDoubleSign, errors.Cause(g.Check(KindVote, 1, 11, 3, a)), "another height at same round")
+	assert.Equal(t, ErrDoubleSign, errors.Cause(g.Check(KindVote, 1, 11, 2, b)), "lower round")
+	assert.Nil(t, g.Check(KindVote, 1, 11, 4, b))
+
+	// re-vote at lower height in higher round after the pacemaker reverts
+	assert.Nil(t, g.Check(KindVote, 1, 10, 5, a))
+	assert.Equal(t, ErrDoubleSign, errors.Cause(g.Check(KindVote, 1, 11, 4, b)), "reverted round")
+
+	// new epoch starts over from round 0
+	assert.Nil(t, g.Check(KindVote, 2, 12, 0, b))
+	assert.Equal(t, ErrDoubleSign, errors.Cause(g.Check(KindVote, 1, 13, 6, a)), "lower epoch")
+
+	// kinds are independent
+	assert.Nil(t, g.Check(KindProposal, 1, 10, 0, a))
+
+	last, ok := g.Last(KindVote)
+	assert.True(t, ok)
+	assert.Equal(t, SignState{Epoch: 2, Height: 12, Round: 0, Hash: b}, last)
+}
+
+func TestGuardPersist(t *testing.T) {
+	dir, err := ioutil.TempDir("", "guard")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "sign-state.json")
+	a := meter.BytesToBytes32([]byte("a"))
+	b := meter.BytesToBytes32([]byte("b"))
+
+	g, err := NewGuard(path)
+	assert.Nil(t, err)
+	assert.Nil(t, g.Check(KindProposal, 1, 3, 2, a))
+
+	// restarted node must remember what has been signed
+	g, err = NewGuard(path)
+	assert.Nil(t, err)
+	assert.Equal(t, ErrDoubleSign, errors.Cause(g.Check(KindProposal, 1, 3, 2, b)))
+	assert.Nil(t, g.Check(KindProposal, 1, 3, 2, a))
+}
@@ -0,0 +1,267 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package signer
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/subtle"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dfinlab/meter/api/utils"
+	"github.com/dfinlab/meter/block"
+	"github.com/dfinlab/meter/meter"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+)
+
+// request types of the remote signing protocol
+const (
+	reqSignBlock      = "block"
+	reqSignProposal   = "proposal"
+	reqSignVote       = "vote"
+	reqSignMessage    = "message"
+	reqSignBlsMessage = "bls-message"
+)
+
+// signRequest is the typed signing request. Data is the rlp encoded header for
+// block, the signing data for proposal and message, and the message for votes
+// and bls messages.
+type signRequest struct {
+	Type      string         `json:"type"`
+	Epoch     uint64         `json:"epoch"`
+	Height    uint32         `json:"height"`
+	Round     uint32         `json:"round"`
+	BlockHash *meter.Bytes32 `json:"blockHash,omitempty"`
+	Data      hexutil.Bytes  `json:"data"`
+}
+
+type signResponse struct {
+	Signature hexutil.Bytes `json:"signature"`
+}
+
+type keysResponse struct {
+	Address      meter.Address `json:"address"`
+	PublicKey    hexutil.Bytes `json:"publicKey"`
+	BlsPublicKey hexutil.Bytes `json:"blsPublicKey"`
+}
+
+// Remote is the signer client, which asks the signing server to sign over http.
+// The double sign protection is performed by the server.
+type Remote struct {
+	url       string
+	token     string
+	client    *http.Client
+	pubKey    *ecdsa.PublicKey
+	blsPubKey []byte
+}
+
+var _ Signer = (*Remote)(nil)
+
+// NewRemote connects to the signing server at url, authenticated with token.
+func NewRemote(url, token string) (*Remote, error) {
+	r := &Remote{
+		url:    strings.TrimSuffix(url, "/"),
+		token:  token,
+		client: &http.Client{Timeout: 4 * time.Second},
+	}
+
+	req, err := http.NewRequest("GET", r.url+"/keys", nil)
+	if err != nil {
+		return nil, err
+	}
+	var keys keysResponse
+	if err := r.do(req, &keys); err != nil {
+		return nil, errors.WithMessage(err, "connect signer")
+	}
+	if r.pubKey, err = crypto.UnmarshalPubkey(keys.PublicKey); err != nil {
+		return nil, errors.WithMessage(err, "signer public key")
+	}
+	if meter.Address(crypto.PubkeyToAddress(*r.pubKey)) != keys.Address {
+		return nil, errors.New("signer address mismatch with public key")
+	}
+	r.blsPubKey = keys.BlsPublicKey
+	return r, nil
+}
+
+func (r *Remote) Address() meter.Address {
+	return meter.Address(crypto.PubkeyToAddress(*r.pubKey))
+}
+
+func (r *Remote) PublicKey() *ecdsa.PublicKey {
+	return r.pubKey
+}
+
+func (r *Remote) BlsPublicKey() []byte {
+	return r.blsPubKey
+}
+
+func (r *Remote) SignBlock(epoch uint64, round uint32, header *block.Header) ([]byte, error) {
+	data, err := rlp.EncodeToBytes(header)
+	if err != nil {
+		return nil, err
+	}
+	return r.sign(&signRequest{Type: reqSignBlock, Epoch: epoch, Height: header.Number(), Round: round, Data: data})
+}
+
+func (r *Remote) SignProposal(epoch uint64, height, round uint32, blockHash meter.Bytes32, data []byte) ([]byte, error) {
+	return r.sign(&signRequest{Type: reqSignProposal, Epoch: epoch, Height: height, Round: round, BlockHash: &blockHash, Data: data})
+}
+
+func (r *Remote) SignVote(epoch uint64, height, round uint32, msg []byte) ([]byte, error) {
+	// checked by the server as well
+	if err := checkVoteMsg(height, msg); err != nil {
+		return nil, err
+	}
+	return r.sign(&signRequest{Type: reqSignVote, Epoch: epoch, Height: height, Round: round, Data: msg})
+}
+
+func (r *Remote) SignMessage(data []byte) ([]byte, error) {
+	return r.sign(&signRequest{Type: reqSignMessage, Data: data})
+}
+
+func (r *Remote) SignBlsMessage(msg []byte) ([]byte, error) {
+	return r.sign(&signRequest{Type: reqSignBlsMessage, Data: msg})
+}
+
+func (r *Remote) sign(sr *signRequest) ([]byte, error) {
+	data, err := json.Marshal(sr)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest("POST", r.url+"/sign", bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	var res signResponse
+	if err := r.do(req, &res); err != nil {
+		return nil, err
+	}
+	return res.Signature, nil
+}
+
+func (r *Remote) do(req *http.Request, v interface{}) error {
+	req.Header.Set("Authorization", "Bearer "+r.token)
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return errors.WithMessage(err, "remote signer")
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return json.Unmarshal(body, v)
+	case http.StatusConflict:
+		return errors.WithMessage(ErrDoubleSign, strings.TrimSpace(string(body)))
+	default:
+		return errors.Errorf("remote signer: %v %v", resp.Status, strings.TrimSpace(string(body)))
+	}
+}
+
+// Server serves the signing requests with the given signer, it's the
+// counterpart of Remote. Requests must carry the token.
+type Server struct {
+	signer Signer
+	token  string
+}
+
+// NewServer creates signing server, token must not be empty.
+func NewServer(signer Signer, token string) *Server {
+	return &Server{signer, token}
+}
+
+func (s *Server) authorize(req *http.Request) error {
+	token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if s.token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+		return utils.HTTPError(errors.New("unauthorized"), http.StatusUnauthorized)
+	}
+	return nil
+}
+
+func (s *Server) handleGetKeys(w http.ResponseWriter, req *http.Request) error {
+	return utils.WriteJSON(w, &keysResponse{
+		Address:      s.signer.Address(),
+		PublicKey:    crypto.FromECDSAPub(s.signer.PublicKey()),
+		BlsPublicKey: s.signer.BlsPublicKey(),
+	})
+}
+
+func (s *Server) handleSign(w http.ResponseWriter, req *http.Request) error {
+	var sr signRequest
+	if err := utils.ParseJSON(req.Body, &sr); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+
+	var (
+		sig []byte
+		err error
+	)
+	switch sr.Type {
+	case reqSignBlock:
+		var header block.Header
+		if err := rlp.DecodeBytes(sr.Data, &header); err != nil {
+			return utils.BadRequest(errors.WithMessage(err, "data"))
+		}
+		if header.Number() != sr.Height {
+			return utils.BadRequest(errors.New("height: mismatch with header"))
+		}
+		sig, err = s.signer.SignBlock(sr.Epoch, sr.Round, &header)
+	case reqSignProposal:
+		if sr.BlockHash == nil {
+			return utils.BadRequest(errors.New("blockHash: required"))
+		}
+		sig, err = s.signer.SignProposal(sr.Epoch, sr.Height, sr.Round, *sr.BlockHash, sr.Data)
+	case reqSignVote:
+		sig, err = s.signer.SignVote(sr.Epoch, sr.Height, sr.Round, sr.Data)
+	case reqSignMessage:
+		sig, err = s.signer.SignMessage(sr.Data)
+	case reqSignBlsMessage:
+		sig, err = s.signer.SignBlsMessage(sr.Data)
+	default:
+		return utils.BadRequest(errors.New("type: unsupported"))
+	}
+	if err != nil {
+		switch errors.Cause(err) {
+		case ErrDoubleSign:
+			return utils.HTTPError(err, http.StatusConflict)
+		case ErrUnexpected:
+			return utils.BadRequest(err)
+		}
+		return err
+	}
+	return utils.WriteJSON(w, &signResponse{Signature: sig})
+}
+
+// action wraps the handler with authorization.
+func (s *Server) action(f utils.HandlerFunc) http.HandlerFunc {
+	return utils.WrapHandlerFunc(func(w http.ResponseWriter, req *http.Request) error {
+		if err := s.authorize(req); err != nil {
+			return err
+		}
+		return f(w, req)
+	})
+}
+
+// Mount mounts the handlers on router with path prefix.
+func (s *Server) Mount(root *mux.Router, pathPrefix string) {
+	sub := root.PathPrefix(pathPrefix).Subrouter()
+
+	sub.Path("/keys").Methods("GET").HandlerFunc(s.action(s.handleGetKeys))
+	sub.Path("/sign").Methods("POST").HandlerFunc(s.action(s.handleSign))
+}
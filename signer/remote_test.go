@@ -0,0 +1,139 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package signer
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dfinlab/meter/block"
+	bls "github.com/dfinlab/meter/crypto/multi_sig"
+	"github.com/dfinlab/meter/meter"
+	"github.com/dfinlab/meter/tx"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+const token = "secret"
+
+func newTestLocal(t *testing.T) *Local {
+	key, _ := crypto.GenerateKey()
+	params := bls.GenParamsTypeA(160, 512)
+	system, err := bls.GenSystem(bls.GenPairing(params))
+	if err != nil {
+		t.Fatal(err)
+	}
+	blsPub, blsPriv, err := bls.GenKeys(system)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewLocal(key, system, blsPriv, blsPub, nil)
+}
+
+// msgData builds the signing data of consensus message.
+func msgData(sender []byte, msgType byte, epoch uint64, height, round uint32) []byte {
+	data, _ := rlp.EncodeToBytes([]interface{}{
+		height, round, sender, []uint{}, msgType, byte(0), epoch, []byte("body"),
+	})
+	return data
+}
+
+// voteMsg builds the vote message of the block, see BuildProposalBlockSignMsg of package consensus.
+func voteMsg(height uint32, name string) []byte {
+	var id meter.Bytes32
+	copy(id[4:], name)
+	binary.BigEndian.PutUint32(id[:], height)
+	root := meter.BytesToBytes32([]byte("root"))
+	return []byte(fmt.Sprintf("BlockType %x Height %x BlockID %v TxRoot %v StateRoot %v",
+		[]byte{0, 0, 0, 2, 0}, []byte{0, 0, 0, 0, 0, 0, 0, byte(height), 0, 0}, id, root, root))
+}
+
+func TestRemote(t *testing.T) {
+	local := newTestLocal(t)
+
+	router := mux.NewRouter()
+	NewServer(local, token).Mount(router, "/")
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	_, err := NewRemote(ts.URL, "wrong")
+	assert.NotNil(t, err, "unauthorized")
+
+	remote, err := NewRemote(ts.URL, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, local.Address(), remote.Address())
+	assert.Equal(t, local.BlsPublicKey(), remote.BlsPublicKey())
+
+	// block
+	header := new(block.Builder).ParentID(meter.BytesToBytes32([]byte("parent"))).Build().Header()
+	sig, err := remote.SignBlock(1, 3, header)
+	assert.Nil(t, err)
+	pub, err := crypto.SigToPub(header.SigningHash().Bytes(), sig)
+	assert.Nil(t, err)
+	assert.Equal(t, local.Address(), meter.Address(crypto.PubkeyToAddress(*pub)))
+
+	other := new(block.Builder).ParentID(meter.BytesToBytes32([]byte("other"))).Build().Header()
+	_, err = remote.SignBlock(1, 3, other)
+	assert.Equal(t, ErrDoubleSign, errors.Cause(err))
+
+	// proposal
+	sender := crypto.FromECDSAPub(local.PublicKey())
+	a := meter.BytesToBytes32([]byte("a"))
+	b := meter.BytesToBytes32([]byte("b"))
+	_, err = remote.SignProposal(1, 1, 3, a, msgData(sender, msgTypeProposal, 1, 1, 3))
+	assert.Nil(t, err)
+	_, err = remote.SignProposal(1, 1, 3, b, msgData(sender, msgTypeProposal, 1, 1, 3))
+	assert.Equal(t, ErrDoubleSign, errors.Cause(err))
+	_, err = remote.SignProposal(1, 1, 4, b, msgData(sender, msgTypeProposal, 1, 1, 5))
+	assert.NotNil(t, err, "header mismatch")
+
+	// vote
+	vote := voteMsg(1, "a")
+	sig, err = remote.SignVote(1, 1, 3, vote)
+	assert.Nil(t, err)
+	blsSig, err := local.blsSystem.SigFromBytes(sig)
+	assert.Nil(t, err)
+	blsPub, err := local.blsSystem.PubKeyFromBytes(remote.BlsPublicKey())
+	assert.Nil(t, err)
+	assert.True(t, bls.Verify(blsSig, sha256.Sum256(vote), blsPub))
+	_, err = remote.SignVote(1, 1, 3, voteMsg(1, "b"))
+	assert.Equal(t, ErrDoubleSign, errors.Cause(err))
+	_, err = remote.SignVote(1, 1, 4, voteMsg(2, "b"))
+	assert.Equal(t, ErrUnexpected, errors.Cause(err), "height mismatch")
+	_, err = remote.SignVote(1, 1, 4, []byte("New View Message: Peer:00 Height:1 Round:4"))
+	assert.Equal(t, ErrUnexpected, errors.Cause(err), "not a vote")
+	_, err = local.SignVote(1, 1, 4, []byte("BlockType 00000002 Height 0000000000000001"))
+	assert.Equal(t, ErrUnexpected, errors.Cause(err), "not a vote")
+
+	// messages
+	_, err = remote.SignMessage(msgData(sender, 0x11, 1, 1, 3))
+	assert.Nil(t, err)
+	_, err = remote.SignMessage(msgData(sender, msgTypeProposal, 1, 2, 4))
+	assert.NotNil(t, err, "proposal is not a message")
+	key, _ := crypto.GenerateKey()
+	_, err = remote.SignMessage(msgData(crypto.FromECDSAPub(&key.PublicKey), 0x11, 1, 1, 3))
+	assert.NotNil(t, err, "message of another node")
+	_, err = remote.SignBlsMessage([]byte("New View Message: Peer:00 Height:1 Round:3"))
+	assert.Nil(t, err)
+	_, err = remote.SignBlsMessage(vote)
+	assert.NotNil(t, err, "vote is not a message")
+
+	// neither block nor tx could be signed as message
+	headerData, _ := rlp.EncodeToBytes(other)
+	_, err = remote.SignMessage(headerData)
+	assert.NotNil(t, err)
+	txData, _ := rlp.EncodeToBytes(new(tx.Builder).ChainTag(1).Expiration(1).Build())
+	_, err = remote.SignMessage(txData)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,237 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+// Package signer keeps the master keys of a node behind an interface, so the
+// keys could be held by a separate process.
+package signer
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"strings"
+
+	"github.com/dfinlab/meter/block"
+	bls "github.com/dfinlab/meter/crypto/multi_sig"
+	"github.com/dfinlab/meter/meter"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
+)
+
+// ErrUnexpected is returned when the content to sign is not what the request claims.
+var ErrUnexpected = errors.New("unexpected content")
+
+// msgTypeProposal is the type of pacemaker proposal, see package consensus.
+const msgTypeProposal = byte(0x10)
+
+// blsMessagePrefixes are the consensus messages signed with the BLS key besides votes.
+var blsMessagePrefixes = []string{
+	"New Committee Message:",
+	"Announce Committee Message:",
+	"Announce Notarization Message:",
+	"New View Message:",
+}
+
+// Signer signs blocks and consensus messages on behalf of the node master.
+// Requests are typed, the signer never signs a hash it can't tell the content of.
+type Signer interface {
+	// Address returns the address of the ECDSA key.
+	Address() meter.Address
+
+	// PublicKey returns the ECDSA public key.
+	PublicKey() *ecdsa.PublicKey
+
+	// BlsPublicKey returns the BLS public key in bytes.
+	BlsPublicKey() []byte
+
+	// SignBlock signs the header of the block proposed at round of epoch with the ECDSA key.
+	// It refuses to sign another block at the same or an earlier round.
+	SignBlock(epoch uint64, round uint32, header *block.Header) ([]byte, error)
+
+	// SignProposal signs the proposal message of the block with the ECDSA key, data is the
+	// preimage of the message signing hash. It refuses to sign another proposal at the
+	// same or an earlier round.
+	SignProposal(epoch uint64, height, round uint32, blockHash meter.Bytes32, data []byte) ([]byte, error)
+
+	// SignVote signs the vote message for the block with the BLS key. It refuses to vote
+	// another block at the same or an earlier round.
+	SignVote(epoch uint64, height, round uint32, msg []byte) ([]byte, error)
+
+	// SignMessage signs the consensus message sent by this node with the ECDSA key, data
+	// is the preimage of the message signing hash. Proposals are not accepted.
+	SignMessage(data []byte) ([]byte, error)
+
+	// SignBlsMessage signs the committee or new view message with the BLS key.
+	SignBlsMessage(msg []byte) ([]byte, error)
+}
+
+// Local is the signer holding keys in memory.
+type Local struct {
+	privKey    *ecdsa.PrivateKey
+	blsSystem  bls.System
+	blsPrivKey bls.PrivateKey
+	blsPubKey  []byte
+	guard      *Guard
+}
+
+var _ Signer = (*Local)(nil)
+
+// NewLocal creates local signer. The guard could be nil, then double sign
+// protection only lasts in memory.
+func NewLocal(privKey *ecdsa.PrivateKey, blsSystem bls.System, blsPrivKey bls.PrivateKey, blsPubKey bls.PublicKey, guard *Guard) *Local {
+	if guard == nil {
+		guard = NewMemGuard()
+	}
+	return &Local{
+		privKey:    privKey,
+		blsSystem:  blsSystem,
+		blsPrivKey: blsPrivKey,
+		blsPubKey:  blsSystem.PubKeyToBytes(blsPubKey),
+		guard:      guard,
+	}
+}
+
+func (l *Local) Address() meter.Address {
+	return meter.Address(crypto.PubkeyToAddress(l.privKey.PublicKey))
+}
+
+func (l *Local) PublicKey() *ecdsa.PublicKey {
+	return &l.privKey.PublicKey
+}
+
+func (l *Local) BlsPublicKey() []byte {
+	return l.blsPubKey
+}
+
+func (l *Local) SignBlock(epoch uint64, round uint32, header *block.Header) ([]byte, error) {
+	hash := header.SigningHash()
+	if err := l.guard.Check(KindBlock, epoch, header.Number(), round, hash); err != nil {
+		return nil, err
+	}
+	return crypto.Sign(hash.Bytes(), l.privKey)
+}
+
+func (l *Local) SignProposal(epoch uint64, height, round uint32, blockHash meter.Bytes32, data []byte) ([]byte, error) {
+	h, err := l.decodeMsgHeader(data)
+	if err != nil {
+		return nil, err
+	}
+	if h.MsgType != msgTypeProposal || h.EpochID != epoch || h.Height != height || h.Round != round {
+		return nil, errors.WithMessage(ErrUnexpected, "proposal header mismatch")
+	}
+	if err := l.guard.Check(KindProposal, epoch, height, round, blockHash); err != nil {
+		return nil, err
+	}
+	return crypto.Sign(meter.Blake2b(data).Bytes(), l.privKey)
+}
+
+func (l *Local) SignVote(epoch uint64, height, round uint32, msg []byte) ([]byte, error) {
+	if err := checkVoteMsg(height, msg); err != nil {
+		return nil, err
+	}
+	hash := sha256.Sum256(msg)
+	if err := l.guard.Check(KindVote, epoch, height, round, meter.Bytes32(hash)); err != nil {
+		return nil, err
+	}
+	return l.signBls(hash), nil
+}
+
+func (l *Local) SignMessage(data []byte) ([]byte, error) {
+	h, err := l.decodeMsgHeader(data)
+	if err != nil {
+		return nil, err
+	}
+	if h.MsgType == msgTypeProposal {
+		return nil, errors.WithMessage(ErrUnexpected, "proposal must be signed as proposal")
+	}
+	return crypto.Sign(meter.Blake2b(data).Bytes(), l.privKey)
+}
+
+func (l *Local) SignBlsMessage(msg []byte) ([]byte, error) {
+	for _, prefix := range blsMessagePrefixes {
+		if strings.HasPrefix(string(msg), prefix) {
+			return l.signBls(sha256.Sum256(msg)), nil
+		}
+	}
+	return nil, errors.WithMessage(ErrUnexpected, "unknown message")
+}
+
+func (l *Local) signBls(hash [32]byte) []byte {
+	sig := bls.Sign(hash, l.blsPrivKey)
+	defer sig.Free()
+	return l.blsSystem.SigToBytes(sig)
+}
+
+// checkVoteMsg checks that msg is the vote for a block at height, in the format of
+// BuildProposalBlockSignMsg of package consensus:
+// "BlockType <type> Height <height> BlockID <id> TxRoot <root> StateRoot <root>".
+// The round is not part of the vote, it's guarded along with the height.
+func checkVoteMsg(height uint32, msg []byte) error {
+	fields := strings.Split(string(msg), " ")
+	if len(fields) != 10 ||
+		fields[0] != "BlockType" || fields[2] != "Height" || fields[4] != "BlockID" ||
+		fields[6] != "TxRoot" || fields[8] != "StateRoot" {
+		return errors.WithMessage(ErrUnexpected, "not a vote")
+	}
+	blockType, err := hex.DecodeString(fields[1])
+	if err != nil || len(blockType) != binary.MaxVarintLen32 {
+		return errors.WithMessage(ErrUnexpected, "vote: invalid block type")
+	}
+	h, err := hex.DecodeString(fields[3])
+	if err != nil || len(h) != binary.MaxVarintLen64 {
+		return errors.WithMessage(ErrUnexpected, "vote: invalid height")
+	}
+	if binary.BigEndian.Uint64(h) != uint64(height) {
+		return errors.WithMessage(ErrUnexpected, "vote: height mismatch")
+	}
+	blockID, err := meter.ParseBytes32(fields[5])
+	if err != nil {
+		return errors.WithMessage(ErrUnexpected, "vote: invalid block id")
+	}
+	if block.Number(blockID) != height {
+		return errors.WithMessage(ErrUnexpected, "vote: block id not at height")
+	}
+	for _, root := range []string{fields[7], fields[9]} {
+		if _, err := meter.ParseBytes32(root); err != nil {
+			return errors.WithMessage(ErrUnexpected, "vote: invalid root")
+		}
+	}
+	return nil
+}
+
+// msgHeader is the leading fields of consensus message signing data.
+type msgHeader struct {
+	Height  uint32
+	Round   uint32
+	Sender  []byte
+	MsgType byte
+	EpochID uint64
+}
+
+// decodeMsgHeader decodes the header of consensus message sent by this node. Neither
+// blocks nor transactions could be decoded, which keeps them from being signed as message.
+func (l *Local) decodeMsgHeader(data []byte) (*msgHeader, error) {
+	var fields []rlp.RawValue
+	if err := rlp.DecodeBytes(data, &fields); err != nil || len(fields) < 7 {
+		return nil, errors.WithMessage(ErrUnexpected, "not a consensus message")
+	}
+	// height, round, sender, timestamp, type, sub type, epoch
+	var h msgHeader
+	for i, v := range []interface{}{&h.Height, &h.Round, &h.Sender, nil, &h.MsgType, nil, &h.EpochID} {
+		if v == nil {
+			continue
+		}
+		if err := rlp.DecodeBytes(fields[i], v); err != nil {
+			return nil, errors.WithMessage(ErrUnexpected, "not a consensus message")
+		}
+	}
+	if !bytes.Equal(h.Sender, crypto.FromECDSAPub(&l.privKey.PublicKey)) {
+		return nil, errors.WithMessage(ErrUnexpected, "message not sent by signer")
+	}
+	return &h, nil
+}
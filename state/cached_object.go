@@ -50,10 +50,12 @@ func (co *cachedObject) GetStorage(key meter.Bytes32) (rlp.RawValue, error) {
 		cache.storage = make(map[meter.Bytes32]rlp.RawValue)
 	} else {
 		if v, ok := cache.storage[key]; ok {
+			markCache("storage", true)
 			return v, nil
 		}
 	}
 	// not found in cache
+	markCache("storage", false)
 
 	trie, err := co.getOrCreateStorageTrie()
 	if err != nil {
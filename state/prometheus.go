@@ -0,0 +1,19 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import "github.com/dfinlab/meter/metric"
+
+var cacheLookupCounter = metric.NewCounterVec("state_cache_lookups_total", "Counter of state cache lookups by cache and result", "cache", "result")
+
+// markCache counts a lookup of the named cache.
+func markCache(cache string, hit bool) {
+	if hit {
+		cacheLookupCounter.WithLabelValues(cache, "hit").Inc()
+	} else {
+		cacheLookupCounter.WithLabelValues(cache, "miss").Inc()
+	}
+}
@@ -139,8 +139,10 @@ func (s *State) changes() map[meter.Address]*changedObject {
 
 func (s *State) getCachedObject(addr meter.Address) *cachedObject {
 	if co, ok := s.cache[addr]; ok {
+		markCache("account", true)
 		return co
 	}
+	markCache("account", false)
 	a, err := loadAccount(s.trie, addr)
 	if err != nil {
 		s.setError(err)
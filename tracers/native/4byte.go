@@ -0,0 +1,98 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package native
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/dfinlab/meter/vm"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// FourByteTracer searches for 4byte-identifiers, and collects them for
+// post-processing. It collects the methods identifiers along with the size of
+// the supplied data, so a reversed signature can be matched against the size
+// of the data, e.g. {"0x27dc297e-128": 1, "0x38cc4831-0": 2}. It is the native
+// version of 4byteTracer.
+type FourByteTracer struct {
+	interrupter
+
+	ids map[string]int // ids aggregates the 4byte ids found
+}
+
+// New4ByteTracer creates a 4byte tracer.
+func New4ByteTracer() *FourByteTracer {
+	return &FourByteTracer{
+		ids: make(map[string]int),
+	}
+}
+
+// store saves the given identifier and datasize.
+func (t *FourByteTracer) store(id []byte, size int) {
+	t.ids[fmt.Sprintf("0x%x-%d", id, size)]++
+}
+
+// CaptureStart implements the Tracer interface.
+func (t *FourByteTracer) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	// Save the outer calldata also
+	if len(input) >= 4 {
+		t.store(input[:4], len(input)-4)
+	}
+	return nil
+}
+
+// CaptureState implements the Tracer interface.
+func (t *FourByteTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	if t.interrupted() {
+		return nil
+	}
+	// Skip any opcodes that are not internal calls, and the stack pointer
+	// to the memory of the input
+	var inOffset int
+	switch op {
+	case vm.CALL, vm.CALLCODE:
+		// gas, addr, val, memin, meminsz, memout, memoutsz
+		inOffset = 3
+	case vm.DELEGATECALL, vm.STATICCALL:
+		// gas, addr, memin, meminsz, memout, memoutsz
+		inOffset = 2
+	default:
+		return nil
+	}
+	// Skip any pre-compile invocations, those are just fancy opcodes
+	if env.IsPrecompiled(common.BigToAddress(stack.Back(1))) {
+		return nil
+	}
+	// Gather internal call details
+	inSize := stack.Back(inOffset + 1)
+	if inSize.IsInt64() && inSize.Int64() >= 4 {
+		if id := memorySlice(memory, stack.Back(inOffset), big.NewInt(4)); id != nil {
+			t.store(id, int(inSize.Int64())-4)
+		}
+	}
+	return nil
+}
+
+// CaptureFault implements the Tracer interface.
+func (t *FourByteTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	return nil
+}
+
+// CaptureEnd implements the Tracer interface.
+func (t *FourByteTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) error {
+	return nil
+}
+
+// GetResult returns the collected ids.
+func (t *FourByteTracer) GetResult() (json.RawMessage, error) {
+	if t.interrupted() {
+		return nil, t.reason
+	}
+	return json.Marshal(t.ids)
+}
@@ -0,0 +1,248 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package native
+
+import (
+	"encoding/json"
+	"math/big"
+	"time"
+
+	"github.com/dfinlab/meter/vm"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// callFrame is a call reported by the call tracer.
+type callFrame struct {
+	Type    string          `json:"type"`
+	From    common.Address  `json:"from"`
+	To      *common.Address `json:"to,omitempty"`
+	Value   *hexutil.Big    `json:"value,omitempty"`
+	Gas     *hexutil.Uint64 `json:"gas,omitempty"`
+	GasUsed *hexutil.Uint64 `json:"gasUsed,omitempty"`
+	Input   hexutil.Bytes   `json:"input,omitempty"`
+	Output  hexutil.Bytes   `json:"output,omitempty"`
+	Error   string          `json:"error,omitempty"`
+	Time    string          `json:"time,omitempty"`
+	Calls   []*callFrame    `json:"calls,omitempty"`
+
+	// book keeping until the call returns
+	gasIn   uint64
+	gasCost uint64
+	outOff  *big.Int
+	outLen  *big.Int
+}
+
+func (f *callFrame) addCall(call *callFrame) {
+	f.Calls = append(f.Calls, call)
+}
+
+func uint64Ptr(v uint64) *hexutil.Uint64 {
+	h := hexutil.Uint64(v)
+	return &h
+}
+
+// CallTracer extracts and reports all the internal calls made by a
+// transaction, it is the native version of callTracer.
+type CallTracer struct {
+	interrupter
+
+	// callstack is the current recursive call stack of the EVM execution.
+	callstack []*callFrame
+	// descended tracks whether we've just descended from an outer transaction into
+	// an inner call.
+	descended bool
+
+	root *callFrame // the outer call, from CaptureStart/CaptureEnd
+	err  error      // error of the outer call
+}
+
+// NewCallTracer creates a call tracer.
+func NewCallTracer() *CallTracer {
+	return &CallTracer{
+		callstack: []*callFrame{{}},
+	}
+}
+
+// CaptureStart implements the Tracer interface.
+func (t *CallTracer) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	t.root = &callFrame{
+		Type:  "CALL",
+		From:  from,
+		To:    &to,
+		Input: common.CopyBytes(input),
+		Gas:   uint64Ptr(gas),
+		Value: (*hexutil.Big)(new(big.Int).Set(value)),
+	}
+	if create {
+		t.root.Type = "CREATE"
+	}
+	return nil
+}
+
+// CaptureState implements the Tracer interface.
+func (t *CallTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	if t.interrupted() {
+		return nil
+	}
+	// Capture any errors immediately
+	if err != nil {
+		return t.CaptureFault(env, pc, op, gas, cost, memory, stack, contract, depth, err)
+	}
+	switch op {
+	case vm.CREATE, vm.CREATE2:
+		// If a new contract is being created, add to the call stack
+		t.callstack = append(t.callstack, &callFrame{
+			Type:    op.String(),
+			From:    contract.Address(),
+			Input:   memorySlice(memory, stack.Back(1), stack.Back(2)),
+			Value:   (*hexutil.Big)(new(big.Int).Set(stack.Back(0))),
+			gasIn:   gas,
+			gasCost: cost,
+		})
+		t.descended = true
+		return nil
+
+	case vm.SELFDESTRUCT:
+		// If a contract is being self destructed, gather that as a subcall too
+		to := common.BigToAddress(stack.Back(0))
+		t.callstack[len(t.callstack)-1].addCall(&callFrame{
+			Type: op.String(),
+			From: contract.Address(),
+			To:   &to,
+		})
+		return nil
+
+	case vm.CALL, vm.CALLCODE, vm.DELEGATECALL, vm.STATICCALL:
+		// Skip any pre-compile invocations, those are just fancy opcodes
+		to := common.BigToAddress(stack.Back(1))
+		if env.IsPrecompiled(to) {
+			return nil
+		}
+		off := 1
+		if op == vm.DELEGATECALL || op == vm.STATICCALL {
+			off = 0
+		}
+		call := &callFrame{
+			Type:    op.String(),
+			From:    contract.Address(),
+			To:      &to,
+			Input:   memorySlice(memory, stack.Back(2+off), stack.Back(3+off)),
+			gasIn:   gas,
+			gasCost: cost,
+			outOff:  new(big.Int).Set(stack.Back(4 + off)),
+			outLen:  new(big.Int).Set(stack.Back(5 + off)),
+		}
+		if off == 1 {
+			call.Value = (*hexutil.Big)(new(big.Int).Set(stack.Back(2)))
+		}
+		t.callstack = append(t.callstack, call)
+		t.descended = true
+		return nil
+	}
+	// If we've just descended into an inner call, retrieve it's true allowance. We
+	// need to extract if from within the call as there may be funky gas dynamics
+	// with regard to requested and actually given gas (2300 stipend, 63/64 rule).
+	if t.descended {
+		if depth >= len(t.callstack) {
+			t.callstack[len(t.callstack)-1].Gas = uint64Ptr(gas)
+		}
+		t.descended = false
+	}
+	if op == vm.REVERT {
+		t.callstack[len(t.callstack)-1].Error = "execution reverted"
+		return nil
+	}
+	// If an existing call is returning, pop off the call stack
+	if depth == len(t.callstack)-1 {
+		call := t.callstack[len(t.callstack)-1]
+		t.callstack = t.callstack[:len(t.callstack)-1]
+
+		ret := stack.Back(0)
+		if call.Type == vm.CREATE.String() || call.Type == vm.CREATE2.String() {
+			// If the call was a CREATE, retrieve the contract address and output code
+			call.GasUsed = uint64Ptr(call.gasIn - call.gasCost - gas)
+			if ret.Sign() != 0 {
+				to := common.BigToAddress(ret)
+				call.To = &to
+				call.Output = env.StateDB.GetCode(to)
+			} else if call.Error == "" {
+				call.Error = "internal failure"
+			}
+		} else if call.Gas != nil {
+			// If the call was a contract call, retrieve the gas usage and output
+			call.GasUsed = uint64Ptr(call.gasIn - call.gasCost + uint64(*call.Gas) - gas)
+			if ret.Sign() != 0 {
+				call.Output = memorySlice(memory, call.outOff, call.outLen)
+			} else if call.Error == "" {
+				call.Error = "internal failure"
+			}
+		}
+		t.callstack[len(t.callstack)-1].addCall(call)
+	}
+	return nil
+}
+
+// CaptureFault implements the Tracer interface.
+func (t *CallTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	if t.interrupted() {
+		return nil
+	}
+	// If the topmost call already reverted, don't handle the additional fault again
+	if t.callstack[len(t.callstack)-1].Error != "" {
+		return nil
+	}
+	// Pop off the just failed call
+	call := t.callstack[len(t.callstack)-1]
+	t.callstack = t.callstack[:len(t.callstack)-1]
+	call.Error = err.Error()
+
+	// Consume all available gas
+	if call.Gas != nil {
+		call.GasUsed = uint64Ptr(uint64(*call.Gas))
+	}
+	// Flatten the failed call into its parent
+	if len(t.callstack) > 0 {
+		t.callstack[len(t.callstack)-1].addCall(call)
+		return nil
+	}
+	// Last call failed too, leave it in the stack
+	t.callstack = append(t.callstack, call)
+	return nil
+}
+
+// CaptureEnd implements the Tracer interface.
+func (t *CallTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) error {
+	if t.root == nil {
+		return nil
+	}
+	t.root.Output = common.CopyBytes(output)
+	t.root.GasUsed = uint64Ptr(gasUsed)
+	t.root.Time = d.String()
+	t.err = err
+	return nil
+}
+
+// GetResult returns the outer call with all the internal calls.
+func (t *CallTracer) GetResult() (json.RawMessage, error) {
+	if t.interrupted() {
+		return nil, t.reason
+	}
+	if t.root == nil {
+		return json.RawMessage(`{}`), nil
+	}
+	result := *t.root
+	result.Calls = t.callstack[0].Calls
+	if t.callstack[0].Error != "" {
+		result.Error = t.callstack[0].Error
+	} else if t.err != nil {
+		result.Error = t.err.Error()
+	}
+	if result.Error != "" {
+		result.Output = nil
+	}
+	return json.Marshal(&result)
+}
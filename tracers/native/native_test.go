@@ -0,0 +1,113 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package native_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/dfinlab/meter/builtin"
+	"github.com/dfinlab/meter/lvldb"
+	"github.com/dfinlab/meter/meter"
+	"github.com/dfinlab/meter/runtime"
+	"github.com/dfinlab/meter/state"
+	"github.com/dfinlab/meter/tracers/native"
+	"github.com/dfinlab/meter/tx"
+	"github.com/dfinlab/meter/vm"
+	"github.com/dfinlab/meter/xenv"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// traceOuter traces Measure.outer(), which calls Measure.inner() once.
+func traceOuter(t *testing.T, tracer native.Tracer) json.RawMessage {
+	kv, _ := lvldb.NewMem()
+	st, _ := state.New(meter.Bytes32{}, kv)
+	st.SetCode(builtin.Measure.Address, builtin.Measure.RuntimeBytecodes())
+
+	outer, _ := builtin.Measure.ABI.MethodByName("outer")
+	data, _ := outer.EncodeInput()
+
+	rt := runtime.New(nil, st, &xenv.BlockContext{}).SetVMConfig(vm.Config{Debug: true, Tracer: tracer})
+	out := rt.ExecuteClause(
+		tx.NewClause(&builtin.Measure.Address).WithData(data),
+		0,
+		math.MaxUint64,
+		&xenv.TransactionContext{})
+	assert.Nil(t, out.VMErr)
+
+	res, err := tracer.GetResult()
+	assert.Nil(t, err)
+	return res
+}
+
+func TestNew(t *testing.T) {
+	for _, name := range []string{"callTracer", "prestateTracer", "4byteTracer"} {
+		tr, ok := native.New(name)
+		assert.True(t, ok, name)
+		assert.NotNil(t, tr, name)
+	}
+	_, ok := native.New("opcountTracer")
+	assert.False(t, ok)
+}
+
+func TestCallTracer(t *testing.T) {
+	var res struct {
+		To    common.Address
+		Input string
+		Calls []struct {
+			From  common.Address
+			To    common.Address
+			Input string
+		}
+	}
+	assert.Nil(t, json.Unmarshal(traceOuter(t, native.NewCallTracer()), &res))
+
+	measure := common.Address(builtin.Measure.Address)
+	inner, _ := builtin.Measure.ABI.MethodByName("inner")
+	assert.Equal(t, measure, res.To)
+	if assert.Len(t, res.Calls, 1) {
+		assert.Equal(t, measure, res.Calls[0].From)
+		assert.Equal(t, measure, res.Calls[0].To)
+		assert.Equal(t, fmt.Sprintf("0x%x", inner.ID()), res.Calls[0].Input)
+	}
+}
+
+func TestPrestateTracer(t *testing.T) {
+	var res map[common.Address]struct {
+		Code string
+	}
+	assert.Nil(t, json.Unmarshal(traceOuter(t, native.NewPrestateTracer()), &res))
+
+	measure := common.Address(builtin.Measure.Address)
+	if assert.Contains(t, res, measure) {
+		assert.Equal(t, fmt.Sprintf("0x%x", builtin.Measure.RuntimeBytecodes()), res[measure].Code)
+	}
+	// the origin is always reported
+	assert.Contains(t, res, common.Address{})
+}
+
+func Test4ByteTracer(t *testing.T) {
+	var res map[string]int
+	assert.Nil(t, json.Unmarshal(traceOuter(t, native.New4ByteTracer()), &res))
+
+	outer, _ := builtin.Measure.ABI.MethodByName("outer")
+	inner, _ := builtin.Measure.ABI.MethodByName("inner")
+	assert.Equal(t, map[string]int{
+		fmt.Sprintf("0x%x-0", outer.ID()): 1,
+		fmt.Sprintf("0x%x-0", inner.ID()): 1,
+	}, res)
+}
+
+func TestStop(t *testing.T) {
+	tr := native.NewCallTracer()
+	tr.Stop(errors.New("execution timeout"))
+	_, err := tr.GetResult()
+	assert.EqualError(t, err, "execution timeout")
+}
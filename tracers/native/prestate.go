@@ -0,0 +1,154 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package native
+
+import (
+	"encoding/json"
+	"math/big"
+	"time"
+
+	"github.com/dfinlab/meter/tx"
+	"github.com/dfinlab/meter/vm"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// prestateAccount is the state of an account before the execution.
+// Balance is the MTRG balance and Energy the MTR balance.
+type prestateAccount struct {
+	Balance *hexutil.Big                `json:"balance"`
+	Energy  *hexutil.Big                `json:"energy"`
+	Code    hexutil.Bytes               `json:"code"`
+	Storage map[common.Hash]common.Hash `json:"storage"`
+}
+
+// PrestateTracer outputs the accounts and storage touched by the execution,
+// as they were before it. It is the native version of prestateTracer.
+type PrestateTracer struct {
+	interrupter
+
+	prestate map[common.Address]*prestateAccount
+	db       vm.StateDB
+
+	create bool
+	from   common.Address
+	to     common.Address
+	value  *big.Int
+	token  byte
+}
+
+// NewPrestateTracer creates a prestate tracer.
+func NewPrestateTracer() *PrestateTracer {
+	return &PrestateTracer{
+		prestate: make(map[common.Address]*prestateAccount),
+		token:    tx.TOKEN_METER,
+	}
+}
+
+// SetToken sets the token of the value carried by the traced clause, so that
+// it can be moved back to the origin. Default is MTR.
+func (t *PrestateTracer) SetToken(token byte) {
+	t.token = token
+}
+
+// lookupAccount injects the specified account into the prestate object.
+func (t *PrestateTracer) lookupAccount(addr common.Address) {
+	if _, ok := t.prestate[addr]; ok {
+		return
+	}
+	t.prestate[addr] = &prestateAccount{
+		Balance: (*hexutil.Big)(new(big.Int).Set(t.db.GetBalance(addr))),
+		Energy:  (*hexutil.Big)(new(big.Int).Set(t.db.GetEnergy(addr))),
+		Code:    common.CopyBytes(t.db.GetCode(addr)),
+		Storage: make(map[common.Hash]common.Hash),
+	}
+}
+
+// lookupStorage injects the specified storage entry of the given account into
+// the prestate object.
+func (t *PrestateTracer) lookupStorage(addr common.Address, key common.Hash) {
+	storage := t.prestate[addr].Storage
+	if _, ok := storage[key]; ok {
+		return
+	}
+	if val := t.db.GetState(addr, key); val != (common.Hash{}) {
+		storage[key] = val
+	}
+}
+
+// CaptureStart implements the Tracer interface.
+func (t *PrestateTracer) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	t.create = create
+	t.from = from
+	t.to = to
+	t.value = new(big.Int).Set(value)
+	return nil
+}
+
+// CaptureState implements the Tracer interface.
+func (t *PrestateTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	if t.interrupted() {
+		return nil
+	}
+	// Add the current account if we just started tracing
+	if t.db == nil {
+		t.db = env.StateDB
+		// Balance will potentially be wrong here, since this will include the value
+		// sent along with the message. We fix that in GetResult.
+		t.lookupAccount(contract.Address())
+	}
+	// Whenever new state is accessed, add it to the prestate
+	switch op {
+	case vm.EXTCODECOPY, vm.EXTCODESIZE, vm.EXTCODEHASH, vm.BALANCE, vm.SELFDESTRUCT:
+		t.lookupAccount(common.BigToAddress(stack.Back(0)))
+	case vm.CALL, vm.CALLCODE, vm.DELEGATECALL, vm.STATICCALL:
+		t.lookupAccount(common.BigToAddress(stack.Back(1)))
+	case vm.SSTORE, vm.SLOAD:
+		t.lookupAccount(contract.Address())
+		t.lookupStorage(contract.Address(), common.BigToHash(stack.Back(0)))
+	}
+	return nil
+}
+
+// CaptureFault implements the Tracer interface.
+func (t *PrestateTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	return nil
+}
+
+// CaptureEnd implements the Tracer interface.
+func (t *PrestateTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) error {
+	return nil
+}
+
+// GetResult returns the touched accounts before the execution.
+func (t *PrestateTracer) GetResult() (json.RawMessage, error) {
+	if t.interrupted() {
+		return nil, t.reason
+	}
+	// no code executed
+	if t.db == nil {
+		return json.Marshal(t.prestate)
+	}
+	// At this point, we need to deduct the 'value' from the
+	// outer transaction, and move it back to the origin
+	t.lookupAccount(t.from)
+	if t.value != nil && t.value.Sign() != 0 {
+		from, to := t.prestate[t.from], t.prestate[t.to]
+		if t.token == tx.TOKEN_METER_GOV {
+			from.Balance = (*hexutil.Big)(new(big.Int).Add(from.Balance.ToInt(), t.value))
+			to.Balance = (*hexutil.Big)(new(big.Int).Sub(to.Balance.ToInt(), t.value))
+		} else {
+			from.Energy = (*hexutil.Big)(new(big.Int).Add(from.Energy.ToInt(), t.value))
+			to.Energy = (*hexutil.Big)(new(big.Int).Sub(to.Energy.ToInt(), t.value))
+		}
+	}
+	if t.create {
+		// We can blindly delete the contract prestate, as any existing state would
+		// have caused the transaction to be rejected as invalid in the first place.
+		delete(t.prestate, t.to)
+	}
+	return json.Marshal(t.prestate)
+}
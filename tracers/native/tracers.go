@@ -0,0 +1,67 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+// Package native is a collection of tracers written in go, they produce the
+// same results as their JavaScript counterparts without the duktape overhead.
+package native
+
+import (
+	"encoding/json"
+	"math/big"
+	"sync/atomic"
+
+	"github.com/dfinlab/meter/vm"
+)
+
+// Tracer is a native tracer producing a json result.
+type Tracer interface {
+	vm.Tracer
+	// GetResult returns the json encoded result of the tracing.
+	GetResult() (json.RawMessage, error)
+	// Stop interrupts the tracing, GetResult returns err afterwards.
+	Stop(err error)
+}
+
+// all contains the constructors of the native tracers by name.
+var all = map[string]func() Tracer{
+	"callTracer":     func() Tracer { return NewCallTracer() },
+	"prestateTracer": func() Tracer { return NewPrestateTracer() },
+	"4byteTracer":    func() Tracer { return New4ByteTracer() },
+}
+
+// New creates the native tracer with the given name.
+func New(name string) (Tracer, bool) {
+	if ctor, ok := all[name]; ok {
+		return ctor(), true
+	}
+	return nil, false
+}
+
+// interrupter implements Stop.
+type interrupter struct {
+	interrupt uint32 // Atomic flag to signal execution interruption
+	reason    error  // Reason for the interruption
+}
+
+func (i *interrupter) Stop(err error) {
+	i.reason = err
+	atomic.StoreUint32(&i.interrupt, 1)
+}
+
+func (i *interrupter) interrupted() bool {
+	return atomic.LoadUint32(&i.interrupt) > 0
+}
+
+// memorySlice returns a copy of the memory range, or nil if out of bound.
+func memorySlice(memory *vm.Memory, offset, size *big.Int) []byte {
+	if !offset.IsInt64() || !size.IsInt64() {
+		return nil
+	}
+	off, n := offset.Int64(), size.Int64()
+	if n == 0 || int64(memory.Len()) < off+n {
+		return nil
+	}
+	return memory.Get(off, n)
+}
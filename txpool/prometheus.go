@@ -0,0 +1,15 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package txpool
+
+import "github.com/dfinlab/meter/metric"
+
+var (
+	poolSizeGauge    = metric.NewGauge("txpool_size", "Number of txs in pool")
+	executablesGauge = metric.NewGauge("txpool_executables", "Number of executable txs after last wash")
+	washDurationHist = metric.NewHistogram("txpool_wash_duration_seconds", "Duration of washing txs")
+	washedCounter    = metric.NewCounter("txpool_washed_total", "Counter of txs washed out")
+)
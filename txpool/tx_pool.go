@@ -111,6 +111,7 @@ func (p *TxPool) housekeeping() {
 				continue
 			}
 			poolLen := p.all.Len()
+			poolSizeGauge.Set(float64(poolLen))
 			log.Debug("wash start", "poolLen", poolLen)
 			// do wash on
 			// 1. head block changed
@@ -125,6 +126,8 @@ func (p *TxPool) housekeeping() {
 				startTime := mclock.Now()
 				executables, removed, err := p.wash(headBlock)
 				elapsed := mclock.Now() - startTime
+				washDurationHist.Observe(time.Duration(elapsed).Seconds())
+				washedCounter.Add(float64(removed))
 
 				ctx := []interface{}{
 					"len", poolLen,
@@ -135,6 +138,7 @@ func (p *TxPool) housekeeping() {
 					ctx = append(ctx, "err", err)
 				} else {
 					p.executables.Store(executables)
+					executablesGauge.Set(float64(len(executables)))
 				}
 
 				log.Debug("wash done", ctx...)
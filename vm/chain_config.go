@@ -0,0 +1,67 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package vm
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// ChainConfig extends the eth ChainConfig with the forks unknown to the
+// pinned go-ethereum version.
+type ChainConfig struct {
+	params.ChainConfig
+	IstanbulBlock *big.Int `json:"istanbulBlock,omitempty"` // Istanbul switch block (nil = no fork, 0 = already on istanbul)
+	BerlinBlock   *big.Int `json:"berlinBlock,omitempty"`   // Berlin switch block (nil = no fork, 0 = already on berlin)
+}
+
+// IsIstanbul returns whether num is either equal to the Istanbul fork block or greater.
+func (c *ChainConfig) IsIstanbul(num *big.Int) bool {
+	return isForked(c.IstanbulBlock, num)
+}
+
+// IsBerlin returns whether num is either equal to the Berlin fork block or greater.
+func (c *ChainConfig) IsBerlin(num *big.Int) bool {
+	return isForked(c.BerlinBlock, num)
+}
+
+// GasTable returns the gas table corresponding to the current phase.
+func (c *ChainConfig) GasTable(num *big.Int) params.GasTable {
+	switch {
+	case num == nil:
+		return c.ChainConfig.GasTable(num)
+	case c.IsBerlin(num):
+		return GasTableBerlin
+	case c.IsIstanbul(num):
+		return GasTableIstanbul
+	default:
+		return c.ChainConfig.GasTable(num)
+	}
+}
+
+// Rules extends the eth Rules with the forks of ChainConfig.
+type Rules struct {
+	params.Rules
+	IsIstanbul, IsBerlin bool
+}
+
+// Rules returns the rules of the phase at block num.
+func (c *ChainConfig) Rules(num *big.Int) Rules {
+	return Rules{
+		Rules:      c.ChainConfig.Rules(num),
+		IsIstanbul: c.IsIstanbul(num),
+		IsBerlin:   c.IsBerlin(num),
+	}
+}
+
+// isForked returns whether a fork scheduled at block s is active at the given head block.
+func isForked(s, head *big.Int) bool {
+	if s == nil || head == nil {
+		return false
+	}
+	return s.Cmp(head) <= 0
+}
@@ -18,9 +18,13 @@ package vm
 
 import (
 	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
 	"errors"
 	"math/big"
+	"sync"
 
+	bls "github.com/dfinlab/meter/crypto/multi_sig"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -59,6 +63,68 @@ var PrecompiledContractsByzantium = map[common.Address]PrecompiledContract{
 	common.BytesToAddress([]byte{8}): &bn256Pairing{},
 }
 
+// PrecompiledContractsIstanbul contains the default set of pre-compiled Ethereum
+// contracts used in the Istanbul release.
+var PrecompiledContractsIstanbul = map[common.Address]PrecompiledContract{
+	common.BytesToAddress([]byte{1}): &ecrecover{},
+	common.BytesToAddress([]byte{2}): &sha256hash{},
+	common.BytesToAddress([]byte{3}): &ripemd160hash{},
+	common.BytesToAddress([]byte{4}): &dataCopy{},
+	common.BytesToAddress([]byte{5}): &bigModExp{},
+	common.BytesToAddress([]byte{6}): &bn256AddIstanbul{},
+	common.BytesToAddress([]byte{7}): &bn256ScalarMulIstanbul{},
+	common.BytesToAddress([]byte{8}): &bn256PairingIstanbul{},
+	common.BytesToAddress([]byte{9}): &blake2F{},
+}
+
+// PrecompiledContractsBerlin contains the default set of pre-compiled Ethereum
+// contracts used in the Berlin release.
+var PrecompiledContractsBerlin = map[common.Address]PrecompiledContract{
+	common.BytesToAddress([]byte{1}): &ecrecover{},
+	common.BytesToAddress([]byte{2}): &sha256hash{},
+	common.BytesToAddress([]byte{3}): &ripemd160hash{},
+	common.BytesToAddress([]byte{4}): &dataCopy{},
+	common.BytesToAddress([]byte{5}): &bigModExp{eip2565: true},
+	common.BytesToAddress([]byte{6}): &bn256AddIstanbul{},
+	common.BytesToAddress([]byte{7}): &bn256ScalarMulIstanbul{},
+	common.BytesToAddress([]byte{8}): &bn256PairingIstanbul{},
+	common.BytesToAddress([]byte{9}): &blake2F{},
+
+	// meter specific, out of the range reserved by ethereum
+	BlsVerifyAddress:   &blsVerify{},
+	BlsRegistryAddress: &blsRegister{},
+}
+
+var (
+	// BlsVerifyAddress is the address of the precompiled contract verifying
+	// meter BLS aggregate signatures.
+	BlsVerifyAddress = common.BytesToAddress([]byte{1, 0})
+
+	// BlsRegistryAddress is the address of the precompiled contract registering
+	// BLS public keys by proof of possession, the registry is kept in its storage.
+	BlsRegistryAddress = common.BytesToAddress([]byte{1, 1})
+)
+
+// statefulPrecompiledContract is the meter precompiled contract which reads or
+// writes the state, it's run by RunWithState instead of Run.
+type statefulPrecompiledContract interface {
+	PrecompiledContract
+	RunWithState(evm *EVM, input []byte) ([]byte, error)
+}
+
+// runPrecompiledContract runs p, with the state if it's stateful.
+func runPrecompiledContract(evm *EVM, p PrecompiledContract, input []byte, contract *Contract) (ret []byte, err error) {
+	sp, ok := p.(statefulPrecompiledContract)
+	if !ok {
+		return RunPrecompiledContract(p, input, contract)
+	}
+	gas := p.RequiredGas(input)
+	if contract.UseGas(gas) {
+		return sp.RunWithState(evm, input)
+	}
+	return nil, ErrOutOfGas
+}
+
 // RunPrecompiledContract runs and evaluates the output of a precompiled contract.
 func RunPrecompiledContract(p PrecompiledContract, input []byte, contract *Contract) (ret []byte, err error) {
 	gas := p.RequiredGas(input)
@@ -147,11 +213,15 @@ func (c *dataCopy) Run(in []byte) ([]byte, error) {
 }
 
 // bigModExp implements a native big integer exponential modular operation.
-type bigModExp struct{}
+type bigModExp struct {
+	eip2565 bool // use the repriced formula of EIP 2565 (berlin)
+}
 
 var (
 	big1      = big.NewInt(1)
+	big3      = big.NewInt(3)
 	big4      = big.NewInt(4)
+	big7      = big.NewInt(7)
 	big8      = big.NewInt(8)
 	big16     = big.NewInt(16)
 	big32     = big.NewInt(32)
@@ -200,6 +270,22 @@ func (c *bigModExp) RequiredGas(input []byte) uint64 {
 
 	// Calculate the gas cost of the operation
 	gas := new(big.Int).Set(math.BigMax(modLen, baseLen))
+	if c.eip2565 {
+		// EIP 2565: complexity is ceil(max_length / 8) ^ 2, divided by 3
+		// with a minimum of 200 gas.
+		gas.Add(gas, big7)
+		gas.Div(gas, big8)
+		gas.Mul(gas, gas)
+		gas.Mul(gas, math.BigMax(adjExpLen, big1))
+		gas.Div(gas, big3)
+		if gas.BitLen() > 64 {
+			return math.MaxUint64
+		}
+		if gas.Uint64() < 200 {
+			return 200
+		}
+		return gas.Uint64()
+	}
 	switch {
 	case gas.Cmp(big64) <= 0:
 		gas.Mul(gas, gas)
@@ -358,3 +444,266 @@ func (c *bn256Pairing) Run(input []byte) ([]byte, error) {
 	}
 	return false32Byte, nil
 }
+
+// bn256AddIstanbul implements a native elliptic curve point addition
+// conforming to Istanbul consensus rules (EIP 1108).
+type bn256AddIstanbul struct {
+	bn256Add
+}
+
+// RequiredGas returns the gas required to execute the pre-compiled contract.
+func (c *bn256AddIstanbul) RequiredGas(input []byte) uint64 {
+	return Bn256AddGasIstanbul
+}
+
+// bn256ScalarMulIstanbul implements a native elliptic curve scalar
+// multiplication conforming to Istanbul consensus rules (EIP 1108).
+type bn256ScalarMulIstanbul struct {
+	bn256ScalarMul
+}
+
+// RequiredGas returns the gas required to execute the pre-compiled contract.
+func (c *bn256ScalarMulIstanbul) RequiredGas(input []byte) uint64 {
+	return Bn256ScalarMulGasIstanbul
+}
+
+// bn256PairingIstanbul implements a pairing pre-compile for the bn256 curve
+// conforming to Istanbul consensus rules (EIP 1108).
+type bn256PairingIstanbul struct {
+	bn256Pairing
+}
+
+// RequiredGas returns the gas required to execute the pre-compiled contract.
+func (c *bn256PairingIstanbul) RequiredGas(input []byte) uint64 {
+	return Bn256PairingBaseGasIstanbul + uint64(len(input)/192)*Bn256PairingPerPointGasIstanbul
+}
+
+const blake2FInputLength = 213
+
+var (
+	errBlake2FInvalidInputLength = errors.New("invalid input length")
+	errBlake2FInvalidFinalFlag   = errors.New("invalid final flag")
+)
+
+// blake2F implements the BLAKE2b compression function F (EIP 152).
+type blake2F struct{}
+
+// RequiredGas returns the gas required to execute the pre-compiled contract.
+func (c *blake2F) RequiredGas(input []byte) uint64 {
+	// If the input is malformed, we can't calculate the gas, return 0 and let the
+	// actual call choke and fault.
+	if len(input) != blake2FInputLength {
+		return 0
+	}
+	return uint64(binary.BigEndian.Uint32(input[0:4])) * Blake2FGasPerRound
+}
+
+func (c *blake2F) Run(input []byte) ([]byte, error) {
+	// Make sure the input is valid (correct length and final flag)
+	if len(input) != blake2FInputLength {
+		return nil, errBlake2FInvalidInputLength
+	}
+	if input[212] != 0 && input[212] != 1 {
+		return nil, errBlake2FInvalidFinalFlag
+	}
+	// Parse the input into the Blake2b call parameters
+	var (
+		rounds = binary.BigEndian.Uint32(input[0:4])
+		final  = input[212] == 1
+		h      [8]uint64
+		m      [16]uint64
+		t      [2]uint64
+	)
+	for i := 0; i < 8; i++ {
+		h[i] = binary.LittleEndian.Uint64(input[4+i*8:])
+	}
+	for i := 0; i < 16; i++ {
+		m[i] = binary.LittleEndian.Uint64(input[68+i*8:])
+	}
+	t[0] = binary.LittleEndian.Uint64(input[196:204])
+	t[1] = binary.LittleEndian.Uint64(input[204:212])
+
+	// Execute the compression function, extract and return the result
+	blake2bF(&h, &m, t, final, rounds)
+
+	output := make([]byte, 64)
+	for i := 0; i < 8; i++ {
+		binary.LittleEndian.PutUint64(output[i*8:], h[i])
+	}
+	return output, nil
+}
+
+// blsElementLength is the length of a compressed G1 element (signature or
+// public key) of the meter pairing.
+const blsElementLength = 65
+
+var (
+	errBadBlsVerifyInput = errors.New("bad bls verify input")
+	errBlsStateRequired  = errors.New("bls precompiled contract requires the state")
+
+	blsSystemOnce sync.Once
+	blsSystem     bls.System
+	blsSystemErr  error
+)
+
+// getBlsSystem loads the BLS system shared by meter nodes.
+func getBlsSystem() (bls.System, error) {
+	blsSystemOnce.Do(func() {
+		paramsBytes, err := hex.DecodeString(bls.ParamsHex)
+		if err != nil {
+			blsSystemErr = err
+			return
+		}
+		params, err := bls.ParamsFromBytes(paramsBytes)
+		if err != nil {
+			blsSystemErr = err
+			return
+		}
+		systemBytes, err := hex.DecodeString(bls.SystemHex)
+		if err != nil {
+			blsSystemErr = err
+			return
+		}
+		blsSystem, blsSystemErr = bls.SystemFromBytes(bls.GenPairing(params), systemBytes)
+	})
+	return blsSystem, blsSystemErr
+}
+
+// blsRegistryKey returns the storage key of the public key in the registry.
+func blsRegistryKey(pubKey []byte) common.Hash {
+	return crypto.Keccak256Hash(pubKey)
+}
+
+// blsRegistered is the storage value of a registered public key.
+var blsRegistered = common.BytesToHash([]byte{1})
+
+// blsRegister implements the registration of a BLS public key, once the proof
+// of possession is verified. Registered keys are safe to aggregate for the same
+// message, so blsVerify only accepts them, and proofs are checked only once.
+//
+// The input is the compressed public key followed by the compressed proof of
+// possession, 65 bytes each. It returns 32 bytes of 1 if the key is registered,
+// 0 if the proof is invalid.
+type blsRegister struct{}
+
+// RequiredGas returns the gas required to execute the pre-compiled contract.
+func (c *blsRegister) RequiredGas(input []byte) uint64 {
+	// two pairings for the proof, and the storage of the key
+	return BlsVerifyBaseGas + 2*BlsVerifyPerPairingGas + SstoreSetGasEIP2200
+}
+
+func (c *blsRegister) Run(input []byte) ([]byte, error) {
+	return nil, errBlsStateRequired
+}
+
+func (c *blsRegister) RunWithState(evm *EVM, input []byte) ([]byte, error) {
+	if evm.interpreter.readOnly {
+		return nil, errWriteProtection
+	}
+	if len(input) != 2*blsElementLength {
+		return nil, errBadBlsVerifyInput
+	}
+	system, err := getBlsSystem()
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := system.PubKeyFromBytes(input[:blsElementLength])
+	if err != nil {
+		return nil, errBadBlsVerifyInput
+	}
+	defer key.Free()
+	proof, err := system.SigFromBytes(input[blsElementLength:])
+	if err != nil {
+		return nil, errBadBlsVerifyInput
+	}
+	defer proof.Free()
+	if !key.InSubgroup() || !proof.InSubgroup() || !bls.VerifyPossession(proof, key) {
+		return false32Byte, nil
+	}
+	evm.StateDB.SetState(BlsRegistryAddress, blsRegistryKey(input[:blsElementLength]), blsRegistered)
+	return true32Byte, nil
+}
+
+// blsVerify implements the verification of an aggregated BLS signature, as
+// carried by meter QCs, over a message hash signed by every signer.
+//
+// The input is the 32 bytes message hash, followed by the compressed aggregated
+// signature, and then the compressed public key of each signer, 65 bytes each.
+// Since every signer signs the same message, keys must be registered by
+// blsRegister beforehand, which rules out rogue keys.
+// It returns 32 bytes of 1 if the signature is valid, 0 otherwise, or if any
+// key is not registered.
+type blsVerify struct{}
+
+// RequiredGas returns the gas required to execute the pre-compiled contract.
+func (c *blsVerify) RequiredGas(input []byte) uint64 {
+	if len(input) < 32+blsElementLength {
+		return BlsVerifyBaseGas
+	}
+	// a registry lookup per key, one pairing for the signature and one per key
+	signers := uint64((len(input) - 32 - blsElementLength) / blsElementLength)
+	return BlsVerifyBaseGas + signers*ColdSloadCostEIP2929 + (1+signers)*BlsVerifyPerPairingGas
+}
+
+func (c *blsVerify) Run(input []byte) ([]byte, error) {
+	return nil, errBlsStateRequired
+}
+
+func (c *blsVerify) RunWithState(evm *EVM, input []byte) ([]byte, error) {
+	// at least one signer is required
+	if len(input) < 32+2*blsElementLength || (len(input)-32)%blsElementLength != 0 {
+		return nil, errBadBlsVerifyInput
+	}
+	system, err := getBlsSystem()
+	if err != nil {
+		return nil, err
+	}
+
+	var hash [sha256.Size]byte
+	copy(hash[:], input[:32])
+	input = input[32:]
+
+	sig, err := system.SigFromBytes(input[:blsElementLength])
+	if err != nil {
+		return nil, errBadBlsVerifyInput
+	}
+	defer sig.Free()
+	if !sig.InSubgroup() {
+		return false32Byte, nil
+	}
+	input = input[blsElementLength:]
+
+	var (
+		n      = len(input) / blsElementLength
+		hashes = make([][sha256.Size]byte, 0, n)
+		keys   = make([]bls.PublicKey, 0, n)
+	)
+	defer func() {
+		for _, key := range keys {
+			key.Free()
+		}
+	}()
+	for i := 0; i < n; i++ {
+		element := input[i*blsElementLength : (i+1)*blsElementLength]
+		if evm.StateDB.GetState(BlsRegistryAddress, blsRegistryKey(element)) != blsRegistered {
+			return false32Byte, nil
+		}
+		// registered keys are well-formed and in the subgroup
+		key, err := system.PubKeyFromBytes(element)
+		if err != nil {
+			return nil, errBadBlsVerifyInput
+		}
+		keys = append(keys, key)
+		hashes = append(hashes, hash)
+	}
+
+	valid, err := bls.AggregateVerify(sig, hashes, keys)
+	if err != nil {
+		return nil, err
+	}
+	if valid {
+		return true32Byte, nil
+	}
+	return false32Byte, nil
+}
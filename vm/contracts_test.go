@@ -17,11 +17,14 @@
 package vm
 
 import (
+	"bytes"
 	"fmt"
 	"math/big"
 	"testing"
 
+	bls "github.com/dfinlab/meter/crypto/multi_sig"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
 )
 
 // precompiledTest defines the input/output pairs for precompiled contract tests.
@@ -336,8 +339,26 @@ var bn256PairingTests = []precompiledTest{
 	},
 }
 
+// blake2FTests are the test vectors of EIP 152.
+var blake2FTests = []precompiledTest{
+	{
+		input:    "0000000c48c9bdf267e6096a3ba7ca8485ae67bb2bf894fe72f36e3cf1361d5f3af54fa5d182e6ad7f520e511f6c3e2b8c68059b6bbd41fbabd9831f79217e1319cde05b61626300000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000300000000000000000000000000000001",
+		expected: "ba80a53f981c4d0d6a2797b69f12f6e94c212f14685ac4b74b12bb6fdbffa2d17d87c5392aab792dc252d5de4533cc9518d38aa8dbf1925ab92386edd4009923",
+		name:     "vector 4",
+	},
+	{
+		input:    "0000000048c9bdf267e6096a3ba7ca8485ae67bb2bf894fe72f36e3cf1361d5f3af54fa5d182e6ad7f520e511f6c3e2b8c68059b6bbd41fbabd9831f79217e1319cde05b61626300000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000300000000000000000000000000000001",
+		expected: "08c9bcf367e6096a3ba7ca8485ae67bb2bf894fe72f36e3cf1361d5f3af54fa5d282e6ad7f520e511f6c3e2b8c68059b9442be0454267ce079217e1319cde05b",
+		name:     "vector 5",
+	},
+}
+
 func testPrecompiled(addr string, test precompiledTest, t *testing.T) {
-	p := PrecompiledContractsByzantium[common.HexToAddress(addr)]
+	testPrecompiledWith(PrecompiledContractsByzantium, addr, test, t)
+}
+
+func testPrecompiledWith(precompiles map[common.Address]PrecompiledContract, addr string, test precompiledTest, t *testing.T) {
+	p := precompiles[common.HexToAddress(addr)]
 	in := common.Hex2Bytes(test.input)
 	contract := NewContract(AccountRef(common.HexToAddress("1337")),
 		nil, new(big.Int), p.RequiredGas(in))
@@ -481,3 +502,159 @@ func BenchmarkPrecompiledBn256Pairing(bench *testing.B) {
 		benchmarkPrecompiled("08", test, bench)
 	}
 }
+
+// Tests the sample inputs from the BLAKE2 F compression EIP 152.
+func TestPrecompiledBlake2F(t *testing.T) {
+	for _, test := range blake2FTests {
+		testPrecompiledWith(PrecompiledContractsIstanbul, "09", test, t)
+	}
+	p := PrecompiledContractsIstanbul[common.HexToAddress("09")]
+	in := common.Hex2Bytes(blake2FTests[0].input)
+	if gas := p.RequiredGas(in); gas != 12 {
+		t.Errorf("Expected 12 gas, got %d", gas)
+	}
+	if _, err := p.Run(in[:len(in)-1]); err != errBlake2FInvalidInputLength {
+		t.Errorf("Expected %v, got %v", errBlake2FInvalidInputLength, err)
+	}
+	in[len(in)-1] = 2
+	if _, err := p.Run(in); err != errBlake2FInvalidFinalFlag {
+		t.Errorf("Expected %v, got %v", errBlake2FInvalidFinalFlag, err)
+	}
+}
+
+// Tests the repricing of the precompiled contracts across forks, with
+// identical outputs.
+func TestPrecompiledRepricing(t *testing.T) {
+	tests := []struct {
+		addr      string
+		test      precompiledTest
+		byzantium uint64
+		istanbul  uint64
+		berlin    uint64
+	}{
+		{"05", modexpTests[0], 13056, 13056, 1360},
+		{"06", bn256AddTests[0], 500, 150, 150},
+		{"07", bn256ScalarMulTests[0], 40000, 6000, 6000},
+	}
+	for _, test := range tests {
+		in := common.Hex2Bytes(test.test.input)
+		addr := common.HexToAddress(test.addr)
+		for _, c := range []struct {
+			precompiles map[common.Address]PrecompiledContract
+			gas         uint64
+		}{
+			{PrecompiledContractsByzantium, test.byzantium},
+			{PrecompiledContractsIstanbul, test.istanbul},
+			{PrecompiledContractsBerlin, test.berlin},
+		} {
+			if gas := c.precompiles[addr].RequiredGas(in); gas != c.gas {
+				t.Errorf("%s: expected %d gas, got %d", test.test.name, c.gas, gas)
+			}
+			testPrecompiledWith(c.precompiles, test.addr, test.test, t)
+		}
+	}
+}
+
+// registryStateDB keeps the storage of the bls registry only.
+type registryStateDB struct {
+	StateDB
+	storage map[common.Hash]common.Hash
+}
+
+func (s *registryStateDB) GetState(addr common.Address, key common.Hash) common.Hash {
+	if addr != BlsRegistryAddress {
+		return common.Hash{}
+	}
+	return s.storage[key]
+}
+
+func (s *registryStateDB) SetState(addr common.Address, key, value common.Hash) {
+	if addr == BlsRegistryAddress {
+		s.storage[key] = value
+	}
+}
+
+func TestPrecompiledBlsVerify(t *testing.T) {
+	system, err := getBlsSystem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	evm := NewEVM(Context{}, &registryStateDB{storage: make(map[common.Hash]common.Hash)}, &ChainConfig{ChainConfig: *params.TestChainConfig}, Config{})
+	register := PrecompiledContractsBerlin[BlsRegistryAddress].(statefulPrecompiledContract)
+	verify := PrecompiledContractsBerlin[BlsVerifyAddress].(statefulPrecompiledContract)
+	hash := [32]byte{1, 2, 3}
+
+	var (
+		sigs   []bls.Signature
+		keys   []byte
+		proofs [][]byte
+	)
+	for i := 0; i < 3; i++ {
+		pubKey, privKey, err := bls.GenKeys(system)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sigs = append(sigs, bls.Sign(hash, privKey))
+		keys = append(keys, system.PubKeyToBytes(pubKey)...)
+		proofs = append(proofs, system.SigToBytes(bls.ProvePossession(privKey, pubKey)))
+	}
+	sig, err := bls.Aggregate(sigs, system)
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := append(append(hash[:], system.SigToBytes(sig)...), keys...)
+	keyAt := func(i int) []byte { return keys[i*blsElementLength : (i+1)*blsElementLength] }
+
+	if gas, expected := verify.RequiredGas(input), BlsVerifyBaseGas+3*ColdSloadCostEIP2929+4*BlsVerifyPerPairingGas; gas != expected {
+		t.Errorf("Expected %d gas, got %d", expected, gas)
+	}
+	if _, err := verify.Run(input); err != errBlsStateRequired {
+		t.Errorf("Expected %v, got %v", errBlsStateRequired, err)
+	}
+
+	// not registered yet
+	if res, err := verify.RunWithState(evm, input); err != nil || !bytes.Equal(res, false32Byte) {
+		t.Errorf("Expected unregistered keys, got %x %v", res, err)
+	}
+	// proof of another key
+	if res, err := register.RunWithState(evm, append(append([]byte{}, keyAt(0)...), proofs[1]...)); err != nil || !bytes.Equal(res, false32Byte) {
+		t.Errorf("Expected invalid proof of possession, got %x %v", res, err)
+	}
+	// no registration in static calls
+	evm.interpreter.readOnly = true
+	if _, err := register.RunWithState(evm, append(append([]byte{}, keyAt(0)...), proofs[0]...)); err != errWriteProtection {
+		t.Errorf("Expected %v, got %v", errWriteProtection, err)
+	}
+	evm.interpreter.readOnly = false
+	for i := 0; i < 3; i++ {
+		if res, err := register.RunWithState(evm, append(append([]byte{}, keyAt(i)...), proofs[i]...)); err != nil || !bytes.Equal(res, true32Byte) {
+			t.Errorf("Expected key %d registered, got %x %v", i, res, err)
+		}
+	}
+
+	if res, err := verify.RunWithState(evm, input); err != nil || !bytes.Equal(res, true32Byte) {
+		t.Errorf("Expected valid signature, got %x %v", res, err)
+	}
+	// missing signer
+	if res, err := verify.RunWithState(evm, input[:len(input)-blsElementLength]); err != nil || !bytes.Equal(res, false32Byte) {
+		t.Errorf("Expected invalid signature, got %x %v", res, err)
+	}
+	// other message
+	other := append([]byte{}, input...)
+	other[0] ^= 0xff
+	if res, err := verify.RunWithState(evm, other); err != nil || !bytes.Equal(res, false32Byte) {
+		t.Errorf("Expected invalid signature, got %x %v", res, err)
+	}
+	// malformed
+	if _, err := verify.RunWithState(evm, input[:32+blsElementLength]); err != errBadBlsVerifyInput {
+		t.Errorf("Expected %v, got %v", errBadBlsVerifyInput, err)
+	}
+	if _, err := verify.RunWithState(evm, input[:len(input)-1]); err != errBadBlsVerifyInput {
+		t.Errorf("Expected %v, got %v", errBadBlsVerifyInput, err)
+	}
+	for _, addr := range []common.Address{BlsVerifyAddress, BlsRegistryAddress} {
+		if _, ok := PrecompiledContractsIstanbul[addr]; ok {
+			t.Errorf("bls precompiles should not be active before berlin")
+		}
+	}
+}
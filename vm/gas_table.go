@@ -17,6 +17,8 @@
 package vm
 
 import (
+	"errors"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/params"
@@ -137,6 +139,54 @@ func gasSStore(gt params.GasTable, evm *EVM, contract *Contract, stack *Stack, m
 	}
 }
 
+var errSStoreSentry = errors.New("not enough gas for reentrancy sentry")
+
+// gasSStoreEIP2200 implements the net gas metering of EIP 2200 (istanbul).
+// Writes are charged against the original value of the slot at the beginning
+// of the execution, so that a dirty slot only costs SLOAD_GAS, and resetting a
+// slot to its original value is refunded.
+func gasSStoreEIP2200(gt params.GasTable, evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
+	// If we fail the minimum gas availability invariant, fail (0)
+	if contract.Gas <= SstoreSentryGasEIP2200 {
+		return 0, errSStoreSentry
+	}
+	// Gas sentry honoured, do the actual gas calculation based on the stored value
+	var (
+		y, x    = stack.Back(1), stack.Back(0)
+		slot    = common.BigToHash(x)
+		current = evm.StateDB.GetState(contract.Address(), slot)
+		value   = common.BigToHash(y)
+	)
+	if current == value { // noop (1)
+		return SloadGasEIP2200, nil
+	}
+	original := evm.StateDB.GetCommittedState(contract.Address(), slot)
+	if original == current {
+		if original == (common.Hash{}) { // create slot (2.1.1)
+			return SstoreSetGasEIP2200, nil
+		}
+		if value == (common.Hash{}) { // delete slot (2.1.2b)
+			evm.StateDB.AddRefund(SstoreClearsScheduleRefundEIP2200)
+		}
+		return SstoreResetGasEIP2200, nil // write existing slot (2.1.2)
+	}
+	if original != (common.Hash{}) {
+		if current == (common.Hash{}) { // recreate slot (2.2.1.1)
+			evm.StateDB.SubRefund(SstoreClearsScheduleRefundEIP2200)
+		} else if value == (common.Hash{}) { // delete slot (2.2.1.2)
+			evm.StateDB.AddRefund(SstoreClearsScheduleRefundEIP2200)
+		}
+	}
+	if original == value {
+		if original == (common.Hash{}) { // reset to original inexistent slot (2.2.2.1)
+			evm.StateDB.AddRefund(SstoreSetGasEIP2200 - SloadGasEIP2200)
+		} else { // reset to original existing slot (2.2.2.2)
+			evm.StateDB.AddRefund(SstoreResetGasEIP2200 - SloadGasEIP2200)
+		}
+	}
+	return SloadGasEIP2200, nil // dirty update (2.2)
+}
+
 func makeGasLog(n uint64) gasFunc {
 	return func(gt params.GasTable, evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
 		requestedSize, overflow := bigUint64(stack.Back(1))
@@ -580,6 +580,21 @@ func opGasLimit(pc *uint64, evm *EVM, contract *Contract, memory *Memory, stack
 	return nil, nil
 }
 
+func opChainID(pc *uint64, evm *EVM, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
+	chainID := evm.interpreter.intPool.getZero()
+	if evm.chainConfig.ChainID != nil {
+		chainID.Set(evm.chainConfig.ChainID)
+	}
+	stack.push(math.U256(chainID))
+	return nil, nil
+}
+
+func opSelfBalance(pc *uint64, evm *EVM, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
+	// same as opBalance, points to meter
+	stack.push(new(big.Int).Set(evm.StateDB.GetEnergy(contract.Address())))
+	return nil, nil
+}
+
 func opPop(pc *uint64, evm *EVM, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
 	evm.interpreter.intPool.put(stack.pop())
 	return nil, nil
@@ -44,8 +44,10 @@ type StateDB interface {
 	GetCodeSize(common.Address) int
 
 	AddRefund(uint64)
+	SubRefund(uint64)
 	GetRefund() uint64
 
+	GetCommittedState(common.Address, common.Hash) common.Hash
 	GetState(common.Address, common.Hash) common.Hash
 	SetState(common.Address, common.Hash, common.Hash)
 
@@ -62,6 +64,12 @@ type StateDB interface {
 	RevertToSnapshot(int)
 	Snapshot() int
 
+	// Access list of EIP-2929, only touched after the berlin fork.
+	AddressInAccessList(addr common.Address) bool
+	SlotInAccessList(addr common.Address, slot common.Hash) (addressOk bool, slotOk bool)
+	AddAddressToAccessList(addr common.Address)
+	AddSlotToAccessList(addr common.Address, slot common.Hash)
+
 	AddLog(*types.Log)
 	AddPreimage(common.Hash, []byte)
 
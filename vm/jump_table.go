@@ -55,8 +55,51 @@ var (
 	homesteadInstructionSet      = NewHomesteadInstructionSet()
 	byzantiumInstructionSet      = NewByzantiumInstructionSet()
 	constantinopleInstructionSet = NewConstantinopleInstructionSet()
+	istanbulInstructionSet       = NewIstanbulInstructionSet()
+	berlinInstructionSet         = NewBerlinInstructionSet()
 )
 
+// NewBerlinInstructionSet returns the frontier, homestead, byzantium,
+// constantinople, istanbul and berlin instructions.
+func NewBerlinInstructionSet() [256]operation {
+	// berlin only reprices state access (EIP 2929).
+	instructionSet := NewIstanbulInstructionSet()
+	instructionSet[SLOAD].gasCost = gasSLoadEIP2929
+	instructionSet[SSTORE].gasCost = gasSStoreEIP2929
+	instructionSet[BALANCE].gasCost = makeAccountAccessGasEIP2929(gasBalance, 0)
+	instructionSet[EXTCODESIZE].gasCost = makeAccountAccessGasEIP2929(gasExtCodeSize, 0)
+	instructionSet[EXTCODECOPY].gasCost = makeAccountAccessGasEIP2929(gasExtCodeCopy, 0)
+	instructionSet[EXTCODEHASH].gasCost = makeAccountAccessGasEIP2929(gasExtCodeHash, 0)
+	instructionSet[CALL].gasCost = makeCallGasEIP2929(gasCall)
+	instructionSet[CALLCODE].gasCost = makeCallGasEIP2929(gasCallCode)
+	instructionSet[DELEGATECALL].gasCost = makeCallGasEIP2929(gasDelegateCall)
+	instructionSet[STATICCALL].gasCost = makeCallGasEIP2929(gasStaticCall)
+	instructionSet[SELFDESTRUCT].gasCost = gasSuicideEIP2929
+	return instructionSet
+}
+
+// NewIstanbulInstructionSet returns the frontier, homestead, byzantium,
+// constantinople and istanbul instructions.
+func NewIstanbulInstructionSet() [256]operation {
+	// instructions that can be executed during the istanbul phase.
+	// repricing of SLOAD, BALANCE and EXTCODEHASH (EIP 1884) goes through the gas table.
+	instructionSet := NewConstantinopleInstructionSet()
+	instructionSet[CHAINID] = operation{
+		execute:       opChainID,
+		gasCost:       constGasFunc(GasQuickStep),
+		validateStack: makeStackFunc(0, 1),
+		valid:         true,
+	}
+	instructionSet[SELFBALANCE] = operation{
+		execute:       opSelfBalance,
+		gasCost:       constGasFunc(GasFastStep),
+		validateStack: makeStackFunc(0, 1),
+		valid:         true,
+	}
+	instructionSet[SSTORE].gasCost = gasSStoreEIP2200
+	return instructionSet
+}
+
 // NewConstantinopleInstructionSet returns the frontier, homestead
 // byzantium and contantinople instructions.
 func NewConstantinopleInstructionSet() [256]operation {
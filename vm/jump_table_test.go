@@ -0,0 +1,78 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func newForkTestEVM(num int64) *EVM {
+	cfg := &ChainConfig{
+		ChainConfig:   *params.TestChainConfig,
+		IstanbulBlock: big.NewInt(10),
+		BerlinBlock:   big.NewInt(20),
+	}
+	cfg.ConstantinopleBlock = big.NewInt(0)
+	return NewEVM(Context{BlockNumber: big.NewInt(num)}, nil, cfg, Config{})
+}
+
+func TestForkInstructionSets(t *testing.T) {
+	tests := []struct {
+		num         int64
+		chainID     bool
+		sload       uint64
+		precompiles map[common.Address]PrecompiledContract
+	}{
+		{9, false, 200, PrecompiledContractsByzantium},
+		{10, true, SloadGasEIP2200, PrecompiledContractsIstanbul},
+		{19, true, SloadGasEIP2200, PrecompiledContractsIstanbul},
+		{20, true, WarmStorageReadCostEIP2929, PrecompiledContractsBerlin},
+	}
+	for _, test := range tests {
+		evm := newForkTestEVM(test.num)
+		jt := evm.interpreter.cfg.JumpTable
+		if jt[CHAINID].valid != test.chainID || jt[SELFBALANCE].valid != test.chainID {
+			t.Errorf("block %d: CHAINID/SELFBALANCE valid = %v, want %v", test.num, jt[CHAINID].valid, test.chainID)
+		}
+		if got := evm.interpreter.gasTable.SLoad; got != test.sload {
+			t.Errorf("block %d: SLOAD gas = %d, want %d", test.num, got, test.sload)
+		}
+		if len(evm.ActivePrecompiles()) != len(test.precompiles) {
+			t.Errorf("block %d: %d precompiles, want %d", test.num, len(evm.ActivePrecompiles()), len(test.precompiles))
+		}
+	}
+}
+
+func TestForkBeforeIstanbulUnchanged(t *testing.T) {
+	evm := newForkTestEVM(9)
+	jt := evm.interpreter.cfg.JumpTable
+	for op := 0; op < 256; op++ {
+		if jt[op].valid != constantinopleInstructionSet[op].valid {
+			t.Errorf("opcode %v: valid = %v before istanbul", OpCode(op), jt[op].valid)
+		}
+	}
+	if evm.interpreter.gasTable != evm.ChainConfig().ChainConfig.GasTable(evm.BlockNumber) {
+		t.Errorf("gas table changed before istanbul: %+v", evm.interpreter.gasTable)
+	}
+}
+
+func TestOpChainID(t *testing.T) {
+	var (
+		cfg   = &ChainConfig{ChainConfig: *params.TestChainConfig}
+		env   = NewEVM(Context{}, nil, cfg, Config{})
+		stack = newstack()
+		pc    = uint64(0)
+	)
+	cfg.ChainID = big.NewInt(82)
+	opChainID(&pc, env, nil, nil, stack)
+	if got := stack.pop(); got.Cmp(big.NewInt(82)) != 0 {
+		t.Errorf("CHAINID = %v, want 82", got)
+	}
+}
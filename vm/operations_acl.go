@@ -0,0 +1,137 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package vm
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// gasSLoadEIP2929 calculates dynamic gas for SLOAD according to EIP-2929.
+// The warm price comes from the gas table, a cold slot costs the difference
+// up to COLD_SLOAD_COST and is added to the access list.
+func gasSLoadEIP2929(gt params.GasTable, evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
+	slot := common.BigToHash(stack.Back(0))
+	if _, slotPresent := evm.StateDB.SlotInAccessList(contract.Address(), slot); !slotPresent {
+		evm.StateDB.AddSlotToAccessList(contract.Address(), slot)
+		return gt.SLoad + ColdSloadCostEIP2929 - WarmStorageReadCostEIP2929, nil
+	}
+	return gt.SLoad, nil
+}
+
+// gasSStoreEIP2929 is gasSStoreEIP2200 with the EIP-2929 cold slot surcharge,
+// and SLOAD_GAS replaced by WARM_STORAGE_READ_COST.
+func gasSStoreEIP2929(gt params.GasTable, evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
+	// If we fail the minimum gas availability invariant, fail (0)
+	if contract.Gas <= SstoreSentryGasEIP2200 {
+		return 0, errSStoreSentry
+	}
+	var (
+		y, x = stack.Back(1), stack.Back(0)
+		slot = common.BigToHash(x)
+		cost = uint64(0)
+	)
+	if _, slotPresent := evm.StateDB.SlotInAccessList(contract.Address(), slot); !slotPresent {
+		cost = ColdSloadCostEIP2929
+		evm.StateDB.AddSlotToAccessList(contract.Address(), slot)
+	}
+	var (
+		current = evm.StateDB.GetState(contract.Address(), slot)
+		value   = common.BigToHash(y)
+	)
+	if current == value { // noop (1)
+		return cost + WarmStorageReadCostEIP2929, nil
+	}
+	original := evm.StateDB.GetCommittedState(contract.Address(), slot)
+	if original == current {
+		if original == (common.Hash{}) { // create slot (2.1.1)
+			return cost + SstoreSetGasEIP2200, nil
+		}
+		if value == (common.Hash{}) { // delete slot (2.1.2b)
+			evm.StateDB.AddRefund(SstoreClearsScheduleRefundEIP2200)
+		}
+		return cost + (SstoreResetGasEIP2200 - ColdSloadCostEIP2929), nil // write existing slot (2.1.2)
+	}
+	if original != (common.Hash{}) {
+		if current == (common.Hash{}) { // recreate slot (2.2.1.1)
+			evm.StateDB.SubRefund(SstoreClearsScheduleRefundEIP2200)
+		} else if value == (common.Hash{}) { // delete slot (2.2.1.2)
+			evm.StateDB.AddRefund(SstoreClearsScheduleRefundEIP2200)
+		}
+	}
+	if original == value {
+		if original == (common.Hash{}) { // reset to original inexistent slot (2.2.2.1)
+			evm.StateDB.AddRefund(SstoreSetGasEIP2200 - WarmStorageReadCostEIP2929)
+		} else { // reset to original existing slot (2.2.2.2)
+			evm.StateDB.AddRefund((SstoreResetGasEIP2200 - ColdSloadCostEIP2929) - WarmStorageReadCostEIP2929)
+		}
+	}
+	return cost + WarmStorageReadCostEIP2929, nil // dirty update (2.2)
+}
+
+// makeAccountAccessGasEIP2929 wraps a gas function of an opcode touching the
+// account at stack position n, charging the EIP-2929 cold account surcharge
+// on first access.
+func makeAccountAccessGasEIP2929(oldCalculator gasFunc, n int) gasFunc {
+	return func(gt params.GasTable, evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
+		addr := common.BigToAddress(stack.Back(n))
+		gas, err := oldCalculator(gt, evm, contract, stack, mem, memorySize)
+		if err != nil || evm.StateDB.AddressInAccessList(addr) {
+			return gas, err
+		}
+		evm.StateDB.AddAddressToAccessList(addr)
+		var overflow bool
+		if gas, overflow = math.SafeAdd(gas, ColdAccountAccessCostEIP2929-WarmStorageReadCostEIP2929); overflow {
+			return 0, errGasUintOverflow
+		}
+		return gas, nil
+	}
+}
+
+// makeCallGasEIP2929 wraps the gas function of the CALL family. The cold
+// surcharge must be taken before the 63/64 rule is applied, so it is deducted
+// from the contract first and handed back once the call gas is known.
+func makeCallGasEIP2929(oldCalculator gasFunc) gasFunc {
+	return func(gt params.GasTable, evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
+		addr := common.BigToAddress(stack.Back(1))
+		warmAccess := evm.StateDB.AddressInAccessList(addr)
+		coldCost := ColdAccountAccessCostEIP2929 - WarmStorageReadCostEIP2929
+		if !warmAccess {
+			evm.StateDB.AddAddressToAccessList(addr)
+			if !contract.UseGas(coldCost) {
+				return 0, ErrOutOfGas
+			}
+		}
+		gas, err := oldCalculator(gt, evm, contract, stack, mem, memorySize)
+		if warmAccess || err != nil {
+			return gas, err
+		}
+		// the cold cost was charged above, give it back and let the
+		// interpreter charge the total.
+		contract.Gas += coldCost
+		var overflow bool
+		if gas, overflow = math.SafeAdd(gas, coldCost); overflow {
+			return 0, errGasUintOverflow
+		}
+		return gas, nil
+	}
+}
+
+// gasSuicideEIP2929 charges COLD_ACCOUNT_ACCESS_COST when the beneficiary is cold.
+func gasSuicideEIP2929(gt params.GasTable, evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
+	addr := common.BigToAddress(stack.Back(0))
+	gas, err := gasSuicide(gt, evm, contract, stack, mem, memorySize)
+	if err != nil || evm.StateDB.AddressInAccessList(addr) {
+		return gas, err
+	}
+	evm.StateDB.AddAddressToAccessList(addr)
+	var overflow bool
+	if gas, overflow = math.SafeAdd(gas, ColdAccountAccessCostEIP2929); overflow {
+		return 0, errGasUintOverflow
+	}
+	return gas, nil
+}
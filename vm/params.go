@@ -0,0 +1,63 @@
+// Copyright (c) 2020 The Meter.io developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package vm
+
+import "github.com/ethereum/go-ethereum/params"
+
+// gas parameters introduced after the pinned go-ethereum version.
+const (
+	SloadGasEIP2200                   uint64 = 800   // Cost of SLOAD after EIP 2200 (part of Istanbul)
+	SstoreSentryGasEIP2200            uint64 = 2300  // Minimum gas required to be present for an SSTORE call, not consumed
+	SstoreSetGasEIP2200               uint64 = 20000 // Once per SSTORE operation from clean zero to non-zero
+	SstoreResetGasEIP2200             uint64 = 5000  // Once per SSTORE operation from clean non-zero to something else
+	SstoreClearsScheduleRefundEIP2200 uint64 = 15000 // Once per SSTORE operation for clearing an originally existing storage slot
+
+	ColdAccountAccessCostEIP2929 uint64 = 2600 // COLD_ACCOUNT_ACCESS_COST
+	ColdSloadCostEIP2929         uint64 = 2100 // COLD_SLOAD_COST
+	WarmStorageReadCostEIP2929   uint64 = 100  // WARM_STORAGE_READ_COST
+
+	Bn256AddGasIstanbul             uint64 = 150   // Gas needed for an elliptic curve addition
+	Bn256ScalarMulGasIstanbul       uint64 = 6000  // Gas needed for an elliptic curve scalar multiplication
+	Bn256PairingBaseGasIstanbul     uint64 = 45000 // Base price for an elliptic curve pairing check
+	Bn256PairingPerPointGasIstanbul uint64 = 34000 // Per-point price for an elliptic curve pairing check
+
+	Blake2FGasPerRound uint64 = 1 // Gas needed per round of the BLAKE2 F compression function
+
+	// the pbc type A pairing of meter is about three times slower than bn256.
+	BlsVerifyBaseGas       uint64 = 45000  // Base price for a meter BLS aggregate signature check
+	BlsVerifyPerPairingGas uint64 = 100000 // Per-pairing price for a meter BLS aggregate signature check
+)
+
+var (
+	// GasTableIstanbul contains the gas prices for the istanbul phase (EIP 1884).
+	GasTableIstanbul = params.GasTable{
+		ExtcodeSize: 700,
+		ExtcodeCopy: 700,
+		ExtcodeHash: 700,
+		Balance:     700,
+		SLoad:       SloadGasEIP2200,
+		Calls:       700,
+		Suicide:     5000,
+		ExpByte:     50,
+
+		CreateBySuicide: 25000,
+	}
+
+	// GasTableBerlin contains the warm access prices for the berlin phase,
+	// the cold access surcharges are added by the EIP 2929 gas functions.
+	GasTableBerlin = params.GasTable{
+		ExtcodeSize: WarmStorageReadCostEIP2929,
+		ExtcodeCopy: WarmStorageReadCostEIP2929,
+		ExtcodeHash: WarmStorageReadCostEIP2929,
+		Balance:     WarmStorageReadCostEIP2929,
+		SLoad:       WarmStorageReadCostEIP2929,
+		Calls:       WarmStorageReadCostEIP2929,
+		Suicide:     5000,
+		ExpByte:     50,
+
+		CreateBySuicide: 25000,
+	}
+)
@@ -91,6 +91,16 @@ func (env *Environment) UseGas(gas uint64) {
 	}
 }
 
+// Fail fails the native call with err, all gas of the call is consumed.
+func (env *Environment) Fail(err error) {
+	panic(&nativeError{err})
+}
+
+// nativeError is the error a native call fails with.
+type nativeError struct {
+	error
+}
+
 func (env *Environment) ParseArgs(val interface{}) {
 	if err := env.abi.DecodeInput(env.contract.Input, val); err != nil {
 		// as vm error
@@ -122,6 +132,8 @@ func (env *Environment) Call(proc func(env *Environment) []interface{}) (output
 		if e := recover(); e != nil {
 			if e == vm.ErrOutOfGas {
 				err = vm.ErrOutOfGas
+			} else if ne, ok := e.(*nativeError); ok {
+				err = ne.error
 			} else {
 				panic(e)
 			}